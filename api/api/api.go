@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"net/http" // Provides HTTP client and server implementations.
 	"strconv"
+	"strings"
 
 	"github.com/blockfreight/go-bftx/api/graphqlObj"
 	apiHandler "github.com/blockfreight/go-bftx/api/handlers"
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Provides some useful functions to work with LevelDB.
+	"github.com/blockfreight/go-bftx/lib/app/bft"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/handler"
 )
@@ -57,6 +59,68 @@ var queryType = graphql.NewObject(
 					return apiHandler.QueryTransaction(bftxID)
 				},
 			},
+			"getLineage": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+					}
+
+					return apiHandler.GetLineage(bftxID)
+				},
+			},
+			"getParty": &graphql.Field{
+				Type: graphqlObj.PartyType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					partyID, isOK := p.Args["id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+					}
+
+					return apiHandler.GetParty(partyID)
+				},
+			},
+			"getTitle": &graphql.Field{
+				Type: graphqlObj.TitleType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+					}
+
+					return apiHandler.GetTitle(bftxID)
+				},
+			},
+			"searchTransactions": &graphql.Field{
+				Type: graphql.NewList(graphqlObj.TransactionType),
+				Args: searchArgs(),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					filters := make(map[string]string, len(p.Args))
+					for name, value := range p.Args {
+						if filter, isOK := value.(string); isOK {
+							filters[name] = filter
+						}
+					}
+
+					return apiHandler.SearchTransactions(filters)
+				},
+			},
 			"getInfo": &graphql.Field{
 				Type: graphqlObj.InfoType,
 				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
@@ -98,6 +162,199 @@ var mutationType = graphql.NewObject(
 					return apiHandler.ConstructBfTx(bftx)
 				},
 			},
+			"registerParty": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"Name": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"Role": &graphql.ArgumentConfig{
+						Description: "One of: " + strings.Join(bf_tx.Roles, ", ") + ".",
+						Type:        graphql.String,
+					},
+					"Principal": &graphql.ArgumentConfig{
+						Description: "Id of the registered party an agent acts for.",
+						Type:        graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					partyID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					name, _ := p.Args["Name"].(string)
+					role, _ := p.Args["Role"].(string)
+					principal, _ := p.Args["Principal"].(string)
+
+					return apiHandler.RegisterParty(partyID, name, role, principal)
+				},
+			},
+			"approveParty": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Party": &graphql.ArgumentConfig{
+						Description: "Id of the party that names the party of the node key as its principal.",
+						Type:        graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					partyID, isOK := p.Args["Party"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+
+					return apiHandler.ApproveParty(partyID)
+				},
+			},
+			"rotateKey": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Party": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"PubKey": &graphql.ArgumentConfig{
+						Description: "Hex encoded public key added to the party.",
+						Type:        graphql.String,
+					},
+					"Revoke": &graphql.ArgumentConfig{
+						Description: "Revoke the node key at the same time.",
+						Type:        graphql.Boolean,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					partyID, isOK := p.Args["Party"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					pubKey, _ := p.Args["PubKey"].(string)
+					revoke, _ := p.Args["Revoke"].(bool)
+
+					return apiHandler.RotateKey(partyID, pubKey, revoke)
+				},
+			},
+			"revokeKey": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Party": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"PubKey": &graphql.ArgumentConfig{
+						Description: "Hex encoded public key revoked.",
+						Type:        graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					partyID, isOK := p.Args["Party"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					pubKey, _ := p.Args["PubKey"].(string)
+
+					return apiHandler.RevokeKey(partyID, pubKey)
+				},
+			},
+			"transferBFTX": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"To": &graphql.ArgumentConfig{
+						Description: "Registered party the bill of lading is endorsed to.",
+						Type:        graphql.String,
+					},
+					"Bearer": &graphql.ArgumentConfig{
+						Description: "Hex encoded public key of the bearer, instead of an endorsee party.",
+						Type:        graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					to, _ := p.Args["To"].(string)
+					bearer, _ := p.Args["Bearer"].(string)
+
+					return apiHandler.TransferBfTx(bftxID, to, bearer)
+				},
+			},
+			"surrenderBFTX": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"To": &graphql.ArgumentConfig{
+						Description: "DeliverAgent party the bill of lading is surrendered to, instead of the carrier.",
+						Type:        graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					to, _ := p.Args["To"].(string)
+
+					return apiHandler.SurrenderBfTx(bftxID, to)
+				},
+			},
+			"cosignBFTX": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+
+					return apiHandler.CosignBfTx(bftxID)
+				},
+			},
+			"voidBFTX": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"Reason": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+					reason, _ := p.Args["Reason"].(string)
+
+					return apiHandler.VoidBfTx(bftxID, reason)
+				},
+			},
+			"releaseBFTX": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"Id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					bftxID, isOK := p.Args["Id"].(string)
+					if !isOK {
+						return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+					}
+
+					return apiHandler.ReleaseBfTx(bftxID)
+				},
+			},
 			"encryptBFTX": &graphql.Field{
 				Type: graphqlObj.TransactionType,
 				Args: graphql.FieldConfigArgument{
@@ -166,6 +423,24 @@ var mutationType = graphql.NewObject(
 	},
 )
 
+// searchArgs returns the searchTransactions arguments, one per searchable shipment field
+func searchArgs() graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		"Id": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"State": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+	}
+	for _, field := range bft.TagFields {
+		args[field] = &graphql.ArgumentConfig{
+			Type: graphql.String,
+		}
+	}
+	return args
+}
+
 //Start start the API
 func Start() error {
 	http.HandleFunc("/bftx-api", httpHandler(&schema))
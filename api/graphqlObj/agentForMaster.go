@@ -16,6 +16,13 @@ var AgentForMasterInput = graphql.NewInputObject(
 			"Sig": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
+			"Party": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"Details": &graphql.InputObjectFieldConfig{
+				Type:        TradePartyInput,
+				Description: "Address, contact and identifiers, without a Party.",
+			},
 		},
 	},
 )
@@ -34,6 +41,13 @@ var AgentForMaster = graphql.NewObject(
 			"Sig": &graphql.Field{
 				Type: graphql.String,
 			},
+			"Party": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Details": &graphql.Field{
+				Type:        TradePartyType,
+				Description: "Address, contact and identifiers, without a Party.",
+			},
 		},
 	},
 )
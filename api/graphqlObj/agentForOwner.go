@@ -16,9 +16,16 @@ var AgentForOwnerInput = graphql.NewInputObject(
 			"Sig": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
+			"Party": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
 			"ConditionsForCarriage": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
+			"Details": &graphql.InputObjectFieldConfig{
+				Type:        TradePartyInput,
+				Description: "Address, contact and identifiers, without a Party.",
+			},
 		},
 	},
 )
@@ -37,9 +44,16 @@ var AgentForOwner = graphql.NewObject(
 			"Sig": &graphql.Field{
 				Type: graphql.String,
 			},
+			"Party": &graphql.Field{
+				Type: graphql.String,
+			},
 			"ConditionsForCarriage": &graphql.Field{
 				Type: graphql.String,
 			},
+			"Details": &graphql.Field{
+				Type:        TradePartyType,
+				Description: "Address, contact and identifiers, without a Party.",
+			},
 		},
 	},
 )
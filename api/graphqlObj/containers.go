@@ -0,0 +1,165 @@
+package graphqlObj
+
+import (
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/graphql-go/graphql"
+)
+
+// CargoItem object for GraphQL integration
+var CargoItem = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "CargoItem",
+		Fields: graphql.Fields{
+			"DescOfGoods": &graphql.Field{
+				Type: graphql.String,
+			},
+			"HSCode": &graphql.Field{
+				Type:        graphql.String,
+				Description: hsCodeDescription,
+			},
+			"MarksAndNumbers": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Packages": &graphql.Field{
+				Type:    graphql.Int,
+				Resolve: resolvePackages,
+			},
+			"PackType": &graphql.Field{
+				Type: graphql.String,
+			},
+			"GrossWeight": &graphql.Field{
+				Type: QuantityType,
+			},
+			"Volume": &graphql.Field{
+				Type: QuantityType,
+			},
+		},
+	},
+)
+
+// CargoItemInput object for GraphQL integration
+var CargoItemInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "CargoItemInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"DescOfGoods": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"HSCode": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: hsCodeDescription,
+			},
+			"MarksAndNumbers": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"Packages": &graphql.InputObjectFieldConfig{
+				Type: graphql.Int,
+			},
+			"PackType": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"GrossWeight": &graphql.InputObjectFieldConfig{
+				Type: QuantityInput,
+			},
+			"Volume": &graphql.InputObjectFieldConfig{
+				Type: QuantityInput,
+			},
+		},
+	},
+)
+
+// Container object for GraphQL integration
+var Container = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Container",
+		Fields: graphql.Fields{
+			"Container": &graphql.Field{
+				Type:        graphql.String,
+				Description: containerDescription,
+			},
+			"ContainerSeal": &graphql.Field{
+				Type: graphql.String,
+			},
+			"ContainerType": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Packages": &graphql.Field{
+				Type:    graphql.Int,
+				Resolve: resolvePackages,
+			},
+			"GrossWeight": &graphql.Field{
+				Type: QuantityType,
+			},
+			"Volume": &graphql.Field{
+				Type: QuantityType,
+			},
+			"Items": &graphql.Field{
+				Type: graphql.NewList(CargoItem),
+			},
+		},
+	},
+)
+
+// ContainerInput object for GraphQL integration
+var ContainerInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "ContainerInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"Container": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: containerDescription,
+			},
+			"ContainerSeal": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"ContainerType": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"Packages": &graphql.InputObjectFieldConfig{
+				Type: graphql.Int,
+			},
+			"GrossWeight": &graphql.InputObjectFieldConfig{
+				Type: QuantityInput,
+			},
+			"Volume": &graphql.InputObjectFieldConfig{
+				Type: QuantityInput,
+			},
+			"Items": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewList(CargoItemInput),
+			},
+		},
+	},
+)
+
+// CargoTotals object for GraphQL integration
+var CargoTotals = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "CargoTotals",
+		Fields: graphql.Fields{
+			"Packages": &graphql.Field{
+				Type:    graphql.Int,
+				Resolve: resolvePackages,
+			},
+			"GrossWeight": &graphql.Field{
+				Type: QuantityType,
+			},
+			"Volume": &graphql.Field{
+				Type: QuantityType,
+			},
+		},
+	},
+)
+
+// containerDescription describes the container number fields for GraphQL integration
+const containerDescription = "ISO 6346 container number, e.g. CSQU3054383."
+
+// hsCodeDescription describes the HS code fields for GraphQL integration
+const hsCodeDescription = "Harmonized System code of the goods, 6 to 10 digits, e.g. 8471.30."
+
+// resolveTotals resolves the totals of the containers of the Properties
+func resolveTotals(p graphql.ResolveParams) (interface{}, error) {
+	if properties, isOK := p.Source.(bf_tx.Properties); isOK && len(properties.Containers) > 0 {
+		return bf_tx.ContainerTotals(properties.Containers)
+	}
+	return nil, nil
+}
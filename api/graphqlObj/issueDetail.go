@@ -11,7 +11,8 @@ var IssueDetailsInput = graphql.NewInputObject(
 				Type: graphql.String,
 			},
 			"DateOfIssue": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type:        graphql.String,
+				Description: dateDescription,
 			},
 		},
 	},
@@ -26,7 +27,8 @@ var IssueDetails = graphql.NewObject(
 				Type: graphql.String,
 			},
 			"DateOfIssue": &graphql.Field{
-				Type: graphql.String,
+				Type:        graphql.String,
+				Description: dateDescription,
 			},
 		},
 	},
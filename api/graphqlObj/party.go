@@ -0,0 +1,61 @@
+package graphqlObj
+
+import (
+	"encoding/hex"
+
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/graphql-go/graphql"
+)
+
+// PartyType object for GraphQL integration
+var PartyType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Party",
+		Fields: graphql.Fields{
+			"Id": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Name": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Role": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Principal": &graphql.Field{
+				Type: graphql.String,
+			},
+			"RoleApproved": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"PrincipalApproved": &graphql.Field{
+				Type: graphql.Boolean,
+			},
+			"PubKeys": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if party, isOK := p.Source.(bf_tx.Party); isOK {
+						return hexKeys(party.PubKeys), nil
+					}
+					return nil, nil
+				},
+			},
+			"Revoked": &graphql.Field{
+				Type: graphql.NewList(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if party, isOK := p.Source.(bf_tx.Party); isOK {
+						return hexKeys(party.Revoked), nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	},
+)
+
+func hexKeys(keys [][]byte) []string {
+	hexKeys := make([]string, len(keys))
+	for i, key := range keys {
+		hexKeys[i] = hex.EncodeToString(key)
+	}
+	return hexKeys
+}
@@ -8,7 +8,7 @@ var PropertiesType = graphql.NewObject(
 		Name: "Properties",
 		Fields: graphql.Fields{
 			"Shipper": &graphql.Field{
-				Type: graphql.String,
+				Type: TradePartyType,
 			},
 			"BolNum": &graphql.Field{
 				Type: graphql.String,
@@ -17,7 +17,7 @@ var PropertiesType = graphql.NewObject(
 				Type: graphql.String,
 			},
 			"Consignee": &graphql.Field{
-				Type: graphql.String,
+				Type: TradePartyType,
 			},
 			"Vessel": &graphql.Field{
 				Type: graphql.String,
@@ -32,7 +32,8 @@ var PropertiesType = graphql.NewObject(
 				Type: graphql.String,
 			},
 			"Packages": &graphql.Field{
-				Type: graphql.String,
+				Type:    graphql.Int,
+				Resolve: resolvePackages,
 			},
 			"PackType": &graphql.Field{
 				Type: graphql.String,
@@ -50,10 +51,10 @@ var PropertiesType = graphql.NewObject(
 				Type: graphql.String,
 			},
 			"DeliverAgent": &graphql.Field{
-				Type: graphql.String,
+				Type: TradePartyType,
 			},
 			"ReceiveAgent": &graphql.Field{
-				Type: graphql.String,
+				Type: TradePartyType,
 			},
 			"Container": &graphql.Field{
 				Type: graphql.String,
@@ -68,31 +69,32 @@ var PropertiesType = graphql.NewObject(
 				Type: graphql.String,
 			},
 			"Volume": &graphql.Field{
-				Type: graphql.String,
+				Type: QuantityType,
 			},
 			"UnitOfVolume": &graphql.Field{
 				Type: graphql.String,
 			},
 			"NotifyAddress": &graphql.Field{
-				Type: graphql.String,
+				Type: TradePartyType,
 			},
 			"DescOfGoods": &graphql.Field{
 				Type: graphql.String,
 			},
 			"GrossWeight": &graphql.Field{
-				Type: graphql.String,
+				Type: QuantityType,
 			},
 			"FreightPayableAmt": &graphql.Field{
-				Type: graphql.String,
+				Type: MoneyType,
 			},
 			"FreightAdvAmt": &graphql.Field{
-				Type: graphql.String,
+				Type: MoneyType,
 			},
 			"GeneralInstructions": &graphql.Field{
 				Type: graphql.String,
 			},
 			"DateShipped": &graphql.Field{
-				Type: graphql.String,
+				Type:        graphql.String,
+				Description: dateDescription,
 			},
 			"IssueDetails": &graphql.Field{
 				Type: IssueDetails,
@@ -109,6 +111,14 @@ var PropertiesType = graphql.NewObject(
 			"AgentForOwner": &graphql.Field{
 				Type: AgentForOwner,
 			},
+			"Containers": &graphql.Field{
+				Type: graphql.NewList(Container),
+			},
+			"Totals": &graphql.Field{
+				Type:        CargoTotals,
+				Description: "Packages, gross weight and volume of the Containers added up.",
+				Resolve:     resolveTotals,
+			},
 		},
 	},
 )
@@ -119,7 +129,7 @@ var PropertiesInput = graphql.NewInputObject(
 		Name: "Properties",
 		Fields: graphql.InputObjectConfigFieldMap{
 			"Shipper": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: TradePartyInput,
 			},
 			"BolNum": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
@@ -128,7 +138,7 @@ var PropertiesInput = graphql.NewInputObject(
 				Type: graphql.String,
 			},
 			"Consignee": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: TradePartyInput,
 			},
 			"Vessel": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
@@ -143,7 +153,7 @@ var PropertiesInput = graphql.NewInputObject(
 				Type: graphql.String,
 			},
 			"Packages": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: graphql.Int,
 			},
 			"PackType": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
@@ -161,10 +171,10 @@ var PropertiesInput = graphql.NewInputObject(
 				Type: graphql.String,
 			},
 			"DeliverAgent": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: TradePartyInput,
 			},
 			"ReceiveAgent": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: TradePartyInput,
 			},
 			"Container": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
@@ -179,31 +189,32 @@ var PropertiesInput = graphql.NewInputObject(
 				Type: graphql.String,
 			},
 			"Volume": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: QuantityInput,
 			},
 			"UnitOfVolume": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
 			"NotifyAddress": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: TradePartyInput,
 			},
 			"DescOfGoods": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
 			"GrossWeight": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: QuantityInput,
 			},
 			"FreightPayableAmt": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: MoneyInput,
 			},
 			"FreightAdvAmt": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type: MoneyInput,
 			},
 			"GeneralInstructions": &graphql.InputObjectFieldConfig{
 				Type: graphql.String,
 			},
 			"DateShipped": &graphql.InputObjectFieldConfig{
-				Type: graphql.String,
+				Type:        graphql.String,
+				Description: dateDescription,
 			},
 			"IssueDetails": &graphql.InputObjectFieldConfig{
 				Type: IssueDetailsInput,
@@ -220,6 +231,9 @@ var PropertiesInput = graphql.NewInputObject(
 			"AgentForOwner": &graphql.InputObjectFieldConfig{
 				Type: AgentForOwnerInput,
 			},
+			"Containers": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewList(ContainerInput),
+			},
 		},
 	},
 )
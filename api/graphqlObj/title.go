@@ -0,0 +1,113 @@
+package graphqlObj
+
+import (
+	"encoding/hex"
+
+	"github.com/blockfreight/go-bftx/lib/app/bft"
+	"github.com/graphql-go/graphql"
+)
+
+// HolderType object for GraphQL integration
+var HolderType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Holder",
+		Fields: graphql.Fields{
+			"Party": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Key": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if holder, isOK := p.Source.(bft.Holder); isOK && holder.Key != nil {
+						return hex.EncodeToString(holder.Key), nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	},
+)
+
+// EndorsementType object for GraphQL integration
+var EndorsementType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Endorsement",
+		Fields: graphql.Fields{
+			"From": &graphql.Field{
+				Type: HolderType,
+			},
+			"To": &graphql.Field{
+				Type: HolderType,
+			},
+			"Signer": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if endorsement, isOK := p.Source.(bft.Endorsement); isOK {
+						return hex.EncodeToString(endorsement.Signer), nil
+					}
+					return nil, nil
+				},
+			},
+			"Height": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"Time": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+// SettlementType object for GraphQL integration
+var SettlementType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Settlement",
+		Fields: graphql.Fields{
+			"Signer": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if settlement, isOK := p.Source.(*bft.Settlement); isOK {
+						return hex.EncodeToString(settlement.Signer), nil
+					}
+					return nil, nil
+				},
+			},
+			"Height": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"Time": &graphql.Field{
+				Type: graphql.Int,
+			},
+		},
+	},
+)
+
+// TitleType object for GraphQL integration
+var TitleType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Title",
+		Fields: graphql.Fields{
+			"Holder": &graphql.Field{
+				Type: HolderType,
+			},
+			"Endorsements": &graphql.Field{
+				Type: graphql.NewList(EndorsementType),
+			},
+			"Surrender": &graphql.Field{
+				Type: SettlementType,
+			},
+			"Release": &graphql.Field{
+				Type: SettlementType,
+			},
+			"Accomplished": &graphql.Field{
+				Type: graphql.Boolean,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if title, isOK := p.Source.(bft.Title); isOK {
+						return title.Accomplished(), nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	},
+)
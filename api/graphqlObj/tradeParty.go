@@ -0,0 +1,125 @@
+package graphqlObj
+
+import "github.com/graphql-go/graphql"
+
+// TradePartyType object for GraphQL integration
+var TradePartyType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "TradeParty",
+		Fields: graphql.Fields{
+			"Name": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Legal name, or the free text of a legacy party.",
+			},
+			"Address": &graphql.Field{
+				Type:        graphql.NewList(graphql.String),
+				Description: "Postal address lines, before the city.",
+			},
+			"City": &graphql.Field{
+				Type: graphql.String,
+			},
+			"PostalCode": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Country": &graphql.Field{
+				Type:        graphql.String,
+				Description: "ISO 3166-1 alpha-2 country code, e.g. SG.",
+			},
+			"Contact": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Name of the person to contact at the party.",
+			},
+			"Phone": &graphql.Field{
+				Type: graphql.String,
+			},
+			"Email": &graphql.Field{
+				Type: graphql.String,
+			},
+			"LEI": &graphql.Field{
+				Type:        graphql.String,
+				Description: "ISO 17442 Legal Entity Identifier, e.g. 5493001KJTIIGC8Y1R12.",
+			},
+			"GLN": &graphql.Field{
+				Type:        graphql.String,
+				Description: "GS1 Global Location Number, 13 digits.",
+			},
+			"EORI": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Economic Operators Registration and Identification number.",
+			},
+			"TaxID": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Tax or VAT number, as its country issues it.",
+			},
+			"ToOrder": &graphql.Field{
+				Type:        graphql.Boolean,
+				Description: "Consigned to the order of the party, or of the shipper if it has no Name. Only a Consignee can be.",
+			},
+			"Party": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Id of the registered party.",
+			},
+		},
+	},
+)
+
+// TradePartyInput object for GraphQL integration
+var TradePartyInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "TradePartyInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"Name": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Legal name, or the free text of a legacy party.",
+			},
+			"Address": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewList(graphql.String),
+				Description: "Postal address lines, before the city.",
+			},
+			"City": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"PostalCode": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"Country": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "ISO 3166-1 alpha-2 country code, e.g. SG.",
+			},
+			"Contact": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Name of the person to contact at the party.",
+			},
+			"Phone": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"Email": &graphql.InputObjectFieldConfig{
+				Type: graphql.String,
+			},
+			"LEI": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "ISO 17442 Legal Entity Identifier, e.g. 5493001KJTIIGC8Y1R12.",
+			},
+			"GLN": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "GS1 Global Location Number, 13 digits.",
+			},
+			"EORI": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Economic Operators Registration and Identification number.",
+			},
+			"TaxID": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Tax or VAT number, as its country issues it.",
+			},
+			"ToOrder": &graphql.InputObjectFieldConfig{
+				Type:        graphql.Boolean,
+				Description: "Consigned to the order of the party, or of the shipper if it has no Name. Only a Consignee can be.",
+			},
+			"Party": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Id of the registered party.",
+			},
+		},
+	},
+)
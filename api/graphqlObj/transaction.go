@@ -15,11 +15,8 @@ var TransactionType = graphql.NewObject(
 			"Type": &graphql.Field{
 				Type: graphql.String,
 			},
-			"Verified": &graphql.Field{
-				Type: graphql.Boolean,
-			},
-			"Transmitted": &graphql.Field{
-				Type: graphql.Boolean,
+			"Lifecycle": &graphql.Field{
+				Type: graphql.String,
 			},
 			"Properties": &graphql.Field{
 				Type: PropertiesType,
@@ -27,6 +24,12 @@ var TransactionType = graphql.NewObject(
 			"Private": &graphql.Field{
 				Type: graphql.String,
 			},
+			"BlockHeight": &graphql.Field{
+				Type: graphql.Int,
+			},
+			"BlockTime": &graphql.Field{
+				Type: graphql.Int,
+			},
 		},
 	},
 )
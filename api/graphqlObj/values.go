@@ -0,0 +1,109 @@
+package graphqlObj
+
+import (
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/graphql-go/graphql"
+)
+
+// QuantityType object for GraphQL integration
+var QuantityType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Quantity",
+		Fields: graphql.Fields{
+			"Value": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if quantity, isOK := p.Source.(bf_tx.Quantity); isOK {
+						return string(quantity.Value), nil
+					}
+					return nil, nil
+				},
+			},
+			"Unit": &graphql.Field{
+				Type:        graphql.String,
+				Description: "UN/ECE Recommendation 20 unit code, e.g. KGM or MTQ.",
+			},
+		},
+	},
+)
+
+// QuantityInput object for GraphQL integration
+var QuantityInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "QuantityInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"Value": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Decimal number, e.g. 15523.5.",
+			},
+			"Unit": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "UN/ECE Recommendation 20 unit code, e.g. KGM or MTQ.",
+			},
+		},
+	},
+)
+
+// MoneyType object for GraphQL integration
+var MoneyType = graphql.NewObject(
+	graphql.ObjectConfig{
+		Name: "Money",
+		Fields: graphql.Fields{
+			"Amount": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if money, isOK := p.Source.(bf_tx.Money); isOK {
+						return string(money.Amount), nil
+					}
+					return nil, nil
+				},
+			},
+			"Currency": &graphql.Field{
+				Type:        graphql.String,
+				Description: "ISO 4217 currency code, e.g. USD.",
+			},
+		},
+	},
+)
+
+// MoneyInput object for GraphQL integration
+var MoneyInput = graphql.NewInputObject(
+	graphql.InputObjectConfig{
+		Name: "MoneyInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"Amount": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "Decimal number, e.g. 354534.00.",
+			},
+			"Currency": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "ISO 4217 currency code, e.g. USD.",
+			},
+		},
+	},
+)
+
+// dateDescription describes the date fields for GraphQL integration
+const dateDescription = "ISO 8601 date, e.g. 2016-11-28."
+
+// resolvePackages resolves the package count of the Properties, a Container, a CargoItem or the CargoTotals, null
+// for a count that is not a number
+func resolvePackages(p graphql.ResolveParams) (interface{}, error) {
+	var count bf_tx.Count
+	switch source := p.Source.(type) {
+	case bf_tx.Properties:
+		count = source.Packages
+	case bf_tx.Container:
+		count = source.Packages
+	case bf_tx.CargoItem:
+		count = source.Packages
+	case bf_tx.CargoTotals:
+		count = source.Packages
+	default:
+		return nil, nil
+	}
+	if value, ok := count.Int(); ok {
+		return int(value), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
+	abciTypes "github.com/tendermint/abci/types"
+)
+
+// GetParty function to get a registered party via API
+func GetParty(idParty string) (interface{}, error) {
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/party/" + idParty})
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if resQuery.Value == nil {
+		return nil, errors.New(strconv.Itoa(http.StatusNotFound))
+	}
+
+	var party bf_tx.Party
+	if err := json.Unmarshal(resQuery.Value, &party); err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return party, nil
+}
+
+// RegisterParty function to register the organization of the node key as a party via API, an agent with the party it acts for
+func RegisterParty(idParty string, name string, role string, principal string) (interface{}, error) {
+	privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+
+	party := bf_tx.Party{Id: idParty, Name: name, Role: role, PubKeys: [][]byte{privKey.PubKey().Bytes()}, Principal: principal}
+	return broadcastOperation(bf_tx.OpRegisterParty, party)
+}
+
+// ApproveParty function to approve, via API, a party that names the party of the node key as its principal
+func ApproveParty(idParty string) (interface{}, error) {
+	return broadcastOperation(bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: idParty})
+}
+
+// RotateKey function to add a new key to the party of the node key via API, and optionally revoke the node key
+func RotateKey(idParty string, pubKey string, revoke bool) (interface{}, error) {
+	newKey, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+	}
+
+	rotation := bf_tx.KeyRotation{Party: idParty, PubKey: newKey}
+	if revoke {
+		privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+		if err != nil {
+			return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+		}
+		rotation.Revoke = privKey.PubKey().Bytes()
+	}
+	return broadcastOperation(bf_tx.OpRotateKey, rotation)
+}
+
+// RevokeKey function to revoke a key of the party of the node key via API
+func RevokeKey(idParty string, pubKey string) (interface{}, error) {
+	revoked, err := hex.DecodeString(pubKey)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+	}
+
+	return broadcastOperation(bf_tx.OpRevokeKey, bf_tx.KeyRevocation{Party: idParty, PubKey: revoked})
+}
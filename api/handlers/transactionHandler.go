@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,7 +13,9 @@ import (
 
 	"net/http" // Provides HTTP client and server implementations.
 
+	"github.com/blockfreight/go-bftx/lib/app/authz"
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/app/bft"
 	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
 	"github.com/blockfreight/go-bftx/lib/pkg/leveldb"
 	"github.com/blockfreight/go-bftx/lib/pkg/saberservice"
@@ -24,6 +27,7 @@ import (
 	// Tendermint Core
 	// ===============
 	abciTypes "github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
 )
 
 //TendermintClient object to call function from tendermint
@@ -79,13 +83,16 @@ func SignBfTx(idBftx string) (interface{}, error) {
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	if transaction.Verified {
-		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
+	// Sign BF_TX with the key of the node party
+	privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
-
-	// Sign BF_TX
-	transaction, err = crypto.SignBFTX(transaction)
+	transaction, err = crypto.SignBFTX(transaction, privKey)
 	if err != nil {
+		if _, isOK := err.(bf_tx.TransitionError); isOK {
+			return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
+		}
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
@@ -121,7 +128,7 @@ func EncryptBfTx(idBftx string) (interface{}, error) {
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	if transaction.Verified {
+	if bf_tx.State(transaction) != bf_tx.StateDraft {
 		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
 	}
 
@@ -163,7 +170,7 @@ func DecryptBfTx(idBftx string) (interface{}, error) {
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	if transaction.Verified {
+	if bf_tx.State(transaction) != bf_tx.StateDraft {
 		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
 	}
 
@@ -212,16 +219,12 @@ func BroadcastBfTx(idBftx string) (interface{}, error) {
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	if !transaction.Verified {
-		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
-	}
-	if transaction.Transmitted {
+	// Only a signed BF_TX can be submitted, and only once
+	transaction, err = bf_tx.Transition(transaction, bf_tx.StateSubmitted)
+	if err != nil {
 		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
 	}
 
-	// Change the boolean valud for Transmitted attribute
-	transaction.Transmitted = true
-
 	// Get the BF_TX content in string format
 	content, err := bf_tx.BFTXContent(transaction)
 	if err != nil {
@@ -233,14 +236,37 @@ func BroadcastBfTx(idBftx string) (interface{}, error) {
 		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	var tx tmTypes.Tx
-	tx = []byte(content)
+	// Wrap the BF_TX in an issue, or amend, transaction envelope, signed with the node key file if there is one
+	env, err := bf_tx.NewBFTXEnvelope(transaction)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if keyPath := os.Getenv("BFTX_KEY"); keyPath != "" {
+		privKey, err := crypto.LoadKeyFile(keyPath)
+		if err != nil {
+			return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+		}
+		if err := checkRules(env.Type, privKey.PubKey().Bytes()); err != nil {
+			return nil, err
+		}
+		if env, err = signEnvelope(env, privKey); err != nil {
+			return nil, err
+		}
+	}
+	tx, err := json.Marshal(env)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
 
-	_, rpcErr := rpcClient.BroadcastTxSync(tx)
+	resp, rpcErr := rpcClient.BroadcastTxSync(tmTypes.Tx(tx))
 	if rpcErr != nil {
 		fmt.Printf("%+v\n", rpcErr)
 		return nil, rpcErr
 	}
+	if resp.Code != abciTypes.CodeTypeOK {
+		fmt.Printf("BF_TX rejected by the network (code %d): %s\n", resp.Code, resp.Log)
+		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
+	}
 
 	defer rpcClient.Stop()
 
@@ -295,30 +321,260 @@ func GetTransaction(idBftx string) (interface{}, error) {
 	return transaction, nil
 }
 
-// QueryTransaction function to query a transaction, from the network, by id via API
+// QueryTransaction function to query a transaction, from the network, by id via API. The record is read from the
+// state of the node, as the transactions of the chain are envelopes of the operations on the BF_TX.
 func QueryTransaction(idBftx string) (interface{}, error) {
+	return queryRecord(idBftx)
+}
+
+// queryRecord reads the committed record of a BFTX, in its current state, from the state of the node
+func queryRecord(idBftx string) (bf_tx.BF_TX, error) {
+	var transaction bf_tx.BF_TX
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/bftx/" + idBftx})
+	if err != nil {
+		return transaction, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if resQuery.Value == nil {
+		return transaction, errors.New(strconv.Itoa(http.StatusNotFound))
+	}
+	if err := json.Unmarshal(resQuery.Value, &transaction); err != nil {
+		return transaction, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return transaction, nil
+}
+
+// GetLineage function to get the amendment lineage of a BFTX via API, from the original BFTX to its latest amendment
+func GetLineage(idBftx string) (interface{}, error) {
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/lineage/" + idBftx})
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if resQuery.Value == nil {
+		return nil, errors.New(strconv.Itoa(http.StatusNotFound))
+	}
+
+	var lineage []string
+	if err := json.Unmarshal(resQuery.Value, &lineage); err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return lineage, nil
+}
+
+// GetTitle function to get the holder and the endorsements of a bill of lading via API, by the Id of any BFTX of its lineage
+func GetTitle(idBftx string) (interface{}, error) {
+	lineage, err := GetLineage(idBftx)
+	if err != nil {
+		return nil, err
+	}
+
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/title/" + lineage.([]string)[0]})
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if resQuery.Value == nil {
+		return nil, errors.New(strconv.Itoa(http.StatusNotFound))
+	}
+
+	var title bft.Title
+	if err := json.Unmarshal(resQuery.Value, &title); err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return title, nil
+}
+
+// TransferBfTx function to endorse a bill of lading held by the node key over to a registered party, or to a bearer key, via API
+func TransferBfTx(idBftx string, to string, bearer string) (interface{}, error) {
+	transfer := bf_tx.Transfer{Id: idBftx, To: to}
+	if bearer != "" {
+		bearerKey, err := hex.DecodeString(bearer)
+		if err != nil {
+			return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+		}
+		transfer.Bearer = bearerKey
+	}
+
+	return broadcastOperation(bf_tx.OpTransfer, transfer)
+}
+
+// SurrenderBfTx function to surrender a bill of lading held by the node key to its carrier, or to its DeliverAgent party, via API
+func SurrenderBfTx(idBftx string, to string) (interface{}, error) {
+	return broadcastOperation(bf_tx.OpSurrender, bf_tx.Surrender{Id: idBftx, To: to})
+}
+
+// ReleaseBfTx function to confirm the release of the cargo of a surrendered bill of lading via API
+func ReleaseBfTx(idBftx string) (interface{}, error) {
+	return broadcastOperation(bf_tx.OpRelease, bf_tx.Release{Id: idBftx})
+}
+
+// VoidBfTx function to void a BFTX via API: a draft or signed one locally, a committed one its issuer still holds on the network
+func VoidBfTx(idBftx string, reason string) (interface{}, error) {
+	transaction, err := leveldb.GetBfTx(idBftx)
+	if err == nil {
+		if state := bf_tx.State(transaction); state == bf_tx.StateDraft || state == bf_tx.StateSigned {
+			if transaction, err = bf_tx.Transition(transaction, bf_tx.StateVoid); err != nil {
+				return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
+			}
+			content, err := bf_tx.BFTXContent(transaction)
+			if err != nil {
+				return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+			}
+			if err = leveldb.RecordOnDB(transaction.Id, content); err != nil {
+				return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+			}
+			return transaction.Id, nil
+		}
+	}
+
+	return broadcastOperation(bf_tx.OpVoid, bf_tx.Void{Id: idBftx, Reason: reason})
+}
+
+// CosignBfTx function to co-sign a committed BFTX that names the party of the node key as its master or one of its agents via API
+func CosignBfTx(idBftx string) (interface{}, error) {
+	transaction, err := queryRecord(idBftx)
+	if err != nil {
+		return nil, err
+	}
+
+	privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	signature, err := crypto.CosignBFTX(transaction, privKey)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return broadcastOperation(bf_tx.OpCosign, bf_tx.Cosign{Id: idBftx, Signature: signature})
+}
+
+// broadcastOperation signs an operation with the node key file in BFTX_KEY and broadcasts it, returning the transaction hash
+func broadcastOperation(opType bf_tx.OpType, payload interface{}) (interface{}, error) {
+	env, err := bf_tx.NewEnvelope(opType, payload)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if err := checkRules(opType, privKey.PubKey().Bytes()); err != nil {
+		return nil, err
+	}
+	if env, err = signEnvelope(env, privKey); err != nil {
+		return nil, err
+	}
+	tx, err := json.Marshal(env)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+
 	rpcClient := rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
-	err := rpcClient.Start()
+	err = rpcClient.Start()
 	if err != nil {
 		fmt.Println("Error when initializing rpcClient")
 		log.Fatal(err.Error())
 	}
 	defer rpcClient.Stop()
-	query := "bftx.id='" + idBftx + "'"
-	resQuery, err := rpcClient.TxSearch(query, true)
+
+	resp, rpcErr := rpcClient.BroadcastTxSync(tmTypes.Tx(tx))
+	if rpcErr != nil {
+		fmt.Printf("%+v\n", rpcErr)
+		return nil, rpcErr
+	}
+	if resp.Code != abciTypes.CodeTypeOK {
+		fmt.Printf("Operation %s rejected by the network (code %d): %s\n", opType, resp.Code, resp.Log)
+		return nil, errors.New(strconv.Itoa(http.StatusNotAcceptable))
+	}
+
+	return hex.EncodeToString(resp.Hash), nil
+}
+
+// signEnvelope signs an envelope with a key, with the next sequence of the key on the network
+func signEnvelope(env bf_tx.Envelope, privKey tmcrypto.PrivKey) (bf_tx.Envelope, error) {
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/chain_id"})
 	if err != nil {
-		return nil, err
+		return env, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	env.ChainID = string(resQuery.Value)
+	resQuery, err = TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/nonce", Data: privKey.PubKey().Bytes()})
+	if err != nil {
+		return env, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if env.Sequence, err = bft.NextSequence(resQuery); err != nil {
+		return env, errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	return crypto.SignEnvelope(env, privKey), nil
+}
+
+// checkRules fails fast, before broadcasting, an operation the role rules of the network never allow to the party of a key
+func checkRules(opType bf_tx.OpType, pubKey []byte) error {
+	resQuery, err := TendermintClient.QuerySync(abciTypes.RequestQuery{Path: "/params/Rules"})
+	if err != nil {
+		return errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	var rules authz.Rules
+	if resQuery.Value == nil {
+		rules = authz.DefaultRules()
+	} else if err := json.Unmarshal(resQuery.Value, &rules); err != nil {
+		return errors.New(strconv.Itoa(http.StatusInternalServerError))
 	}
 
-	if len(resQuery) > 0 {
-		var transaction bf_tx.BF_TX
-		err := json.Unmarshal(resQuery[0].Tx, &transaction)
+	// The role of an unregistered key is empty
+	var role string
+	resQuery, err = TendermintClient.QuerySync(abciTypes.RequestQuery{Data: append([]byte(bft.PartyKeyPrefix), pubKey...)})
+	if err != nil {
+		return errors.New(strconv.Itoa(http.StatusInternalServerError))
+	}
+	if resQuery.Value != nil {
+		party, err := GetParty(string(resQuery.Value))
 		if err != nil {
-			return nil, err
+			return err
 		}
+		role = party.(bf_tx.Party).Role
+	}
 
-		return transaction, nil
+	if err := rules.AuthorizeRole(opType, role); err != nil {
+		fmt.Println(err.Error())
+		return errors.New(strconv.Itoa(http.StatusForbidden))
+	}
+	return nil
+}
+
+// SearchTransactions function to search the committed BFTX by id, state and shipment fields via API. Any operation
+// on a BFTX can match, so the BFTX they operate on are read, once each and in their current state, from the state
+// of the node.
+func SearchTransactions(filters map[string]string) (interface{}, error) {
+	query, err := bft.SearchQuery(filters)
+	if err != nil {
+		return nil, errors.New(strconv.Itoa(http.StatusBadRequest))
+	}
+
+	rpcClient := rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
+	err = rpcClient.Start()
+	if err != nil {
+		fmt.Println("Error when initializing rpcClient")
+		log.Fatal(err.Error())
+	}
+	defer rpcClient.Stop()
+	resQuery, err := rpcClient.TxSearch(query, true)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]bf_tx.BF_TX, 0, len(resQuery))
+	found := make(map[string]bool)
+	for _, resTx := range resQuery {
+		for _, tag := range resTx.TxResult.Tags {
+			if tag.Key != bft.TagID || found[tag.ValueString] {
+				continue
+			}
+			found[tag.ValueString] = true
+			transaction, err := queryRecord(tag.ValueString)
+			if err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, transaction)
+		}
 	}
 
-	return nil, errors.New(strconv.Itoa(http.StatusNotFound))
+	return transactions, nil
 }
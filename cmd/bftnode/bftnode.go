@@ -59,7 +59,6 @@ import (
 	// ===============
 	"github.com/tendermint/abci/client"
 	"github.com/tendermint/abci/server"
-	"github.com/tendermint/abci/types"
 	tendermint "github.com/tendermint/go-common"
 	// ======================
 	// Blockfreight™ packages
@@ -68,6 +67,7 @@ import (
 	"github.com/blockfreight/go-bftx/api/api"
 	"github.com/blockfreight/go-bftx/api/handlers"
 	"github.com/blockfreight/go-bftx/lib/app/bft" // Implements the main functions to work with the Blockfreight™ Network.
+	"github.com/blockfreight/go-bftx/lib/pkg/saberservice"
 )
 
 var client abcicli.Client
@@ -78,12 +78,38 @@ func main() {
 	// Parameters
 	addrPtr := flag.String("addr", "tcp://0.0.0.0:46658", "Listen address")
 	abciPtr := flag.String("bft", "socket", "socket | grpc")
-	// persistencePtr := flag.String("persist", "", "directory to use for a database")
+	persistencePtr := flag.String("persist", "", "directory to use for a database")
+	encryptionConfigPtr := flag.String("encryption-config", "", "encryption config file, its encrypted fields are not emitted as tags")
+	keepRecentPtr := flag.Int64("keep-recent", 0, "number of recent block states to keep for historical queries, 0 keeps all")
+	genesisPtr := flag.String("genesis", "", "genesis file whose app_state is loaded into the state of a new chain")
 	flag.Parse()
 
 	// Create the application - in memory or persisted to disk
-	var app types.Application
-	app = bft.NewBftApplication() //if *persistencePtr != "" => NewPersistentBftApplication(*persistencePtr)
+	var app *bft.BftApplication
+	if *persistencePtr != "" {
+		persistentApp, err := bft.NewPersistentBftApplication(*persistencePtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		app = persistentApp
+	} else {
+		app = bft.NewBftApplication()
+	}
+	app.SetKeepRecent(*keepRecentPtr)
+	if *encryptionConfigPtr != "" {
+		encryptedFields, err := saberservice.EncryptedFields(*encryptionConfigPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		app.SetEncryptedFields(encryptedFields)
+	}
+	if *genesisPtr != "" {
+		genesis, err := bft.LoadGenesisState(*genesisPtr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		app.SetGenesisState(genesis)
+	}
 
 	// Start the listener
 	srv, err := server.NewServer(*addrPtr, *abciPtr, app)
@@ -122,6 +148,7 @@ func main() {
 		// Cleanup
 		fmt.Println("Stopping service")
 		srv.Stop()
+		app.Close()
 	})
 
 }
@@ -51,17 +51,21 @@ import (
 	// =======================
 	"bufio" // Implements buffered I/O.
 	// Package csv reads and writes comma-separated values (CSV) files.
-	"encoding/hex" // Implements hexadecimal encoding and decoding.
-	"errors"       // Implements functions to manipulate errors.
-	"fmt"          // Implements formatted I/O with functions analogous to C's printf and scanf.
-	"io"           // Provides basic interfaces to I/O primitives.
-	"log"          // Implements a simple logging package.
-	"os"           // Provides a platform-independent interface to operating system functionality.
-	"reflect"      // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
-	"runtime"      // Contains operations that interact with Go's runtime system.
-	"strconv"      // Implements conversions to and from string representations of basic data types.
-	"strings"      // Implements simple functions to manipulate UTF-8 encoded strings.
-	"time"         // Provides functionality for measuring and displaying time.
+	"encoding/hex"  // Implements hexadecimal encoding and decoding.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"fmt"           // Implements formatted I/O with functions analogous to C's printf and scanf.
+	"io"            // Provides basic interfaces to I/O primitives.
+	"io/ioutil"     // Implements some I/O utility functions.
+	"log"           // Implements a simple logging package.
+	"os"            // Provides a platform-independent interface to operating system functionality.
+	"path/filepath" // Implements utility routines for manipulating filename paths.
+	"reflect"       // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"runtime"       // Contains operations that interact with Go's runtime system.
+	"sort"          // Provides primitives for sorting slices and user-defined collections.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+	"time"          // Provides functionality for measuring and displaying time.
 
 	// ====================
 	// Third-party packages
@@ -74,6 +78,7 @@ import (
 	// ===============
 	"github.com/tendermint/abci/client"
 	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
 	rpc "github.com/tendermint/tendermint/rpc/client"
 	tmTypes "github.com/tendermint/tendermint/types"
 
@@ -84,6 +89,7 @@ import (
 	"github.com/blockfreight/go-bftx/api/handlers"
 	"github.com/blockfreight/go-bftx/build/package/version" // Defines the current version of the project.
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx"         // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+	"github.com/blockfreight/go-bftx/lib/app/bft"           // Implements the main functions to work with the Blockfreight™ Network.
 	"github.com/blockfreight/go-bftx/lib/app/validator"     // Provides functions to assure the input JSON is correct.
 	"github.com/blockfreight/go-bftx/lib/pkg/crypto"        // Provides useful functions to sign BF_TX.
 	"github.com/blockfreight/go-bftx/lib/pkg/leveldb"       // Provides some useful functions to work with LevelDB.
@@ -146,6 +152,11 @@ func main() {
 			Value: "./examples/",
 			Usage: "define the source path where the json is",
 		},
+		cli.StringFlag{
+			Name:  "key",
+			Value: filepath.Join(os.Getenv("HOME"), ".bftx", "priv_key"),
+			Usage: "private key file the transactions are signed with",
+		},
 	}
 	app.Commands = []cli.Command{
 		{
@@ -218,6 +229,34 @@ func main() {
 				return cmdBroadcastBfTx(c)
 			},
 		},
+		{
+			Name:  "keygen",
+			Usage: "Generate the private key file the transactions are signed with (Parameters: none)",
+			Action: func(c *cli.Context) error {
+				return cmdKeygen(c)
+			},
+		},
+		{
+			Name:  "validator",
+			Usage: "Approve adding, updating or removing (power 0) a validator with this node's validator key, applied once the validators that approve it hold the VoteThreshold of the power (Parameters: priv_validator.json filepath, validator public key hex, power)",
+			Action: func(c *cli.Context) error {
+				return cmdValidatorSetChange(c)
+			},
+		},
+		{
+			Name:  "propose",
+			Usage: "Propose new values of network parameters, signed by this node's validator key (Parameters: priv_validator.json filepath, JSON object of the new values by parameter name, optional description)",
+			Action: func(c *cli.Context) error {
+				return cmdProposeParams(c)
+			},
+		},
+		{
+			Name:  "vote",
+			Usage: "Vote for an open parameter proposal, signed by this node's validator key (Parameters: priv_validator.json filepath, proposal id)",
+			Action: func(c *cli.Context) error {
+				return cmdVote(c)
+			},
+		},
 		{
 			Name:  "commit",
 			Usage: "Commit the application state and return the Merkle root hash (Parameters: none)",
@@ -227,11 +266,29 @@ func main() {
 		},
 		{
 			Name:  "query",
-			Usage: "Query application state",
+			Usage: "Search the committed [BF_TX] by id and/or shipment fields (Parameters: BF_TX id, optional when a filter is given)",
+			Flags: searchFlags(),
 			Action: func(c *cli.Context) error {
 				return cmdQuery(c)
 			},
 		},
+		{
+			Name:  "lookup",
+			Usage: "Look up application state by query path, e.g. /bftx/<id>, /bol/<BolNum>, /container/<Container>, /shipper/<Shipper> or /amendments/<id> (Parameters: path)",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "prove",
+					Usage: "include the Merkle proof of the value",
+				},
+				cli.IntFlag{
+					Name:  "height",
+					Usage: "look up the state as it was at this block height, 0 for the latest",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return cmdLookup(c)
+			},
+		},
 		{
 			Name:  "get",
 			Usage: "Retrieve a [BF_TX] by its ID (Parameters: BF_TX id)",
@@ -241,11 +298,93 @@ func main() {
 		},
 		{
 			Name:  "append",
-			Usage: "Append a new BF_TX to an existing BF_TX (Parameters: JSON Filepath, BF_TX id)",
+			Usage: "Amend a committed BF_TX with a new BF_TX, signed and broadcast to the network (Parameters: JSON Filepath, id of the latest BF_TX of the lineage)",
 			Action: func(c *cli.Context) error {
 				return cmdAppendBfTx(c)
 			},
 		},
+		{
+			Name:  "register",
+			Usage: "Register your organization as a party of the network, with the key file as its key (Parameters: party id, name, role: " + strings.Join(bf_tx.Roles, ", ") + ", and for an agent the party id of its principal)",
+			Action: func(c *cli.Context) error {
+				return cmdRegisterParty(c)
+			},
+		},
+		{
+			Name:  "rotate-key",
+			Usage: "Add a new key to your party (Parameters: party id, hex encoded new public key)",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "revoke",
+					Usage: "revoke the key file key at the same time",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return cmdRotateKey(c)
+			},
+		},
+		{
+			Name:  "revoke-key",
+			Usage: "Revoke a key of your party, e.g. a compromised one (Parameters: party id, hex encoded public key)",
+			Action: func(c *cli.Context) error {
+				return cmdRevokeKey(c)
+			},
+		},
+		{
+			Name:  "approve",
+			Usage: "Approve a party that names your party as its principal, or with this node's validator key, a party with a privileged role (Parameters: party id, optional priv_validator.json filepath)",
+			Action: func(c *cli.Context) error {
+				return cmdApproveParty(c)
+			},
+		},
+		{
+			Name:  "transfer",
+			Usage: "Endorse a bill of lading you hold over to a registered party, or in blank to a bearer key (Parameters: BF_TX id, endorsee party id)",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "bearer",
+					Usage: "hex encoded public key of the bearer, instead of an endorsee party",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return cmdTransferBfTx(c)
+			},
+		},
+		{
+			Name:  "surrender",
+			Usage: "Surrender a bill of lading you hold at destination, to its carrier or DeliverAgent (Parameters: BF_TX id, optional DeliverAgent party id)",
+			Action: func(c *cli.Context) error {
+				return cmdSurrenderBfTx(c)
+			},
+		},
+		{
+			Name:  "release",
+			Usage: "Confirm the release of the cargo of a surrendered bill of lading (Parameters: BF_TX id)",
+			Action: func(c *cli.Context) error {
+				return cmdReleaseBfTx(c)
+			},
+		},
+		{
+			Name:  "export",
+			Usage: "Export the state of the node as the genesis file of a new chain (Parameters: chain id, optional genesis file, printed without it)",
+			Action: func(c *cli.Context) error {
+				return cmdExportGenesis(c)
+			},
+		},
+		{
+			Name:  "cosign",
+			Usage: "Co-sign a BF_TX that names your party as its master or one of its agents (Parameters: BF_TX id)",
+			Action: func(c *cli.Context) error {
+				return cmdCosignBfTx(c)
+			},
+		},
+		{
+			Name:  "void",
+			Usage: "Void a BF_TX: a draft or signed one locally, a committed one its issuer still holds on the network (Parameters: BF_TX id, reason)",
+			Action: func(c *cli.Context) error {
+				return cmdVoidBfTx(c)
+			},
+		},
 		{
 			Name:  "state",
 			Usage: "Get the current state of a determined BF_TX (Parameters: BF_TX id)",
@@ -260,6 +399,13 @@ func main() {
 				return cmdTotalBfTx(c)
 			},
 		},
+		{
+			Name:  "migrate",
+			Usage: "Migrate every BF_TX in DB to the current schema version in place, and report the result (Parameters: none)",
+			Action: func(c *cli.Context) error {
+				return cmdMigrateBfTx(c)
+			},
+		},
 		{
 			Name:  "echo",
 			Usage: "Print clearly a BF_TX (Parameters: BF_TX id)",
@@ -605,14 +751,7 @@ func cmdVerifyBfTx(c *cli.Context) error {
 		return err
 	}
 
-	// Get the BF_TX old_content in string format
-	jcontent, err := bf_tx.BFTXContent(jbftx)
-	if err != nil {
-		simpleLogger(cmdVerifyBfTx, err)
-		return err
-	}
-
-	result, err := leveldb.Verify(jcontent)
+	result, err := leveldb.Verify(jbftx)
 	if err != nil {
 		simpleLogger(cmdVerifyBfTx, err)
 		return err
@@ -723,12 +862,13 @@ func cmdSignBfTx(c *cli.Context) error {
 		transLogger(cmdSignBfTx, err, bftx)
 		return err
 	}
-	if bftx.Verified {
-		return errors.New("BF_TX already signed.")
+	// Sign BF_TX with the key of your party
+	privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+	if err != nil {
+		transLogger(cmdSignBfTx, err, bftx)
+		return err
 	}
-
-	// Sign BF_TX
-	bftx, err = crypto.SignBFTX(bftx)
+	bftx, err = crypto.SignBFTX(bftx, privKey)
 	if err != nil {
 		transLogger(cmdSignBfTx, err, bftx)
 		return err
@@ -768,16 +908,12 @@ func cmdBroadcastBfTx(c *cli.Context) error {
 		transLogger(cmdBroadcastBfTx, err, bftx)
 		return err
 	}
-	if !bftx.Verified {
-		return errors.New("BF_TX is not signed yet.")
-	}
-	if bftx.Transmitted {
-		return errors.New("BF_TX already transmitted.")
+	// Only a signed BF_TX can be submitted, and only once
+	bftx, err = bf_tx.Transition(bftx, bf_tx.StateSubmitted)
+	if err != nil {
+		return err
 	}
 
-	// Change the boolean valud for Transmitted attribute
-	bftx.Transmitted = true
-
 	// Get the BF_TX content in string format
 	content, err := bf_tx.BFTXContent(bftx)
 	if err != nil {
@@ -807,10 +943,24 @@ func cmdBroadcastBfTx(c *cli.Context) error {
 
 	defer rpcClient.Stop()
 
-	var tx tmTypes.Tx
-	tx = []byte(content)
+	// Wrap the BF_TX in an issue, or amend, transaction envelope signed by the sender
+	env, err := bf_tx.NewBFTXEnvelope(bftx)
+	if err != nil {
+		transLogger(cmdBroadcastBfTx, err, bftx)
+		return err
+	}
+	env, err = signEnvelope(c, env, env.Type == bf_tx.OpAmend)
+	if err != nil {
+		transLogger(cmdBroadcastBfTx, err, bftx)
+		return err
+	}
+	tx, err := json.Marshal(env)
+	if err != nil {
+		transLogger(cmdBroadcastBfTx, err, bftx)
+		return err
+	}
 
-	resp, rpcErr := rpcClient.BroadcastTxSync(tx)
+	resp, rpcErr := rpcClient.BroadcastTxSync(tmTypes.Tx(tx))
 	if rpcErr != nil {
 		fmt.Printf("%+v\n", rpcErr)
 		transLogger(cmdBroadcastBfTx, rpcErr, bftx)
@@ -819,12 +969,88 @@ func cmdBroadcastBfTx(c *cli.Context) error {
 
 	printResponse(c, response{
 		Data: resp.Hash,
+		Code: resp.Code,
 		Log:  resp.Log,
 	})
 
 	return nil
 }
 
+// Add, update or remove a validator of the network
+func cmdValidatorSetChange(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 3 {
+		return errors.New("Command validator takes 3 arguments")
+	}
+
+	pubKey, err := hex.DecodeString(args[1])
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+	power, err := strconv.ParseInt(args[2], 10, 64)
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+
+	// The change is approved for the chain and the validator set change sequence of the node
+	resQuery, err := client.QuerySync(types.RequestQuery{Path: "/chain_id"})
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+	chainID := string(resQuery.Value)
+	resQuery, err = client.QuerySync(types.RequestQuery{Path: "/validators/sequence"})
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+	sequence, err := bft.NextSequence(resQuery)
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+
+	// Sign the change with the key of the validator running this node
+	privValidator := tmTypes.LoadPrivValidatorFS(args[0])
+	change := bft.ValidatorSetChange{
+		ChainID:  chainID,
+		Sequence: sequence,
+		PubKey:   pubKey,
+		Power:    power,
+		Signer:   privValidator.PubKey.Bytes(),
+	}
+	change.Signature = privValidator.PrivKey.Sign(change.SignBytes()).Bytes()
+
+	tx, err := bft.MakeValSetChangeTx(change)
+	if err != nil {
+		simpleLogger(cmdValidatorSetChange, err)
+		return err
+	}
+
+	rpcClient = rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
+	err = rpcClient.Start()
+	if err != nil {
+		fmt.Println("Error when initializing rpcClient")
+		log.Fatal(err.Error())
+	}
+	defer rpcClient.Stop()
+
+	resp, rpcErr := rpcClient.BroadcastTxSync(tx)
+	if rpcErr != nil {
+		simpleLogger(cmdValidatorSetChange, rpcErr)
+		return rpcErr
+	}
+
+	printResponse(c, response{
+		Data: resp.Hash,
+		Code: resp.Code,
+		Log:  resp.Log,
+	})
+	return nil
+}
+
 // Get application Merkle root hash
 func cmdCommit(c *cli.Context) error {
 	result, err := client.CommitSync()
@@ -844,20 +1070,32 @@ func cmdCommit(c *cli.Context) error {
 // TODO JCNM: Make request and response support all fields.
 func cmdQuery(c *cli.Context) error {
 	args := c.Args()
-	if len(args) != 1 {
-		return errors.New("Command query takes 1 argument")
+	if len(args) > 1 {
+		return errors.New("Command query takes at most 1 argument")
+	}
+
+	filters := map[string]string{
+		"State": c.String("state"),
+	}
+	if len(args) == 1 {
+		filters["Id"] = args[0]
+	}
+	for _, field := range bft.TagFields {
+		filters[field] = c.String(strings.ToLower(field))
+	}
+	query, err := bft.SearchQuery(filters)
+	if err != nil {
+		return err
 	}
 
 	rpcClient = rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
-	err := rpcClient.Start()
+	err = rpcClient.Start()
 	if err != nil {
 		fmt.Println("Error when initializing rpcClient")
 		log.Fatal(err.Error())
 		simpleLogger(cmdQuery, err)
 	}
 
-	query := "bftx.id='" + args[0] + "'"
-
 	resQuery, err := rpcClient.TxSearch(query, true)
 	if err != nil {
 		simpleLogger(cmdQuery, err)
@@ -867,9 +1105,11 @@ func cmdQuery(c *cli.Context) error {
 	defer rpcClient.Stop()
 
 	if len(resQuery) > 0 {
-		printResponse(c, response{
-			Result: string(resQuery[0].Tx),
-		})
+		for _, resTx := range resQuery {
+			printResponse(c, response{
+				Result: string(resTx.Tx),
+			})
+		}
 
 		return nil
 	}
@@ -877,6 +1117,53 @@ func cmdQuery(c *cli.Context) error {
 	return errors.New("Blockfreight Transaction not found.")
 }
 
+// searchFlags returns the query command flags, one per searchable shipment field
+func searchFlags() []cli.Flag {
+	flags := []cli.Flag{
+		cli.StringFlag{
+			Name:  "state",
+			Usage: "search by BF_TX state",
+		},
+	}
+	for _, field := range bft.TagFields {
+		flags = append(flags, cli.StringFlag{
+			Name:  strings.ToLower(field),
+			Usage: "search by " + field,
+		})
+	}
+	return flags
+}
+
+// Look up application state by query path
+func cmdLookup(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.New("Command lookup takes 1 argument")
+	}
+
+	resQuery, err := client.QuerySync(types.RequestQuery{
+		Path:   args[0],
+		Height: int64(c.Int("height")),
+		Prove:  c.Bool("prove"),
+	})
+	if err != nil {
+		simpleLogger(cmdLookup, err)
+		return err
+	}
+
+	printResponse(c, response{
+		Code: resQuery.Code,
+		Log:  resQuery.Log,
+		Query: &queryResponse{
+			Key:    resQuery.Key,
+			Value:  resQuery.Value,
+			Height: resQuery.Height,
+			Proof:  resQuery.Proof,
+		},
+	})
+	return nil
+}
+
 // Return the output JSON
 func cmdGetBfTx(c *cli.Context) error {
 	args := c.Args()
@@ -905,74 +1192,473 @@ func cmdGetBfTx(c *cli.Context) error {
 	return nil
 }
 
-// Append a new BF_TX to an existing BF_TX
+// Amend a committed BF_TX with a new BF_TX, and broadcast the amendment
 func cmdAppendBfTx(c *cli.Context) error {
 	args := c.Args()
 	if len(args) != 2 {
 		return errors.New("Command append takes 2 arguments")
 	}
 
-	// Get a BF_TX by id
-	oldBftx, err := leveldb.GetBfTx(args[1])
+	// Read JSON and instance the BF_TX structure
+	newBftx, err := bf_tx.SetBFTX(c.GlobalString("json_path") + args[0])
 	if err != nil {
-		transLogger(cmdAppendBfTx, err, oldBftx)
 		return err
 	}
 
-	// Query the total of BF_TX in DB
-	// n, err := leveldb.Total()
+	// The amendment references the BF_TX it amends
+	newBftx.Amendment = args[1]
+
+	// Set the BF_TX id
+	newBftx.Id, err = cmdGenerateBftxID(newBftx)
 	if err != nil {
-		transLogger(cmdAppendBfTx, err, oldBftx)
+		transLogger(cmdAppendBfTx, err, newBftx)
 		return err
 	}
 
-	// Read JSON and instance the BF_TX structure
-	newBftx, err := bf_tx.SetBFTX(c.GlobalString("json_path") + args[0])
+	// Re-validate and sign the amendment
+	result, err := validator.ValidateBFTX(newBftx)
+	if err != nil {
+		fmt.Println(result)
+		transLogger(cmdAppendBfTx, err, newBftx)
+		return err
+	}
+	privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+	if err != nil {
+		transLogger(cmdAppendBfTx, err, newBftx)
+		return err
+	}
+	newBftx, err = crypto.SignBFTX(newBftx, privKey)
+	if err != nil {
+		transLogger(cmdAppendBfTx, err, newBftx)
+		return err
+	}
+	newBftx, err = bf_tx.Transition(newBftx, bf_tx.StateSubmitted)
 	if err != nil {
 		return err
-
 	}
 
-	// Set the BF_TX id
-	//newBftx.Id = n+1     //TODO JCNM: Solve concurrency problem
-
-	// Update the BF_TX appended attribute of the old BF_TX
-	oldBftx.Amendment = newBftx.Id
-
-	// Get the BF_TX (old and new) content in string format
-	newContent, err := bf_tx.BFTXContent(newBftx)
+	// Wrap it in an amend envelope signed by a party with authority over the original BF_TX
+	env, err := bf_tx.NewBFTXEnvelope(newBftx)
+	if err != nil {
+		transLogger(cmdAppendBfTx, err, newBftx)
+		return err
+	}
+	env, err = signEnvelope(c, env, true)
 	if err != nil {
 		transLogger(cmdAppendBfTx, err, newBftx)
 		return err
 	}
-	oldContent, err := bf_tx.BFTXContent(oldBftx)
+	tx, err := json.Marshal(env)
 	if err != nil {
 		transLogger(cmdAppendBfTx, err, newBftx)
 		return err
 	}
 
 	// Save on DB
-	err = leveldb.RecordOnDB(string(newBftx.Id), newContent)
+	newContent, err := bf_tx.BFTXContent(newBftx)
 	if err != nil {
 		transLogger(cmdAppendBfTx, err, newBftx)
 		return err
 	}
-
-	// Update on DB
-	err = leveldb.RecordOnDB(string(oldBftx.Id), oldContent)
+	err = leveldb.RecordOnDB(string(newBftx.Id), newContent)
 	if err != nil {
 		transLogger(cmdAppendBfTx, err, newBftx)
 		return err
 	}
 
+	rpcClient = rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
+	err = rpcClient.Start()
+	if err != nil {
+		fmt.Println("Error when initializing rpcClient")
+		log.Fatal(err.Error())
+	}
+
+	defer rpcClient.Stop()
+
+	resp, rpcErr := rpcClient.BroadcastTxSync(tmTypes.Tx(tx))
+	if rpcErr != nil {
+		transLogger(cmdAppendBfTx, rpcErr, newBftx)
+		return rpcErr
+	}
+
 	//Result
 	printResponse(c, response{
 		Result: "BF_TX Id: " + string(newBftx.Id),
+		Data:   resp.Hash,
+		Code:   resp.Code,
+		Log:    resp.Log,
+	})
+
+	return nil
+}
+
+// Register your organization as a party of the network
+func cmdRegisterParty(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 3 && len(args) != 4 {
+		return errors.New("Command register takes 3 or 4 arguments")
+	}
+
+	privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+	if err != nil {
+		simpleLogger(cmdRegisterParty, err)
+		return err
+	}
+	party := bf_tx.Party{
+		Id:      args[0],
+		Name:    args[1],
+		Role:    args[2],
+		PubKeys: [][]byte{privKey.PubKey().Bytes()},
+	}
+	if len(args) == 4 {
+		party.Principal = args[3]
+	}
+	return broadcastOperation(c, bf_tx.OpRegisterParty, party)
+}
+
+// Add a new key to your party
+func cmdRotateKey(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.New("Command rotate-key takes 2 arguments")
+	}
+
+	pubKey, err := hex.DecodeString(args[1])
+	if err != nil {
+		return err
+	}
+	rotation := bf_tx.KeyRotation{Party: args[0], PubKey: pubKey}
+	if c.Bool("revoke") {
+		privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+		if err != nil {
+			simpleLogger(cmdRotateKey, err)
+			return err
+		}
+		rotation.Revoke = privKey.PubKey().Bytes()
+	}
+	return broadcastOperation(c, bf_tx.OpRotateKey, rotation)
+}
+
+// Revoke a key of your party
+func cmdRevokeKey(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.New("Command revoke-key takes 2 arguments")
+	}
+
+	pubKey, err := hex.DecodeString(args[1])
+	if err != nil {
+		return err
+	}
+	return broadcastOperation(c, bf_tx.OpRevokeKey, bf_tx.KeyRevocation{Party: args[0], PubKey: pubKey})
+}
+
+// Approve a party as its principal, or its privileged role as a validator
+func cmdApproveParty(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return errors.New("Command approve takes 1 or 2 arguments")
+	}
+
+	approval := bf_tx.PartyApproval{Party: args[0]}
+	if len(args) == 2 {
+		return broadcastValidatorOperation(c, args[1], bf_tx.OpApproveParty, approval)
+	}
+	return broadcastOperation(c, bf_tx.OpApproveParty, approval)
+}
+
+// Endorse a bill of lading over to its next holder
+func cmdTransferBfTx(c *cli.Context) error {
+	args := c.Args()
+	transfer := bf_tx.Transfer{}
+	if c.String("bearer") != "" {
+		if len(args) != 1 {
+			return errors.New("Command transfer to a bearer takes 1 argument")
+		}
+		bearer, err := hex.DecodeString(c.String("bearer"))
+		if err != nil {
+			return err
+		}
+		transfer.Bearer = bearer
+	} else {
+		if len(args) != 2 {
+			return errors.New("Command transfer takes 2 arguments")
+		}
+		transfer.To = args[1]
+	}
+	transfer.Id = args[0]
+
+	return broadcastOperation(c, bf_tx.OpTransfer, transfer)
+}
+
+// Surrender a bill of lading at destination
+func cmdSurrenderBfTx(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return errors.New("Command surrender takes 1 or 2 arguments")
+	}
+
+	surrender := bf_tx.Surrender{Id: args[0]}
+	if len(args) == 2 {
+		surrender.To = args[1]
+	}
+	return broadcastOperation(c, bf_tx.OpSurrender, surrender)
+}
+
+// Confirm the release of the cargo of a surrendered bill of lading
+func cmdReleaseBfTx(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.New("Command release takes 1 argument")
+	}
+
+	return broadcastOperation(c, bf_tx.OpRelease, bf_tx.Release{Id: args[0]})
+}
+
+// Propose new values of network parameters as a validator
+func cmdProposeParams(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 && len(args) != 3 {
+		return errors.New("Command propose takes 2 or 3 arguments")
+	}
+
+	proposal := bf_tx.ParamProposal{}
+	if err := json.Unmarshal([]byte(args[1]), &proposal.Changes); err != nil {
+		simpleLogger(cmdProposeParams, err)
+		return err
+	}
+	if len(args) == 3 {
+		proposal.Description = args[2]
+	}
+	return broadcastValidatorOperation(c, args[0], bf_tx.OpProposeParams, proposal)
+}
+
+// Vote for an open parameter proposal as a validator
+func cmdVote(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.New("Command vote takes 2 arguments")
+	}
+
+	return broadcastValidatorOperation(c, args[0], bf_tx.OpVote, bf_tx.Vote{Proposal: args[1]})
+}
+
+// broadcastValidatorOperation signs an operation with the key of the validator running this node, and broadcasts it
+func broadcastValidatorOperation(c *cli.Context, privValidatorPath string, opType bf_tx.OpType, payload interface{}) error {
+	env, err := bf_tx.NewEnvelope(opType, payload)
+	if err != nil {
+		return err
+	}
+	privValidator := tmTypes.LoadPrivValidatorFS(privValidatorPath)
+	if env, err = signEnvelopeWith(env, privValidator.PrivKey); err != nil {
+		return err
+	}
+	return broadcastEnvelope(c, env)
+}
+
+// Export the validators, parameters, parties, BF_TX records and titles of the node as a genesis file
+func cmdExportGenesis(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 && len(args) != 2 {
+		return errors.New("Command export takes 1 or 2 arguments")
+	}
+
+	resQuery, err := client.QuerySync(types.RequestQuery{Path: "/genesis"})
+	if err != nil {
+		simpleLogger(cmdExportGenesis, err)
+		return err
+	}
+	if resQuery.Code != 0 {
+		return errors.New(resQuery.Log)
+	}
+	var genesis bft.GenesisDoc
+	if err := json.Unmarshal(resQuery.Value, &genesis); err != nil {
+		simpleLogger(cmdExportGenesis, err)
+		return err
+	}
+	genesis.GenesisTime = time.Now()
+	genesis.ChainID = args[0]
+
+	content, err := json.MarshalIndent(genesis, "", "  ")
+	if err != nil {
+		simpleLogger(cmdExportGenesis, err)
+		return err
+	}
+	if len(args) == 1 {
+		printResponse(c, response{
+			Result: string(content),
+		})
+		return nil
+	}
+	if err := ioutil.WriteFile(args[1], content, 0644); err != nil {
+		simpleLogger(cmdExportGenesis, err)
+		return err
+	}
+	printResponse(c, response{
+		Result: "Genesis file: " + args[1] + ", " + strconv.Itoa(len(genesis.AppState.Parties)) + " parties, " + strconv.Itoa(len(genesis.AppState.BFTX)) + " BF_TX",
 	})
+	return nil
+}
+
+// Co-sign a committed BF_TX that names your party
+func cmdCosignBfTx(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 1 {
+		return errors.New("Command cosign takes 1 argument")
+	}
+
+	// The document hash is computed over the BF_TX as the network holds it
+	resQuery, err := client.QuerySync(types.RequestQuery{Path: "/bftx/" + args[0]})
+	if err != nil {
+		simpleLogger(cmdCosignBfTx, err)
+		return err
+	}
+	if len(resQuery.Value) == 0 {
+		return errors.New("BF_TX " + args[0] + " does not exist.")
+	}
+	bftx := bf_tx.ByteArrayToBFTX(resQuery.Value)
+
+	privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+	if err != nil {
+		simpleLogger(cmdCosignBfTx, err)
+		return err
+	}
+	signature, err := crypto.CosignBFTX(bftx, privKey)
+	if err != nil {
+		transLogger(cmdCosignBfTx, err, bftx)
+		return err
+	}
+	return broadcastOperation(c, bf_tx.OpCosign, bf_tx.Cosign{Id: args[0], Signature: signature})
+}
+
+// Void a BF_TX
+func cmdVoidBfTx(c *cli.Context) error {
+	args := c.Args()
+	if len(args) != 2 {
+		return errors.New("Command void takes 2 arguments")
+	}
 
+	// A BF_TX that never left this node is voided locally
+	bftx, err := leveldb.GetBfTx(args[0])
+	if err == nil {
+		if state := bf_tx.State(bftx); state == bf_tx.StateDraft || state == bf_tx.StateSigned {
+			bftx, err = bf_tx.Transition(bftx, bf_tx.StateVoid)
+			if err != nil {
+				return err
+			}
+			content, err := bf_tx.BFTXContent(bftx)
+			if err != nil {
+				transLogger(cmdVoidBfTx, err, bftx)
+				return err
+			}
+			if err = leveldb.RecordOnDB(bftx.Id, content); err != nil {
+				transLogger(cmdVoidBfTx, err, bftx)
+				return err
+			}
+
+			printResponse(c, response{
+				Result: "BF_TX voided",
+			})
+			return nil
+		}
+	}
+
+	return broadcastOperation(c, bf_tx.OpVoid, bf_tx.Void{Id: args[0], Reason: args[1]})
+}
+
+// broadcastOperation signs an operation with the key file and broadcasts it to the network
+func broadcastOperation(c *cli.Context, opType bf_tx.OpType, payload interface{}) error {
+	env, err := bf_tx.NewEnvelope(opType, payload)
+	if err != nil {
+		return err
+	}
+	env, err = signEnvelope(c, env, true)
+	if err != nil {
+		return err
+	}
+	return broadcastEnvelope(c, env)
+}
+
+// broadcastEnvelope broadcasts a signed transaction envelope to the network
+func broadcastEnvelope(c *cli.Context, env bf_tx.Envelope) error {
+	tx, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	rpcClient = rpc.NewHTTP(os.Getenv("LOCAL_RPC_CLIENT_ADDRESS"), "/websocket")
+	err = rpcClient.Start()
+	if err != nil {
+		fmt.Println("Error when initializing rpcClient")
+		log.Fatal(err.Error())
+	}
+
+	defer rpcClient.Stop()
+
+	resp, rpcErr := rpcClient.BroadcastTxSync(tmTypes.Tx(tx))
+	if rpcErr != nil {
+		simpleLogger(broadcastEnvelope, rpcErr)
+		return rpcErr
+	}
+
+	printResponse(c, response{
+		Data: resp.Hash,
+		Code: resp.Code,
+		Log:  resp.Log,
+	})
 	return nil
 }
 
+// Generate the private key file the transactions are signed with
+func cmdKeygen(c *cli.Context) error {
+	keyPath := c.GlobalString("key")
+	if _, err := os.Stat(keyPath); err == nil {
+		return errors.New("Key file " + keyPath + " already exists.")
+	}
+
+	privKey, err := crypto.GenerateKeyFile(keyPath)
+	if err != nil {
+		simpleLogger(cmdKeygen, err)
+		return err
+	}
+
+	printResponse(c, response{
+		Result: "Key file: " + keyPath + ", public key: " + hex.EncodeToString(privKey.PubKey().Bytes()),
+	})
+	return nil
+}
+
+// signEnvelope signs a transaction envelope with the key file, with the next sequence of the key on the network.
+// Without a key file the envelope is left unsigned, unless a signature is required.
+func signEnvelope(c *cli.Context, env bf_tx.Envelope, required bool) (bf_tx.Envelope, error) {
+	privKey, err := crypto.LoadKeyFile(c.GlobalString("key"))
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return env, nil
+		}
+		return env, err
+	}
+	return signEnvelopeWith(env, privKey)
+}
+
+// signEnvelopeWith signs a transaction envelope with a private key, for the chain of the network and with the next
+// sequence of the key on it
+func signEnvelopeWith(env bf_tx.Envelope, privKey tmcrypto.PrivKey) (bf_tx.Envelope, error) {
+	resQuery, err := client.QuerySync(types.RequestQuery{Path: "/chain_id"})
+	if err != nil {
+		return env, err
+	}
+	env.ChainID = string(resQuery.Value)
+	resQuery, err = client.QuerySync(types.RequestQuery{Path: "/nonce", Data: privKey.PubKey().Bytes()})
+	if err != nil {
+		return env, err
+	}
+	if env.Sequence, err = bft.NextSequence(resQuery); err != nil {
+		return env, err
+	}
+	return crypto.SignEnvelope(env, privKey), nil
+}
+
 // Get the current state of a determined BF_TX
 func cmdStateBfTx(c *cli.Context) error {
 	args := c.Args()
@@ -989,7 +1675,7 @@ func cmdStateBfTx(c *cli.Context) error {
 
 	// Result
 	printResponse(c, response{
-		Result: "BF_TX state: " + bf_tx.State(bftx),
+		Result: "BF_TX state: " + string(bf_tx.State(bftx)),
 	})
 	return nil
 }
@@ -1027,6 +1713,36 @@ func cmdTotalBfTx(c *cli.Context) error {
 	return nil
 }
 
+func cmdMigrateBfTx(c *cli.Context) error {
+	// Migrate the BF_TX in DB
+	report, err := leveldb.Migrate()
+	if err != nil {
+		simpleLogger(cmdMigrateBfTx, err)
+		return err
+	}
+
+	// Result
+	result := "Total BF_TX on BD: " + strconv.Itoa(report.Total) + ", already in schema version " + strconv.Itoa(int(bf_tx.CurrentSchema)) + ": " + strconv.Itoa(report.Current)
+	for version := bf_tx.SchemaFlags; version < bf_tx.CurrentSchema; version++ {
+		result += ", migrated from schema version " + strconv.Itoa(int(version)) + ": " + strconv.Itoa(report.Migrated[version])
+	}
+	result += ", amendment links turned around: " + strconv.Itoa(len(report.Relinked)) + ", dropped: " + strconv.Itoa(len(report.Unlinked))
+	result += ", failed: " + strconv.Itoa(len(report.Failed))
+	var failures []string
+	for id, successor := range report.Unlinked {
+		failures = append(failures, id+": dropped the legacy link to the BF_TX that amends it, "+successor)
+	}
+	for id, reason := range report.Failed {
+		failures = append(failures, id+": "+reason)
+	}
+	sort.Strings(failures)
+	printResponse(c, response{
+		Result: result,
+		Log:    strings.Join(failures, "\n"),
+	})
+	return nil
+}
+
 //--------------------------------------------------------------------------------
 
 func printResponse(c *cli.Context, rsp response) {
@@ -1040,6 +1756,9 @@ func printResponse(c *cli.Context, rsp response) {
 	if rsp.Result != "" {
 		fmt.Printf("-> blockfreight result: %s\n", rsp.Result)
 	}
+	if rsp.Code != types.CodeTypeOK {
+		fmt.Printf("-> code: %d\n", rsp.Code)
+	}
 	if len(rsp.Data) != 0 {
 		//fmt.Printf("-> blockfreight data: %s\n", rsp.Data)
 		fmt.Printf("-> data.hex: %X\n", rsp.Data)
@@ -0,0 +1,399 @@
+// File: ./blockfreight/lib/authz/authz.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+// Package authz is a package that defines the role rules that authorize the operations on a BF_TX. The Blockfreight™
+// application enforces them, and clients can check them to fail fast before broadcasting an operation.
+package authz
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"errors"  // Implements functions to manipulate errors.
+	"reflect" // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"strings" // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// Relations the signer of an operation can have with the bill of lading it operates on.
+const (
+	RelationIssuer         = "issuer"           // Has authority over the bill of lading: signed its issue, or is in the same party.
+	RelationHolder         = "holder"           // Holds the bill of lading.
+	RelationShipper        = "shipper"          // Is the registered party named as Shipper of the BF_TX.
+	RelationAgentForMaster = "agent_for_master" // Is an agent party acting for a registered carrier.
+	RelationCosigner       = "cosigner"         // Is a registered party the BF_TX names to co-sign it.
+)
+
+// FieldCargoItems names the cargo items of the Containers, for the grants of an amendment that changes nothing
+// else of them.
+const FieldCargoItems = "Containers.Items"
+
+// Relations lists the relations a Grant can require.
+var Relations = []string{RelationIssuer, RelationHolder, RelationShipper, RelationAgentForMaster, RelationCosigner}
+
+// Operations lists the operations on a bill of lading the rules authorize. The other operations, e.g. a party
+// registration, have checks of their own.
+var Operations = []bf_tx.OpType{bf_tx.OpIssue, bf_tx.OpAmend, bf_tx.OpStatusUpdate, bf_tx.OpTransfer, bf_tx.OpSurrender, bf_tx.OpRelease, bf_tx.OpVoid, bf_tx.OpCosign}
+
+// Invariants maps the operations on a bill of lading to the relations their signer needs whatever the rules grant,
+// one of them: the rules can restrict who amends, transfers or voids it, but never give that away to a stranger.
+var Invariants = map[bf_tx.OpType][]string{
+	bf_tx.OpAmend:     {RelationIssuer, RelationShipper},
+	bf_tx.OpTransfer:  {RelationHolder},
+	bf_tx.OpSurrender: {RelationHolder},
+	bf_tx.OpRelease:   {RelationIssuer, RelationHolder},
+	bf_tx.OpVoid:      {RelationIssuer},
+	bf_tx.OpCosign:    {RelationCosigner},
+}
+
+// Grant allows the signers with a party role, a relation with the bill of lading, or both. A grant of an amendment
+// can also limit the Fields of the Properties it changes, and the States of the bill of lading it amends.
+type Grant struct {
+	Role     string            `json:"Role,omitempty"`
+	Relation string            `json:"Relation,omitempty"`
+	Fields   []string          `json:"Fields,omitempty"`
+	States   []bf_tx.Lifecycle `json:"States,omitempty"`
+}
+
+// Rule authorizes an operation. A signer needs one of the Allow grants, none means no signer is allowed. An
+// amendment that changes one of the Fields of the Properties also needs one of the grants of that field.
+type Rule struct {
+	Allow  []Grant            `json:"Allow"`
+	Fields map[string][]Grant `json:"Fields,omitempty"`
+}
+
+// Rules maps the operations to the rule that authorizes them. Operations without a rule are not allowed to any signer.
+type Rules map[bf_tx.OpType]Rule
+
+// Signer describes the signer of an operation: the role of its party, empty when its key is not registered,
+// and its relations with the bill of lading.
+type Signer struct {
+	Role      string
+	Relations []string
+}
+
+// NotAllowedError is returned when the signer of an operation has none of the grants of its rule.
+type NotAllowedError struct {
+	Op     bf_tx.OpType
+	Signer Signer
+}
+
+func (err NotAllowedError) Error() string {
+	role := err.Signer.Role
+	if role == "" {
+		role = "unregistered"
+	}
+	return "Operation " + string(err.Op) + " is not allowed to a " + role + " signer with relations [" + strings.Join(err.Signer.Relations, ", ") + "]."
+}
+
+// FieldError is returned when an amendment changes a field the signer has no grant for.
+type FieldError struct {
+	Op    bf_tx.OpType
+	Field string
+}
+
+func (err FieldError) Error() string {
+	return "Operation " + string(err.Op) + " is not allowed to change " + err.Field + "."
+}
+
+// DefaultRules returns the rules of a network whose genesis does not set them: only a carrier, or an agent for
+// its master, issues; the issuer amends, and the shipper too while the bill of lading is not committed yet, but
+// only its description of the goods and cargo items; only the shipper changes the description of the goods; the
+// issuer, a carrier or the holder updates the status; the holder transfers and surrenders; the issuer, or the
+// party it was surrendered to, releases; the issuer voids; the parties a BF_TX names co-sign it.
+func DefaultRules() Rules {
+	return Rules{
+		bf_tx.OpIssue: {Allow: []Grant{{Role: bf_tx.RoleCarrier}, {Role: bf_tx.RoleAgent, Relation: RelationAgentForMaster}}},
+		bf_tx.OpAmend: {
+			Allow: []Grant{
+				{Relation: RelationIssuer},
+				{Relation: RelationShipper, Fields: []string{"DescOfGoods", FieldCargoItems}, States: []bf_tx.Lifecycle{bf_tx.StatePending}},
+			},
+			Fields: map[string][]Grant{"DescOfGoods": {{Relation: RelationShipper}}},
+		},
+		bf_tx.OpStatusUpdate: {Allow: []Grant{{Relation: RelationIssuer}, {Role: bf_tx.RoleCarrier}, {Relation: RelationHolder}}},
+		bf_tx.OpTransfer:     {Allow: []Grant{{Relation: RelationHolder}}},
+		bf_tx.OpSurrender:    {Allow: []Grant{{Relation: RelationHolder}}},
+		bf_tx.OpRelease:      {Allow: []Grant{{Relation: RelationIssuer}, {Relation: RelationHolder}}},
+		bf_tx.OpVoid:         {Allow: []Grant{{Relation: RelationIssuer}}},
+		bf_tx.OpCosign:       {Allow: []Grant{{Relation: RelationCosigner}}},
+	}
+}
+
+// Validate checks the rules only authorize Operations, and only use known roles, relations and Properties fields.
+func (rules Rules) Validate() error {
+	for op, rule := range rules {
+		if !governed(op) {
+			return errors.New("Operation " + string(op) + " is not authorized by rules.")
+		}
+		if err := validateGrants(op, rule.Allow); err != nil {
+			return err
+		}
+		for field, grants := range rule.Fields {
+			if !knownField(field) {
+				return errors.New("Rule of operation " + string(op) + " restricts unknown field " + field + ".")
+			}
+			if err := validateGrants(op, grants); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateGrants(op bf_tx.OpType, grants []Grant) error {
+	for _, grant := range grants {
+		if grant.Role != "" && !contains(bf_tx.Roles, grant.Role) {
+			return errors.New("Rule of operation " + string(op) + " has unknown role " + grant.Role + ".")
+		}
+		if grant.Relation != "" && !contains(Relations, grant.Relation) {
+			return errors.New("Rule of operation " + string(op) + " has unknown relation " + grant.Relation + ".")
+		}
+		if (len(grant.Fields) > 0 || len(grant.States) > 0) && op != bf_tx.OpAmend {
+			return errors.New("Rule of operation " + string(op) + " limits fields or states, only an amendment can.")
+		}
+		for _, field := range grant.Fields {
+			if !knownField(field) {
+				return errors.New("Rule of operation " + string(op) + " grants unknown field " + field + ".")
+			}
+		}
+		for _, state := range grant.States {
+			if !bf_tx.CanTransition(state, bf_tx.StateAmended) {
+				return errors.New("Rule of operation " + string(op) + " grants state " + string(state) + ", which cannot be amended.")
+			}
+		}
+	}
+	return nil
+}
+
+// knownField reports whether a field is one of the Properties, or FieldCargoItems
+func knownField(field string) bool {
+	if field == FieldCargoItems {
+		return true
+	}
+	_, ok := reflect.TypeOf(bf_tx.Properties{}).FieldByName(field)
+	return ok
+}
+
+// Authorize returns a NotAllowedError if the signer has none of the grants of the operation rule, or none of the
+// Invariants relations of the operation.
+func (rules Rules) Authorize(op bf_tx.OpType, signer Signer) error {
+	if relations, ok := Invariants[op]; ok && !signer.related(relations) {
+		return NotAllowedError{Op: op, Signer: signer}
+	}
+	if !signer.granted(rules[op].Allow) {
+		return NotAllowedError{Op: op, Signer: signer}
+	}
+	return nil
+}
+
+// AuthorizeRole returns a NotAllowedError if the operation rule has no grant a signer with a party role could have,
+// whatever its relations with the bill of lading. Clients that do not know those relations use it to fail fast.
+func (rules Rules) AuthorizeRole(op bf_tx.OpType, role string) error {
+	if !governed(op) {
+		return nil
+	}
+	for _, grant := range rules[op].Allow {
+		if grant.Role == "" || grant.Role == role {
+			return nil
+		}
+	}
+	return NotAllowedError{Op: op, Signer: Signer{Role: role}}
+}
+
+// AuthorizeAmendment authorizes an amendment of a bill of lading in a state: the signer needs a grant that allows
+// that state and every field it changes. It returns a FieldError if the amendment changes a field of the Properties
+// the signer has no grant for.
+func (rules Rules) AuthorizeAmendment(signer Signer, state bf_tx.Lifecycle, before bf_tx.Properties, after bf_tx.Properties) error {
+	if !signer.related(Invariants[bf_tx.OpAmend]) {
+		return NotAllowedError{Op: bf_tx.OpAmend, Signer: signer}
+	}
+	changed := ChangedFields(before, after)
+	var denied error = NotAllowedError{Op: bf_tx.OpAmend, Signer: signer}
+	for _, grant := range rules[bf_tx.OpAmend].Allow {
+		if !signer.granted([]Grant{grant}) || (len(grant.States) > 0 && !hasState(grant.States, state)) {
+			continue
+		}
+		if field := grant.uncovered(changed); field != "" {
+			denied = FieldError{Op: bf_tx.OpAmend, Field: field}
+			continue
+		}
+		denied = nil
+		break
+	}
+	if denied != nil {
+		return denied
+	}
+	for _, field := range changed {
+		if grants, ok := rules[bf_tx.OpAmend].Fields[field]; ok && !signer.granted(grants) {
+			return FieldError{Op: bf_tx.OpAmend, Field: field}
+		}
+	}
+	return nil
+}
+
+// ChangedFields returns the names of the Properties fields that differ between two versions of a bill of lading,
+// FieldCargoItems for Containers that only differ in their cargo items.
+func ChangedFields(before bf_tx.Properties, after bf_tx.Properties) []string {
+	var changed []string
+	beforeValue, afterValue := reflect.ValueOf(before), reflect.ValueOf(after)
+	for i := 0; i < beforeValue.NumField(); i++ {
+		if !reflect.DeepEqual(beforeValue.Field(i).Interface(), afterValue.Field(i).Interface()) {
+			field := beforeValue.Type().Field(i).Name
+			if field == "Containers" && reflect.DeepEqual(withoutItems(before.Containers), withoutItems(after.Containers)) {
+				field = FieldCargoItems
+			}
+			changed = append(changed, field)
+		}
+	}
+	return changed
+}
+
+// withoutItems returns a copy of the containers without their cargo items
+func withoutItems(containers []bf_tx.Container) []bf_tx.Container {
+	stripped := make([]bf_tx.Container, len(containers))
+	for i, container := range containers {
+		container.Items = nil
+		stripped[i] = container
+	}
+	return stripped
+}
+
+// uncovered returns the first of the changed fields the grant does not cover, none if it does not limit them
+func (grant Grant) uncovered(changed []string) string {
+	if len(grant.Fields) == 0 {
+		return ""
+	}
+	for _, field := range changed {
+		if !contains(grant.Fields, field) {
+			return field
+		}
+	}
+	return ""
+}
+
+// granted reports whether the signer has one of the grants
+func (signer Signer) granted(grants []Grant) bool {
+	for _, grant := range grants {
+		if grant.Role != "" && grant.Role != signer.Role {
+			continue
+		}
+		if grant.Relation != "" && !contains(signer.Relations, grant.Relation) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// governed reports whether an operation is one of the Operations the rules authorize
+func governed(op bf_tx.OpType) bool {
+	for _, governed := range Operations {
+		if op == governed {
+			return true
+		}
+	}
+	return false
+}
+
+// related reports whether the signer has one of the relations
+func (signer Signer) related(relations []string) bool {
+	for _, relation := range relations {
+		if contains(signer.Relations, relation) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasState(states []bf_tx.Lifecycle, state bf_tx.Lifecycle) bool {
+	for _, item := range states {
+		if item == state {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
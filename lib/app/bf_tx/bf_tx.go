@@ -50,12 +50,12 @@ import (
 	// =======================
 	// Golang Standard library
 	// =======================
-	"crypto/ecdsa"  // Implements the Elliptic Curve Digital Signature Algorithm, as defined in FIPS 186-3.
-	"crypto/sha256" // Implements the SHA256 Algorithm for Hash.
 	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
 
 	"fmt" // Implements formatted I/O with functions analogous to C's printf and scanf.
 
+	"strings" // Implements simple functions to manipulate UTF-8 encoded strings.
+
 	// ====================
 	// Third-party packages
 	// ====================
@@ -79,17 +79,8 @@ func SetBFTX(jsonpath string) (BF_TX, error) {
 	return bftx, nil
 }
 
-//HashBFTX hashes the BF_TX object
-func HashBFTX(bftx BF_TX) ([]byte, error) {
-	bftxBytes := []byte(fmt.Sprintf("%v", bftx))
-
-	hash := sha256.New()
-	hash.Write(bftxBytes)
-
-	return hash.Sum(nil), nil
-}
-
-//GenerateBFTXUID hashes two byte arrays and returns it.
+// GenerateBFTXUID returns the Id of a BF_TX from the HashBFTX hash of its document, salted with the app hash of
+// the last block.
 func GenerateBFTXUID(hash []byte, salt []byte) string {
 	return "BFTX" + fmt.Sprintf("%x", common.HashByteArrays(hash, salt))
 }
@@ -105,15 +96,56 @@ func PrintBFTX(bftx BF_TX) {
 	spew.Dump(bftx)
 }
 
-// State reports the current state of a BF_TX
-func State(bftx BF_TX) string {
-	if bftx.Transmitted {
-		return "Transmitted!"
-	} else if bftx.Verified {
-		return "Signed!"
-	} else {
-		return "Constructed!"
+// State reports the current state of a BF_TX in its lifecycle
+func State(bftx BF_TX) Lifecycle {
+	if bftx.Lifecycle == "" {
+		return StateDraft
+	}
+	return bftx.Lifecycle
+}
+
+// CoSigners returns the Ids of the parties named to co-sign a BF_TX as its master, agent for the master and agent
+// for the owner, without repeats
+func CoSigners(bftx BF_TX) []string {
+	var parties []string
+	for _, id := range []string{bftx.Properties.MasterInfo.Party, bftx.Properties.AgentForMaster.Party, bftx.Properties.AgentForOwner.Party} {
+		if id != "" && !containsString(parties, id) {
+			parties = append(parties, id)
+		}
+	}
+	return parties
+}
+
+// EncryptedFields returns the Properties fields the EncryptionMetaData of a BF_TX lists as encrypted, keyed in
+// lower case. The EncryptionMetaData is a comma separated list of field names, like "Shipper,GrossWeight".
+func EncryptedFields(properties Properties) map[string]bool {
+	encrypted := make(map[string]bool)
+	for _, field := range strings.Split(properties.EncryptionMetaData, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			encrypted[strings.ToLower(field)] = true
+		}
+	}
+	return encrypted
+}
+
+// EncryptionMetaData returns the EncryptionMetaData of a BF_TX whose given fields are encrypted
+func EncryptionMetaData(fields []string) string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			names = append(names, field)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
 	}
+	return false
 }
 
 func ByteArrayToBFTX(obj []byte) BF_TX {
@@ -124,14 +156,12 @@ func ByteArrayToBFTX(obj []byte) BF_TX {
 
 // Reinitialize set the default values to the Blockfreight attributes of BF_TX
 func Reinitialize(bftx BF_TX) BF_TX {
-	bftx.PrivateKey.Curve = nil
-	bftx.PrivateKey.X = nil
-	bftx.PrivateKey.Y = nil
-	bftx.PrivateKey.D = nil
+	bftx.Signer = nil
 	bftx.Signhash = nil
 	bftx.Signature = ""
-	bftx.Verified = false
-	bftx.Transmitted = false
+	bftx.Lifecycle = StateDraft
+	bftx.BlockHeight = 0
+	bftx.BlockTime = 0
 	return bftx
 }
 
@@ -145,25 +175,27 @@ type BF_TX struct {
 	// ===================================
 	// Blockfreight Transaction attributes
 	// ===================================
-	Id          string           `json:"Id"`
-	PrivateKey  ecdsa.PrivateKey `json:"-"`
-	Signhash    []uint8          `json:"Signhash"`
-	Signature   string           `json:"Signature"`
-	Verified    bool             `json:"Verified"`
-	Transmitted bool             `json:"Transmitted"`
-	Amendment   string           `json:"Amendment"`
-	Private     string           `json:"Private"`
+	Schema      SchemaVersion `json:"Schema,omitempty"` // Version of the shape the BF_TX is encoded in, set when it is decoded.
+	Id          string        `json:"Id"`
+	Signer      []byte        `json:"Signer"` // go-wire encoded public key of the party key that signed the BF_TX.
+	Signhash    []uint8       `json:"Signhash"`
+	Signature   string        `json:"Signature"` // Hex encoded go-wire signature of the Signer over the BF_TX.
+	Lifecycle   Lifecycle     `json:"Lifecycle"`
+	Amendment   string        `json:"Amendment"` // Id of the BF_TX this one amends.
+	Private     string        `json:"Private"`
+	BlockHeight int64         `json:"BlockHeight"` // Height of the block the BF_TX was committed in.
+	BlockTime   int64         `json:"BlockTime"`   // Time of that block, in seconds since the Unix epoch.
 }
 
 // Properties struct
 type Properties struct {
-	Shipper             string       `protobuf:"bytes,1,opt,name=Shipper" json:"Shipper"`
+	Shipper             TradeParty   `protobuf:"bytes,1,opt,name=Shipper" json:"Shipper"`
 	BolNum              string       `protobuf:"varint,1,opt,name=BolNum" json:"BolNum"`
 	RefNum              string       `protobuf:"varint,2,opt,name=RefNum" json:"RefNum"`
-	Consignee           string       `protobuf:"bytes,2,opt,name=Consignee" json:"Consignee"`
+	Consignee           TradeParty   `protobuf:"bytes,2,opt,name=Consignee" json:"Consignee"`
 	HouseBill           string       `protobuf:"bytes,3,opt,name=HouseBill" json:"HouseBill"`
 	Vessel              string       `protobuf:"varint,3,opt,name=Vessel" json:"Vessel"`
-	Packages            string       `protobuf:"varint,4,opt,name=Packages" json:"Packages"`
+	Packages            Count        `protobuf:"varint,4,opt,name=Packages" json:"Packages"`
 	PackType            string       `protobuf:"bytes,4,opt,name=PackType" json:"PackType"`
 	INCOTerms           string       `protobuf:"bytes,5,opt,name=INCOTerms" json:"INCOTerms"`
 	PortOfLoading       string       `protobuf:"bytes,6,opt,name=PortOfLoading" json:"PortOfLoading"`
@@ -171,27 +203,28 @@ type Properties struct {
 	Destination         string       `protobuf:"bytes,8,opt,name=Destination" json:"Destination"`
 	MarksAndNumbers     string       `protobuf:"bytes,9,opt,name=MarksAndNumbers" json:"MarksAndNumbers"`
 	UnitOfWeight        string       `protobuf:"bytes,10,opt,name=UnitOfWeight" json:"UnitOfWeight"`
-	DeliverAgent        string       `protobuf:"bytes,11,opt,name=DeliverAgent" json:"DeliverAgent"`
-	ReceiveAgent        string       `protobuf:"bytes,12,opt,name=ReceiveAgent" json:"ReceiveAgent"`
+	DeliverAgent        TradeParty   `protobuf:"bytes,11,opt,name=DeliverAgent" json:"DeliverAgent"`
+	ReceiveAgent        TradeParty   `protobuf:"bytes,12,opt,name=ReceiveAgent" json:"ReceiveAgent"`
 	Container           string       `protobuf:"bytes,13,opt,name=Container" json:"Container"`
 	ContainerSeal       string       `protobuf:"bytes,14,opt,name=ContainerSeal" json:"ContainerSeal"`
 	ContainerMode       string       `protobuf:"bytes,15,opt,name=ContainerMode" json:"ContainerMode"`
 	ContainerType       string       `protobuf:"bytes,16,opt,name=ContainerType" json:"ContainerType"`
-	Volume              string       `protobuf:"bytes,17,opt,name=Volume" json:"Volume"`
+	Volume              Quantity     `protobuf:"bytes,17,opt,name=Volume" json:"Volume"`
 	UnitOfVolume        string       `protobuf:"bytes,18,opt,name=UnitOfVolume" json:"UnitOfVolume"`
-	NotifyAddress       string       `protobuf:"bytes,19,opt,name=NotifyAddress" json:"NotifyAddress"`
+	NotifyAddress       TradeParty   `protobuf:"bytes,19,opt,name=NotifyAddress" json:"NotifyAddress"`
 	DescOfGoods         string       `protobuf:"bytes,20,opt,name=DescOfGoods" json:"DescOfGoods"`
-	GrossWeight         string       `protobuf:"varint,5,opt,name=GrossWeight" json:"GrossWeight"`
-	FreightPayableAmt   string       `protobuf:"varint,6,opt,name=FreightPayableAmt" json:"FreightPayableAmt"`
-	FreightAdvAmt       string       `protobuf:"varint,7,opt,name=FreightAdvAmt" json:"FreightAdvAmt"`
+	GrossWeight         Quantity     `protobuf:"varint,5,opt,name=GrossWeight" json:"GrossWeight"`
+	FreightPayableAmt   Money        `protobuf:"varint,6,opt,name=FreightPayableAmt" json:"FreightPayableAmt"`
+	FreightAdvAmt       Money        `protobuf:"varint,7,opt,name=FreightAdvAmt" json:"FreightAdvAmt"`
 	GeneralInstructions string       `protobuf:"bytes,21,opt,name=GeneralInstructions" json:"GeneralInstructions"`
-	DateShipped         string       `protobuf:"bytes,22,opt,name=DateShipped" json:"DateShipped"`
+	DateShipped         ISODate      `protobuf:"bytes,22,opt,name=DateShipped" json:"DateShipped"`
 	IssueDetails        IssueDetails `json:"IssueDetails"`
 	NumBol              string       `protobuf:"varint,8,opt,name=NumBol" json:"NumBol"`
 	MasterInfo          MasterInfo   `json:"MasterInfo"`
 	AgentForMaster      AgentMaster  `json:"AgentForMaster"`
 	AgentForOwner       AgentOwner   `json:"AgentForOwner"`
 	EncryptionMetaData  string       `json:"EncryptionMetaData"`
+	Containers          []Container  `json:"Containers,omitempty"` // Containers of a bill of lading of more than one, or of several lines of goods.
 }
 
 // Shipper struct
@@ -267,8 +300,8 @@ type Date struct {
 
 // IssueDetails struct
 type IssueDetails struct {
-	PlaceOfIssue string `json:"PlaceOfIssue"`
-	DateOfIssue  string `json:"DateOfIssue"`
+	PlaceOfIssue string  `json:"PlaceOfIssue"`
+	DateOfIssue  ISODate `json:"DateOfIssue"`
 }
 
 // PlaceIssue struct
@@ -281,26 +314,32 @@ type NumBol struct {
 	Type string
 }
 
-// MasterInfo struct
+// MasterInfo struct. Sig is a free text signature line, the master co-signs on the network as its Party.
 type MasterInfo struct {
-	FirstName string `json:"FirstName"`
-	LastName  string `json:"LastName"`
-	Sig       string `json:"Sig"`
+	FirstName string      `json:"FirstName"`
+	LastName  string      `json:"LastName"`
+	Sig       string      `json:"Sig"`
+	Party     string      `json:"Party,omitempty"`   // Id of the registered party that co-signs the BF_TX as the master.
+	Details   *TradeParty `json:"Details,omitempty"` // Address, contact and identifiers of the master, without a Party.
 }
 
-// AgentMaster struct
+// AgentMaster struct. Sig is a free text signature line, the agent co-signs on the network as its Party.
 type AgentMaster struct {
-	FirstName string `json:"FirstName"`
-	LastName  string `json:"LastName"`
-	Sig       string `json:"Sig"`
+	FirstName string      `json:"FirstName"`
+	LastName  string      `json:"LastName"`
+	Sig       string      `json:"Sig"`
+	Party     string      `json:"Party,omitempty"`   // Id of the registered party that co-signs the BF_TX as agent for the master.
+	Details   *TradeParty `json:"Details,omitempty"` // Legal name, address, contact and identifiers of the agent, without a Party.
 }
 
-// AgentOwner struct
+// AgentOwner struct. Sig is a free text signature line, the agent co-signs on the network as its Party.
 type AgentOwner struct {
-	FirstName             string `json:"FirstName"`
-	LastName              string `json:"LastName"`
-	Sig                   string `json:"Sig"`
-	ConditionsForCarriage string `json:"ConditionsForCarriage"`
+	FirstName             string      `json:"FirstName"`
+	LastName              string      `json:"LastName"`
+	Sig                   string      `json:"Sig"`
+	ConditionsForCarriage string      `json:"ConditionsForCarriage"`
+	Party                 string      `json:"Party,omitempty"`   // Id of the registered party that co-signs the BF_TX as agent for the owner.
+	Details               *TradeParty `json:"Details,omitempty"` // Legal name, address, contact and identifiers of the agent, without a Party.
 }
 
 // FirstName struct
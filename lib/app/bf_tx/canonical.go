@@ -0,0 +1,134 @@
+// File: ./blockfreight/lib/bf_tx/canonical.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
+	"crypto/sha256" // Implements the SHA256 Algorithm for Hash.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+)
+
+// CanonicalBFTX returns the canonical encoding of a BF_TX, the content its signature covers: the BF_TX without its
+// schema version, signature and state attributes, as JSON with the keys of every object sorted, no insignificant
+// whitespace and no HTML escaping. It only depends on the values of the BF_TX, not on the Go version or the node
+// that encodes it.
+func CanonicalBFTX(bftx BF_TX) ([]byte, error) {
+	bftx.Schema = 0
+	bftx.Signhash = nil
+	bftx.Signature = ""
+	bftx.Lifecycle = ""
+	bftx.BlockHeight = 0
+	bftx.BlockTime = 0
+	return canonicalJSON(bftx)
+}
+
+// HashBFTX returns the SHA-256 hash of the canonical encoding of a BF_TX document, without its Id and Signer, so
+// the hash an Id is generated from depends neither on the Id nor on the key that signs the BF_TX.
+func HashBFTX(bftx BF_TX) ([]byte, error) {
+	bftx.Id = ""
+	bftx.Signer = nil
+	content, err := CanonicalBFTX(bftx)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	return hash[:], nil
+}
+
+// canonicalJSON encodes a value as JSON, then decodes and encodes it again as generic values, which encoding/json
+// writes with their object keys sorted. Numbers keep the digits of the first encoding.
+func canonicalJSON(value interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	var canonical bytes.Buffer
+	encoder := json.NewEncoder(&canonical)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(generic); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(canonical.Bytes(), []byte("\n")), nil
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,262 @@
+// File: ./blockfreight/lib/bf_tx/cargo.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"errors"   // Implements functions to manipulate errors.
+	"math/big" // Implements arbitrary-precision arithmetic.
+	"strings"  // Implements simple functions to manipulate UTF-8 encoded strings.
+)
+
+// Container is one container of a bill of lading, or one lot of cargo shipped without a container, and the cargo
+// items it holds. The Container, ContainerSeal and ContainerType of the Properties are those of a bill of lading
+// of a single container.
+type Container struct {
+	Container     string      `json:"Container"`     // ISO 6346 container number, e.g. CSQU3054383, empty for cargo without a container.
+	ContainerSeal string      `json:"ContainerSeal"` // Number of the seal on the container.
+	ContainerType string      `json:"ContainerType"` // ISO 6346 size and type code, e.g. 45G1.
+	Packages      Count       `json:"Packages"`
+	GrossWeight   Quantity    `json:"GrossWeight"`
+	Volume        Quantity    `json:"Volume"`
+	Items         []CargoItem `json:"Items,omitempty"`
+}
+
+// CargoItem is one line of the goods in a container.
+type CargoItem struct {
+	DescOfGoods     string   `json:"DescOfGoods"`
+	HSCode          string   `json:"HSCode"` // Harmonized System code of the goods, 6 to 10 digits, e.g. 8471.30.
+	MarksAndNumbers string   `json:"MarksAndNumbers"`
+	Packages        Count    `json:"Packages"`
+	PackType        string   `json:"PackType"`
+	GrossWeight     Quantity `json:"GrossWeight"`
+	Volume          Quantity `json:"Volume"`
+}
+
+// CargoTotals are the packages, gross weight and volume of some cargo, e.g. of a whole bill of lading.
+type CargoTotals struct {
+	Packages    Count
+	GrossWeight Quantity
+	Volume      Quantity
+}
+
+// ItemTotals returns the totals of cargo items.
+func ItemTotals(items []CargoItem) (CargoTotals, error) {
+	var packages []Count
+	var weights, volumes []Quantity
+	for _, item := range items {
+		packages = append(packages, item.Packages)
+		weights = append(weights, item.GrossWeight)
+		volumes = append(volumes, item.Volume)
+	}
+	return sumTotals(packages, weights, volumes)
+}
+
+// ContainerTotals returns the totals of the containers of a bill of lading. A container that leaves out its
+// packages, gross weight or volume counts the total of its items instead.
+func ContainerTotals(containers []Container) (CargoTotals, error) {
+	var packages []Count
+	var weights, volumes []Quantity
+	for _, container := range containers {
+		stated := CargoTotals{Packages: container.Packages, GrossWeight: container.GrossWeight, Volume: container.Volume}
+		if len(container.Items) > 0 {
+			items, err := ItemTotals(container.Items)
+			if err != nil {
+				return CargoTotals{}, errors.New("Items of container " + container.Container + ": " + err.Error())
+			}
+			stated = stated.orElse(items)
+		}
+		packages = append(packages, stated.Packages)
+		weights = append(weights, stated.GrossWeight)
+		volumes = append(volumes, stated.Volume)
+	}
+	return sumTotals(packages, weights, volumes)
+}
+
+// Mismatches returns the names of the values stated for some cargo that are not its totals. A value left out, on
+// either side, is not compared, nor the unit of a quantity without one.
+func (totals CargoTotals) Mismatches(stated CargoTotals) []string {
+	var mismatches []string
+	if !stated.Packages.matches(totals.Packages) {
+		mismatches = append(mismatches, "Packages")
+	}
+	if !stated.GrossWeight.matches(totals.GrossWeight) {
+		mismatches = append(mismatches, "GrossWeight")
+	}
+	if !stated.Volume.matches(totals.Volume) {
+		mismatches = append(mismatches, "Volume")
+	}
+	return mismatches
+}
+
+// ContainerNumbers returns the numbers of the containers of a bill of lading, without repeats.
+func ContainerNumbers(properties Properties) []string {
+	var numbers []string
+	if properties.Container != "" {
+		numbers = append(numbers, properties.Container)
+	}
+	for _, container := range properties.Containers {
+		if container.Container != "" && !containsString(numbers, container.Container) {
+			numbers = append(numbers, container.Container)
+		}
+	}
+	return numbers
+}
+
+// SumQuantities returns the sum of quantities of the same unit. A quantity without a unit is in the unit of the
+// others, and one left out counts as nothing.
+func SumQuantities(quantities ...Quantity) (Quantity, error) {
+	var sum Quantity
+	total, scale := new(big.Rat), 0
+	for _, quantity := range quantities {
+		if quantity.Value == "" {
+			continue
+		}
+		value, ok := quantity.Value.Rat()
+		if !ok {
+			return Quantity{}, errors.New("Invalid quantity " + quantity.String() + ", it must be a decimal number.")
+		}
+		if quantity.Unit != "" && sum.Unit != "" && quantity.Unit != sum.Unit {
+			return Quantity{}, errors.New("Quantities of " + sum.Unit + " and " + quantity.Unit + " cannot be added up.")
+		}
+		if quantity.Unit != "" {
+			sum.Unit = quantity.Unit
+		}
+		if i := strings.Index(string(quantity.Value), "."); i >= 0 && len(quantity.Value)-i-1 > scale {
+			scale = len(quantity.Value) - i - 1
+		}
+		total.Add(total, value)
+		sum.Value = Decimal(total.FloatString(scale))
+	}
+	return sum, nil
+}
+
+// SumCounts returns the sum of counts. A count left out counts as nothing.
+func SumCounts(counts ...Count) (Count, error) {
+	var total int64
+	for _, count := range counts {
+		value, ok := count.Int()
+		if !ok {
+			return "", errors.New("Invalid count " + string(count) + ", it must be a whole number.")
+		}
+		total += value
+	}
+	return NewCount(total), nil
+}
+
+// sumTotals returns the totals of the package counts, weights and volumes of some cargo
+func sumTotals(packages []Count, weights []Quantity, volumes []Quantity) (CargoTotals, error) {
+	count, err := SumCounts(packages...)
+	if err != nil {
+		return CargoTotals{}, errors.New("Packages: " + err.Error())
+	}
+	weight, err := SumQuantities(weights...)
+	if err != nil {
+		return CargoTotals{}, errors.New("GrossWeight: " + err.Error())
+	}
+	volume, err := SumQuantities(volumes...)
+	if err != nil {
+		return CargoTotals{}, errors.New("Volume: " + err.Error())
+	}
+	return CargoTotals{Packages: count, GrossWeight: weight, Volume: volume}, nil
+}
+
+// orElse returns the totals, with the values they leave out taken from other totals
+func (totals CargoTotals) orElse(other CargoTotals) CargoTotals {
+	if value, _ := totals.Packages.Int(); value == 0 {
+		totals.Packages = other.Packages
+	}
+	if totals.GrossWeight.Value == "" {
+		totals.GrossWeight = other.GrossWeight
+	}
+	if totals.Volume.Value == "" {
+		totals.Volume = other.Volume
+	}
+	return totals
+}
+
+// matches reports whether a stated quantity is a total, always if either is left out
+func (quantity Quantity) matches(total Quantity) bool {
+	if quantity.Value == "" || total.Value == "" {
+		return true
+	}
+	stated, ok := quantity.Value.Rat()
+	value, totalOK := total.Value.Rat()
+	if !ok || !totalOK || stated.Cmp(value) != 0 {
+		return false
+	}
+	return quantity.Unit == "" || total.Unit == "" || quantity.Unit == total.Unit
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,272 @@
+// File: ./blockfreight/lib/bf_tx/envelope.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+)
+
+// EnvelopeVersion is the version of the transaction envelope built by this release. Version 2 added the Sequence,
+// version 3 the ChainID.
+const EnvelopeVersion = 3
+
+// OpType is the kind of operation a transaction envelope carries.
+type OpType string
+
+// Operations that can be sent to the Blockfreight™ Network, with the payload each one carries.
+const (
+	OpIssue         OpType = "issue"          // Payload: BF_TX, issues a new bill of lading.
+	OpAmend         OpType = "amend"          // Payload: BF_TX, whose Amendment is the Id of the BF_TX it amends.
+	OpStatusUpdate  OpType = "status_update"  // Payload: StatusUpdate.
+	OpTransfer      OpType = "transfer"       // Payload: Transfer.
+	OpSurrender     OpType = "surrender"      // Payload: Surrender.
+	OpRelease       OpType = "release"        // Payload: Release.
+	OpVoid          OpType = "void"           // Payload: Void.
+	OpRegisterParty OpType = "register_party" // Payload: Party.
+	OpRotateKey     OpType = "rotate_key"     // Payload: KeyRotation.
+	OpRevokeKey     OpType = "revoke_key"     // Payload: KeyRevocation.
+	OpCosign        OpType = "cosign"         // Payload: Cosign.
+	OpProposeParams OpType = "propose_params" // Payload: ParamProposal.
+	OpVote          OpType = "vote"           // Payload: Vote.
+	OpApproveParty  OpType = "approve_party"  // Payload: PartyApproval.
+)
+
+// Envelope wraps every transaction sent to the Blockfreight™ Network, so the ledger records what the sender
+// meant to do and not only the resulting document.
+type Envelope struct {
+	Version   int             `json:"Version"`
+	Type      OpType          `json:"Type"`
+	Payload   json.RawMessage `json:"Payload"`
+	ChainID   string          `json:"ChainID,omitempty"`   // Id of the chain the envelope is signed for.
+	Sequence  uint64          `json:"Sequence,omitempty"`  // Follows the sequence of the last envelope of the signer, starting at 1.
+	Signer    []byte          `json:"Signer,omitempty"`    // go-wire encoded public key of the sender.
+	Signature []byte          `json:"Signature,omitempty"` // go-wire encoded signature of the signer over SignBytes.
+}
+
+// StatusUpdate reports a shipment status, e.g. loaded, departed or arrived, for a committed BF_TX.
+type StatusUpdate struct {
+	Id      string `json:"Id"`
+	Status  string `json:"Status"`
+	Remarks string `json:"Remarks"`
+}
+
+// Transfer is signed by the holder of a bill of lading to endorse it over to a named party, or in blank to the
+// owner of a bearer key. Exactly one of To and Bearer is set.
+type Transfer struct {
+	Id     string `json:"Id"`
+	To     string `json:"To,omitempty"`     // Id of the registered party the bill is endorsed to.
+	Bearer []byte `json:"Bearer,omitempty"` // go-wire encoded public key of the bearer the bill is endorsed to.
+}
+
+// Surrender is signed by the final holder of a bill of lading to give it up at destination, to the carrier or
+// to its DeliverAgent, in exchange for the cargo.
+type Surrender struct {
+	Id string `json:"Id"`
+	To string `json:"To,omitempty"` // Id of the registered DeliverAgent party, empty to surrender to the carrier.
+}
+
+// Release is signed by the carrier, or the party the bill of lading was surrendered to, to confirm the cargo
+// was released. The bill of lading is then accomplished.
+type Release struct {
+	Id string `json:"Id"`
+}
+
+// Void is signed by a party with authority over a bill of lading to cancel it, while its issuer still holds it.
+type Void struct {
+	Id     string `json:"Id"`
+	Reason string `json:"Reason"`
+}
+
+// Cosign is signed by a party named in the MasterInfo, AgentForMaster or AgentForOwner of a committed BF_TX. It
+// carries the signature of the party, with the key that signs the envelope, over the document hash of the BF_TX.
+type Cosign struct {
+	Id        string `json:"Id"`
+	Signature []byte `json:"Signature"` // go-wire encoded signature over the document hash.
+}
+
+// ParamProposal is signed by a validator to propose new values of parameters of the network. It passes once the
+// validators that voted for it, its proposer included, hold the VoteThreshold of the voting power.
+type ParamProposal struct {
+	Changes     map[string]json.RawMessage `json:"Changes"` // New JSON value of each parameter, by name.
+	Description string                     `json:"Description"`
+}
+
+// Vote is signed by a validator to vote for an open proposal.
+type Vote struct {
+	Proposal string `json:"Proposal"` // Id of the proposal, tagged when it was delivered.
+}
+
+// Roles a party of the Blockfreight™ Network can register with.
+const (
+	RoleShipper   = "shipper"
+	RoleCarrier   = "carrier"
+	RoleAgent     = "agent"
+	RoleConsignee = "consignee"
+	RoleBank      = "bank"
+)
+
+// Roles lists the roles a party can register with.
+var Roles = []string{RoleShipper, RoleCarrier, RoleAgent, RoleConsignee, RoleBank}
+
+// Party is a participant of the Blockfreight™ Network, e.g. a carrier, shipper, consignee or bank.
+type Party struct {
+	Id        string   `json:"Id"`
+	Name      string   `json:"Name"`
+	Role      string   `json:"Role"`                // One of Roles.
+	PubKeys   [][]byte `json:"PubKeys"`             // go-wire encoded public keys the party signs with.
+	Revoked   [][]byte `json:"Revoked,omitempty"`   // Keys the party revoked, set by the network.
+	Principal string   `json:"Principal,omitempty"` // Id of the registered party an agent acts for, e.g. the carrier of the master.
+	// A party only acts with a privileged role, e.g. carrier, once a validator approved it, and only acts for its
+	// Principal once the principal approved it. Both are set by the network.
+	RoleApproved      bool `json:"RoleApproved,omitempty"`
+	PrincipalApproved bool `json:"PrincipalApproved,omitempty"`
+}
+
+// PartyApproval is signed by a validator to approve the privileged role of a registered party, or with a key of
+// the principal a registered party names to approve it acts for the principal.
+type PartyApproval struct {
+	Party string `json:"Party"`
+}
+
+// KeyRotation is signed with a key of a party to add a new key to it, and optionally revoke an old one.
+type KeyRotation struct {
+	Party  string `json:"Party"`
+	PubKey []byte `json:"PubKey"`           // go-wire encoded public key added to the party.
+	Revoke []byte `json:"Revoke,omitempty"` // go-wire encoded public key revoked at the same time.
+}
+
+// KeyRevocation is signed with a key of a party to revoke one of its keys, e.g. a compromised one.
+type KeyRevocation struct {
+	Party  string `json:"Party"`
+	PubKey []byte `json:"PubKey"`
+}
+
+// NewEnvelope wraps an operation payload in an unsigned envelope of the current version.
+func NewEnvelope(opType OpType, payload interface{}) (Envelope, error) {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{Version: EnvelopeVersion, Type: opType, Payload: content}, nil
+}
+
+// NewBFTXEnvelope wraps a BF_TX in an issue envelope, or in an amend envelope when it amends another BF_TX.
+func NewBFTXEnvelope(bftx BF_TX) (Envelope, error) {
+	if bftx.Amendment != "" {
+		return NewEnvelope(OpAmend, bftx)
+	}
+	return NewEnvelope(OpIssue, bftx)
+}
+
+// SignBytes returns the bytes the sender signs, the envelope without its signature.
+func (env Envelope) SignBytes() []byte {
+	env.Signature = nil
+	signBytes, _ := json.Marshal(env)
+	return signBytes
+}
+
+// DecodeEnvelope decodes a transaction. A bare BF_TX, as sent before envelopes existed, is decoded
+// as a version 0 issue or amend envelope.
+func DecodeEnvelope(tx []byte) (Envelope, error) {
+	var probe struct {
+		Version   int    `json:"Version"`
+		Type      OpType `json:"Type"`
+		Amendment string `json:"Amendment"`
+	}
+	if err := json.Unmarshal(tx, &probe); err != nil {
+		return Envelope{}, err
+	}
+
+	if probe.Type == "" {
+		env := Envelope{Type: OpIssue, Payload: tx}
+		if probe.Amendment != "" {
+			env.Type = OpAmend
+		}
+		return env, nil
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(tx, &env); err != nil {
+		return env, err
+	}
+	if env.Version < 1 || env.Version > EnvelopeVersion {
+		return env, errors.New("Unsupported envelope version " + strconv.Itoa(env.Version))
+	}
+	return env, nil
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
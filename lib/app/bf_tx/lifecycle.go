@@ -0,0 +1,133 @@
+// File: ./blockfreight/lib/bf_tx/lifecycle.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+// Lifecycle is the state of a BF_TX in its lifecycle.
+type Lifecycle string
+
+// States of a BF_TX. Draft, Signed and Submitted are reached by the sender, the others only on the Blockfreight™ Network.
+const (
+	StateDraft        Lifecycle = "Draft"        // Constructed, not signed yet.
+	StateSigned       Lifecycle = "Signed"       // Signed by its sender.
+	StateSubmitted    Lifecycle = "Submitted"    // Sent to the network.
+	StatePending      Lifecycle = "Pending"      // Committed in a block, waiting for the co-signatures of its named parties.
+	StateCommitted    Lifecycle = "Committed"    // Committed in a block, held by its issuer.
+	StateAmended      Lifecycle = "Amended"      // Superseded by an amendment.
+	StateTransferred  Lifecycle = "Transferred"  // Endorsed over to another holder.
+	StateSurrendered  Lifecycle = "Surrendered"  // Surrendered at destination.
+	StateAccomplished Lifecycle = "Accomplished" // Its cargo was released.
+	StateVoid         Lifecycle = "Void"         // Cancelled.
+)
+
+// transitions lists the states a BF_TX can move to from each state. Amended, Accomplished and Void are final.
+var transitions = map[Lifecycle][]Lifecycle{
+	StateDraft:       {StateSigned, StateVoid},
+	StateSigned:      {StateSubmitted, StateVoid},
+	StateSubmitted:   {StatePending, StateCommitted},
+	StatePending:     {StateCommitted, StateAmended, StateVoid},
+	StateCommitted:   {StateAmended, StateTransferred, StateSurrendered, StateVoid},
+	StateTransferred: {StateAmended, StateTransferred, StateSurrendered},
+	StateSurrendered: {StateAccomplished},
+}
+
+// TransitionError is returned when a BF_TX cannot move from its state to the one asked for.
+type TransitionError struct {
+	Id   string
+	From Lifecycle
+	To   Lifecycle
+}
+
+func (err TransitionError) Error() string {
+	return "BF_TX " + err.Id + " cannot go from " + string(err.From) + " to " + string(err.To) + "."
+}
+
+// CanTransition reports whether the lifecycle allows a BF_TX to move from one state to another.
+func CanTransition(from Lifecycle, to Lifecycle) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves a BF_TX to a new state, or returns a TransitionError if its lifecycle does not allow it.
+func Transition(bftx BF_TX, to Lifecycle) (BF_TX, error) {
+	from := State(bftx)
+	if !CanTransition(from, to) {
+		return bftx, TransitionError{Id: bftx.Id, From: from, To: to}
+	}
+	bftx.Lifecycle = to
+	return bftx, nil
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,184 @@
+// File: ./blockfreight/lib/bf_tx/party.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"reflect"       // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+)
+
+// TradeParty is a party named in a bill of lading, like its shipper, consignee or notify party: its legal name,
+// postal address, contact and identifiers. A legacy party is only a name, the free text of its field, and is encoded
+// in JSON and in the canonical encoding as that string, so the hash and signature of a legacy BF_TX do not change.
+// Any other party is encoded as an object of its fields, and in the saber proto as the text of that object.
+type TradeParty struct {
+	Name       string   `json:"Name"`              // Legal name, or the free text of a legacy party.
+	Address    []string `json:"Address,omitempty"` // Postal address lines, before the city.
+	City       string   `json:"City,omitempty"`
+	PostalCode string   `json:"PostalCode,omitempty"`
+	Country    string   `json:"Country,omitempty"` // ISO 3166-1 alpha-2 country code, e.g. SG.
+	Contact    string   `json:"Contact,omitempty"` // Name of the person to contact at the party.
+	Phone      string   `json:"Phone,omitempty"`
+	Email      string   `json:"Email,omitempty"`
+	LEI        string   `json:"LEI,omitempty"`     // ISO 17442 Legal Entity Identifier.
+	GLN        string   `json:"GLN,omitempty"`     // GS1 Global Location Number.
+	EORI       string   `json:"EORI,omitempty"`    // Economic Operators Registration and Identification number.
+	TaxID      string   `json:"TaxID,omitempty"`   // Tax or VAT number, as its country issues it.
+	ToOrder    bool     `json:"ToOrder,omitempty"` // Consigned to the order of the party, or of the shipper if it has no Name.
+	Party      string   `json:"Party,omitempty"`   // Id of the registered party.
+}
+
+// ParseParty decodes the text form of a TradeParty: the text of the object of its fields, or else a legacy party
+// named by the whole text.
+func ParseParty(text string) TradeParty {
+	if strings.HasPrefix(text, "{") {
+		type fields TradeParty
+		var party TradeParty
+		if err := json.Unmarshal([]byte(text), (*fields)(&party)); err == nil && !party.legacy() {
+			return party
+		}
+	}
+	return TradeParty{Name: text}
+}
+
+// String returns the text form of a TradeParty, its Name for a legacy party.
+func (party TradeParty) String() string {
+	if party.legacy() {
+		return party.Name
+	}
+	type fields TradeParty
+	text, _ := json.Marshal(fields(party))
+	return string(text)
+}
+
+// Ref returns the value a TradeParty is indexed and tagged by: the Id of its registered Party, or else its Name, which
+// for a legacy party is often that Id. It is only a search key; a TradeParty stands for a registered party, and is
+// granted what that party may do, only through its Party.
+func (party TradeParty) Ref() string {
+	if party.Party != "" {
+		return party.Party
+	}
+	return party.Name
+}
+
+// Empty reports whether a TradeParty is left out.
+func (party TradeParty) Empty() bool {
+	return party.Name == "" && party.legacy()
+}
+
+// MarshalJSON encodes a legacy TradeParty as its Name, and any other as an object of its fields.
+func (party TradeParty) MarshalJSON() ([]byte, error) {
+	if party.legacy() {
+		return json.Marshal(party.Name)
+	}
+	type fields TradeParty
+	return json.Marshal(fields(party))
+}
+
+// UnmarshalJSON decodes a TradeParty from its text form, a legacy string or an object of its fields.
+func (party *TradeParty) UnmarshalJSON(data []byte) error {
+	if text, ok, err := textValue(data); ok {
+		*party = ParseParty(text)
+		return err
+	}
+	type fields TradeParty
+	return json.Unmarshal(data, (*fields)(party))
+}
+
+// legacy reports whether a TradeParty has no field but its Name
+func (party TradeParty) legacy() bool {
+	party.Name = ""
+	if len(party.Address) == 0 {
+		party.Address = nil
+	}
+	return reflect.DeepEqual(party, TradeParty{})
+}
+
+// PartiesAsText returns Properties with each of their parties replaced by a legacy party named by its text form,
+// the string the saber proto carries. ParseParty decodes them back.
+func PartiesAsText(properties Properties) Properties {
+	for _, party := range []*TradeParty{&properties.Shipper, &properties.Consignee, &properties.NotifyAddress, &properties.DeliverAgent, &properties.ReceiveAgent} {
+		*party = TradeParty{Name: party.String()}
+	}
+	for _, details := range []**TradeParty{&properties.MasterInfo.Details, &properties.AgentForMaster.Details, &properties.AgentForOwner.Details} {
+		if *details != nil {
+			*details = &TradeParty{Name: (*details).String()}
+		}
+	}
+	return properties
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,297 @@
+// File: ./blockfreight/lib/bf_tx/schema.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+)
+
+// SchemaVersion is the version of the shape a BF_TX is encoded in.
+type SchemaVersion int
+
+const (
+	// SchemaFlags is the shape of the first BF_TX: their state in the Verified and Transmitted flags, and every
+	// property a string. saberservice.BFTXTransaction is this shape too, with the signing key of the BF_TX.
+	SchemaFlags SchemaVersion = 1
+	// SchemaLifecycle is the shape of BF_TX with a Lifecycle state, a Signer and the block they were committed
+	// in, and every property still a string.
+	SchemaLifecycle SchemaVersion = 2
+	// SchemaTyped is the shape of BF_TX with typed quantities, amounts, package count and dates, and the first
+	// to carry its Schema version.
+	SchemaTyped SchemaVersion = 3
+	// SchemaContainers is the shape of BF_TX that can list many Containers, each with its cargo items.
+	SchemaContainers SchemaVersion = 4
+	// SchemaParties is the shape of BF_TX whose parties can be structured TradeParty records, not only names.
+	SchemaParties SchemaVersion = 5
+
+	// CurrentSchema is the version every BF_TX is encoded in.
+	CurrentSchema = SchemaParties
+)
+
+// SchemaError is returned for a BF_TX encoded in a schema version this node does not know.
+type SchemaError struct {
+	Version SchemaVersion
+}
+
+func (err SchemaError) Error() string {
+	return "Unknown BF_TX schema version " + strconv.Itoa(int(err.Version)) + ", the latest known is " + strconv.Itoa(int(CurrentSchema)) + "."
+}
+
+// migrations upgrades the JSON fields of a BF_TX from each schema version to the next one.
+var migrations = map[SchemaVersion]func(fields map[string]json.RawMessage) error{
+	SchemaFlags:      migrateFlags,
+	SchemaLifecycle:  migrateLifecycle,
+	SchemaTyped:      migrateNothing,
+	SchemaContainers: migrateNothing,
+}
+
+// DetectSchema reports the schema version a BF_TX is encoded in: its Schema attribute, or for a BF_TX encoded
+// before it existed, SchemaLifecycle if it has a Lifecycle state and SchemaFlags if not.
+func DetectSchema(data []byte) (SchemaVersion, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, err
+	}
+	return schemaOf(fields)
+}
+
+// DecodeBFTX decodes a BF_TX encoded in any known schema version, migrated to the current one, and reports the
+// version it was encoded in.
+func DecodeBFTX(data []byte) (BF_TX, SchemaVersion, error) {
+	var bftx BF_TX
+	version, err := DetectSchema(data)
+	if err != nil {
+		return bftx, version, err
+	}
+	err = json.Unmarshal(data, &bftx)
+	return bftx, version, err
+}
+
+// MigrateBFTX returns a stored BF_TX encoded in the current schema version, and the version it was encoded in.
+// A migration keeps the canonical encoding of the BF_TX, so its Id and signatures stay valid, but for the forward
+// Amendment link of a BF_TX encoded before SchemaTyped, which it drops (see LegacyAmendedBy).
+func MigrateBFTX(data []byte) ([]byte, SchemaVersion, error) {
+	bftx, version, err := DecodeBFTX(data)
+	if err != nil {
+		return nil, version, err
+	}
+	content, err := json.Marshal(bftx)
+	return content, version, err
+}
+
+// UnmarshalJSON decodes a BF_TX encoded in any known schema version, migrating it to the current one.
+func (bftx *BF_TX) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	version, err := schemaOf(fields)
+	if err != nil {
+		return err
+	}
+	for ; version < CurrentSchema; version++ {
+		if err := migrations[version](fields); err != nil {
+			return err
+		}
+	}
+
+	migrated, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	type plain BF_TX
+	if err := json.Unmarshal(migrated, (*plain)(bftx)); err != nil {
+		return err
+	}
+	bftx.Schema = CurrentSchema
+	return nil
+}
+
+// schemaOf reports the schema version of the JSON fields of a BF_TX
+func schemaOf(fields map[string]json.RawMessage) (SchemaVersion, error) {
+	var version SchemaVersion
+	if err := decodeField(fields, "Schema", &version); err != nil {
+		return 0, err
+	}
+	switch {
+	case version == 0:
+		if _, ok := fields["Lifecycle"]; ok {
+			return SchemaLifecycle, nil
+		}
+		return SchemaFlags, nil
+	case version < SchemaTyped || version > CurrentSchema:
+		// The Schema attribute was introduced by SchemaTyped, no BF_TX carries an earlier version
+		return version, SchemaError{Version: version}
+	}
+	return version, nil
+}
+
+// migrateFlags derives the Lifecycle state of a SchemaFlags BF_TX from its Verified and Transmitted flags, or
+// from the block it was committed in, and drops the flags and the signing key of a saberservice BF_TX.
+func migrateFlags(fields map[string]json.RawMessage) error {
+	var verified, transmitted bool
+	var height int64
+	for name, value := range map[string]interface{}{"Verified": &verified, "Transmitted": &transmitted, "BlockHeight": &height} {
+		if err := decodeField(fields, name, value); err != nil {
+			return err
+		}
+	}
+
+	state := StateDraft
+	switch {
+	case height > 0:
+		state = StateCommitted
+	case transmitted:
+		state = StateSubmitted
+	case verified:
+		state = StateSigned
+	}
+	if state != StateDraft {
+		lifecycle, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		fields["Lifecycle"] = lifecycle
+	}
+
+	delete(fields, "Verified")
+	delete(fields, "Transmitted")
+	delete(fields, "PrivateKey")
+	return nil
+}
+
+// LegacyAmendedBy returns the Id of the BF_TX that amends a stored BF_TX encoded before SchemaTyped, none for a
+// later one. The Amendment of those BF_TX pointed forward, to the BF_TX appended to them, where it now names the
+// BF_TX a BF_TX amends: the migration drops it, and the successor takes the backward link instead.
+func LegacyAmendedBy(data []byte) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	version, err := schemaOf(fields)
+	if err != nil || version >= SchemaTyped {
+		return "", err
+	}
+	var successor string
+	err = decodeField(fields, "Amendment", &successor)
+	return successor, err
+}
+
+// migrateLifecycle upgrades a SchemaLifecycle BF_TX to SchemaTyped: it drops the forward Amendment link and
+// re-encodes the string properties as the typed properties.
+func migrateLifecycle(fields map[string]json.RawMessage) error {
+	delete(fields, "Amendment")
+	return migrateProperties(fields)
+}
+
+// migrateProperties re-encodes the string properties of a SchemaLifecycle BF_TX as the typed properties. Their
+// text forms are the legacy strings, so the canonical encoding of the BF_TX does not change.
+func migrateProperties(fields map[string]json.RawMessage) error {
+	var properties Properties
+	if err := decodeField(fields, "Properties", &properties); err != nil {
+		return errors.New("Properties of the BF_TX do not migrate to schema version " + strconv.Itoa(int(SchemaTyped)) + ": " + err.Error())
+	}
+	if _, ok := fields["Properties"]; !ok {
+		return nil
+	}
+	typed, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	fields["Properties"] = typed
+	return nil
+}
+
+// migrateNothing upgrades a BF_TX whose encoding is still valid in the next schema version, which only adds
+// optional fields or new forms of existing ones
+func migrateNothing(fields map[string]json.RawMessage) error {
+	return nil
+}
+
+// decodeField decodes a JSON field of a BF_TX into a value, and leaves the value as is if the field is missing
+func decodeField(fields map[string]json.RawMessage, name string, value interface{}) error {
+	data, ok := fields[name]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, value)
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
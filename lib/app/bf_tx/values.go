@@ -0,0 +1,328 @@
+// File: ./blockfreight/lib/bf_tx/values.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bf_tx
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"math/big"      // Implements arbitrary-precision arithmetic.
+	"regexp"        // Implements regular expression search.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+	"time"          // Provides functionality for measuring and displaying time.
+)
+
+// The typed Properties values are encoded in JSON, in the saber proto and in the canonical encoding as text: a
+// Quantity as "15523.5 KGM", Money as "354534.00 USD", a Count as "12" and an ISODate as "2016-11-28". The values of
+// a legacy BF_TX, plain strings without a unit or currency, decode to typed values that encode back to the same
+// strings, so the hash and signature of a legacy BF_TX do not change. A Quantity or Money also decodes from a JSON
+// object of its fields, the form the GraphQL API sends.
+
+// Decimal is an exact decimal number, kept as the text of its digits, e.g. "15523.5".
+type Decimal string
+
+var (
+	decimalPattern  = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// Rat returns the exact value of a Decimal, false if it is not a decimal number.
+func (decimal Decimal) Rat() (*big.Rat, bool) {
+	if !decimalPattern.MatchString(string(decimal)) {
+		return nil, false
+	}
+	return new(big.Rat).SetString(string(decimal))
+}
+
+// nonNegative reports whether a Decimal is a decimal number of at least zero
+func (decimal Decimal) nonNegative() bool {
+	value, ok := decimal.Rat()
+	return ok && value.Sign() >= 0
+}
+
+// Quantity is a decimal amount of a unit, e.g. a weight of 15523.5 KGM. Units are UN/ECE Recommendation 20 codes,
+// like KGM for kilograms and MTQ for cubic meters. A legacy quantity has no unit, the BF_TX gives it apart.
+type Quantity struct {
+	Value Decimal `json:"Value"`
+	Unit  string  `json:"Unit"`
+}
+
+// ParseQuantity decodes the text form of a Quantity, its value and unit separated by a space.
+func ParseQuantity(text string) Quantity {
+	value, unit := splitUnit(text)
+	return Quantity{Value: Decimal(value), Unit: unit}
+}
+
+// String returns the text form of a Quantity.
+func (quantity Quantity) String() string {
+	return joinUnit(string(quantity.Value), quantity.Unit)
+}
+
+// Valid reports whether a Quantity is left out, or is a non-negative decimal number of a unit code without spaces.
+func (quantity Quantity) Valid() bool {
+	if quantity == (Quantity{}) {
+		return true
+	}
+	return quantity.Value.nonNegative() && !strings.ContainsAny(quantity.Unit, " \t\n")
+}
+
+// MarshalJSON encodes a Quantity as its text form.
+func (quantity Quantity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quantity.String())
+}
+
+// UnmarshalJSON decodes a Quantity from its text form, a legacy string or an object of its fields.
+func (quantity *Quantity) UnmarshalJSON(data []byte) error {
+	if text, ok, err := textValue(data); ok {
+		*quantity = ParseQuantity(text)
+		return err
+	}
+	type fields Quantity
+	return json.Unmarshal(data, (*fields)(quantity))
+}
+
+// Money is a decimal amount of an ISO 4217 currency, e.g. 354534.00 USD. Legacy amounts have no currency.
+type Money struct {
+	Amount   Decimal `json:"Amount"`
+	Currency string  `json:"Currency"`
+}
+
+// ParseMoney decodes the text form of Money, its amount and currency separated by a space.
+func ParseMoney(text string) Money {
+	amount, currency := splitUnit(text)
+	return Money{Amount: Decimal(amount), Currency: currency}
+}
+
+// String returns the text form of Money.
+func (money Money) String() string {
+	return joinUnit(string(money.Amount), money.Currency)
+}
+
+// Valid reports whether Money is left out, or is a non-negative decimal amount of an ISO 4217 currency code, or of
+// no currency.
+func (money Money) Valid() bool {
+	if money == (Money{}) {
+		return true
+	}
+	return money.Amount.nonNegative() && (money.Currency == "" || currencyPattern.MatchString(money.Currency))
+}
+
+// MarshalJSON encodes Money as its text form.
+func (money Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(money.String())
+}
+
+// UnmarshalJSON decodes Money from its text form, a legacy string or an object of its fields.
+func (money *Money) UnmarshalJSON(data []byte) error {
+	if text, ok, err := textValue(data); ok {
+		*money = ParseMoney(text)
+		return err
+	}
+	type fields Money
+	return json.Unmarshal(data, (*fields)(money))
+}
+
+// Count is a whole number of things, e.g. packages, kept as the text of its digits, e.g. "12", and left out as an
+// empty string. A legacy count keeps its text as it was, padded with spaces, "0", or followed by its kind of
+// packages like "12 CTNS", so that it encodes back to the same string.
+type Count string
+
+// NewCount returns the Count of a number, left out for 0.
+func NewCount(value int64) Count {
+	if value == 0 {
+		return ""
+	}
+	return Count(strconv.FormatInt(value, 10))
+}
+
+// ParseCount decodes the text of the digits of a Count, with any spaces around them, an empty string as a count
+// left out.
+func ParseCount(text string) (Count, error) {
+	count := Count(strings.TrimSpace(text))
+	if !count.Valid() {
+		return "", errors.New("Invalid count " + text + ", it must be a whole number.")
+	}
+	return count, nil
+}
+
+// Int returns the number of a Count, 0 if it is left out, and false if it has none. The number of a legacy count
+// followed by its kind of packages is the number before it.
+func (count Count) Int() (int64, bool) {
+	number, _ := splitUnit(strings.TrimSpace(string(count)))
+	if number == "" {
+		return 0, true
+	}
+	value, err := strconv.ParseInt(number, 10, 64)
+	return value, err == nil
+}
+
+// Valid reports whether a Count is left out, or is the digits of a whole number that is not negative.
+func (count Count) Valid() bool {
+	if count == "" {
+		return true
+	}
+	value, err := strconv.ParseInt(string(count), 10, 64)
+	return err == nil && value >= 0
+}
+
+// matches reports whether a Count is the same number as another one, or either is left out
+func (count Count) matches(other Count) bool {
+	value, ok := count.Int()
+	otherValue, otherOk := other.Int()
+	return ok && otherOk && (value == 0 || otherValue == 0 || value == otherValue)
+}
+
+// UnmarshalJSON decodes a Count from its text, a legacy string, or a JSON number.
+func (count *Count) UnmarshalJSON(data []byte) error {
+	if text, ok, err := textValue(data); ok {
+		*count = Count(text)
+		return err
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return errors.New("Invalid count " + string(data) + ", it must be a whole number.")
+	}
+	*count = Count(number)
+	return nil
+}
+
+// ISODate is a calendar date in the ISO 8601 form YYYY-MM-DD. The basic form of legacy BF_TX, YYYYMMDD, is valid too.
+type ISODate string
+
+// DateFormat is the ISO 8601 form of an ISODate, as a time layout.
+const DateFormat = "2006-01-02"
+
+// legacyDateFormat is the basic ISO 8601 form of the dates of a legacy BF_TX
+const legacyDateFormat = "20060102"
+
+// NewDate returns the ISODate of a time.
+func NewDate(t time.Time) ISODate {
+	return ISODate(t.Format(DateFormat))
+}
+
+// Time returns the start of an ISODate, in UTC.
+func (date ISODate) Time() (time.Time, error) {
+	if t, err := time.Parse(DateFormat, string(date)); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(legacyDateFormat, string(date))
+	if err != nil {
+		return t, errors.New("Invalid date " + string(date) + ", it must be an ISO 8601 date like 2016-11-28.")
+	}
+	return t, nil
+}
+
+// Valid reports whether an ISODate is left out, or is an ISO 8601 date.
+func (date ISODate) Valid() bool {
+	if date == "" {
+		return true
+	}
+	_, err := date.Time()
+	return err == nil
+}
+
+// textValue returns the text of a JSON string, or of null, false if the JSON value is neither
+func textValue(data []byte) (string, bool, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", true, nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		return "", false, nil
+	}
+	var text string
+	err := json.Unmarshal(data, &text)
+	return text, true, err
+}
+
+// splitUnit splits the text form of a value and its unit at its last space. Text without a unit after a space is
+// all value, so any legacy string decodes to a value that encodes back to the same string.
+func splitUnit(text string) (string, string) {
+	i := strings.LastIndex(text, " ")
+	if i < 0 || i == len(text)-1 {
+		return text, ""
+	}
+	return text[:i], text[i+1:]
+}
+
+// joinUnit returns the text form of a value and its unit
+func joinUnit(value string, unit string) string {
+	if unit == "" {
+		return value
+	}
+	return value + " " + unit
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
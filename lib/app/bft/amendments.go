@@ -0,0 +1,218 @@
+// File: ./blockfreight/lib/bft/amendments.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"fmt"           // Implements formatted I/O with functions analogous to C's printf and scanf.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// AuthorityPrefix is the prefix of the state entries that hold who has authority over an issued BF_TX.
+const AuthorityPrefix string = "authority:"
+
+// Authority records who issued a BF_TX. The issuer, or any key of the issuing party, may amend it.
+type Authority struct {
+	Issuer []byte `json:"Issuer"` // go-wire encoded public key that signed the issue operation.
+	Party  string `json:"Party"`  // Id of the registered party the issuer key belongs to, if any.
+}
+
+// checkAmendment checks an amendment references the latest version of a committed BF_TX of the same bill of
+// lading, and that the role rules allow its signer to make it.
+func (app *BftApplication) checkAmendment(env bf_tx.Envelope, bftx bf_tx.BF_TX) (uint32, string) {
+	if bftx.Amendment == "" {
+		return CodeTypeValidationError, "BF_TX " + bftx.Id + " does not say which BF_TX it amends."
+	}
+	predecessor, ok := app.getBFTX(bftx.Amendment, 0)
+	if !ok {
+		return CodeTypeUnknownBFTX, "BF_TX " + bftx.Amendment + " does not exist."
+	}
+	if resCode, resLog := app.checkTransition(predecessor.Id, bf_tx.StateAmended); resCode != code.CodeTypeOK {
+		return resCode, resLog
+	}
+	if bftx.Properties.BolNum != predecessor.Properties.BolNum {
+		return CodeTypeValidationError, "BF_TX " + bftx.Id + " amends a different bill of lading."
+	}
+
+	if env.Signer == nil {
+		return CodeTypeUnsigned, "Amendment " + bftx.Id + " is not signed."
+	}
+	return app.authorizeAmendment(env, app.lineage(predecessor.Id, 0)[0], predecessor, bftx)
+}
+
+// setIssuer records the signer of an issue operation as the authority over the issued BF_TX
+func (app *BftApplication) setIssuer(id string, signer []byte) {
+	authority, _ := json.Marshal(Authority{Issuer: signer, Party: app.partyOfKey(signer)})
+	app.state.Set([]byte(AuthorityPrefix+id), authority)
+}
+
+// authority returns who has authority over an issued BF_TX
+func (app *BftApplication) authority(id string) (Authority, bool) {
+	var authority Authority
+	_, value := app.state.Get([]byte(AuthorityPrefix + id))
+	if value == nil || json.Unmarshal(value, &authority) != nil {
+		return authority, false
+	}
+	return authority, true
+}
+
+// hasAuthority reports whether a key may act on behalf of the issuer of a BF_TX
+func (app *BftApplication) hasAuthority(id string, signer []byte) bool {
+	authority, ok := app.authority(id)
+	if !ok {
+		return false
+	}
+
+	if hasKey([][]byte{authority.Issuer}, signer) {
+		return true
+	}
+	return authority.Party != "" && app.partyOfKey(signer) == authority.Party
+}
+
+// lineage returns the amendment lineage a BF_TX belongs to, from the original BF_TX to its latest amendment,
+// as it was at a state version. Version 0 reads the current state.
+func (app *BftApplication) lineage(id string, version uint64) []string {
+	// Walk back to the original through the BF_TX each one amends
+	lineage := []string{id}
+	for current, ok := app.getBFTX(id, version); ok && current.Amendment != ""; current, ok = app.getBFTX(current.Amendment, version) {
+		lineage = append([]string{current.Amendment}, lineage...)
+	}
+
+	// and forward to the latest amendment through the amendments index
+	for next := app.indexEntry([]byte(AmendmentsIndexPrefix+id), version); len(next) > 0; next = app.indexEntry([]byte(AmendmentsIndexPrefix+next[0]), version) {
+		lineage = append(lineage, next[0])
+	}
+	return lineage
+}
+
+// lineageQuery returns the Id a /lineage query asks for
+func lineageQuery(path string, data []byte) (string, bool) {
+	route := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if route[0] != "lineage" {
+		return "", false
+	}
+	if len(route) == 2 && route[1] != "" {
+		return route[1], true
+	}
+	return string(data), true
+}
+
+// queryLineage answers a /lineage query at a block height, 0 for the latest state
+func (app *BftApplication) queryLineage(id string, height int64) (resQuery types.ResponseQuery) {
+	resQuery.Key = []byte(id)
+	resQuery.Height = int64(app.state.LatestVersion())
+	if height != 0 {
+		if height < 0 || !app.state.VersionExists(uint64(height)) {
+			resQuery.Code = CodeTypeUnknownHeight
+			resQuery.Log = fmt.Sprintf("no state kept at height %d", height)
+			return
+		}
+		resQuery.Height = height
+	}
+
+	if _, ok := app.getBFTX(id, uint64(height)); !ok {
+		resQuery.Log = "does not exist"
+		return
+	}
+	resQuery.Value, _ = json.Marshal(app.lineage(id, uint64(height)))
+	resQuery.Log = "exists"
+	return
+}
+
+// getBFTX returns a committed BF_TX as it was at a state version. Version 0 reads the current state.
+func (app *BftApplication) getBFTX(id string, version uint64) (bf_tx.BF_TX, bool) {
+	var bftx bf_tx.BF_TX
+	if !strings.HasPrefix(id, "BFTX") {
+		return bftx, false
+	}
+	value := app.get([]byte(id), version)
+	if value == nil || json.Unmarshal(value, &bftx) != nil {
+		return bftx, false
+	}
+	return bftx, true
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
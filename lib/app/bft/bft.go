@@ -49,6 +49,9 @@ import (
 	"encoding/json"
 
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/app/params"
+	"github.com/blockfreight/go-bftx/lib/app/validator"
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
 	// =======================
 	// Golang Standard library
 	// =======================
@@ -58,8 +61,9 @@ import (
 	// Tendermint Core
 	// ===============
 
-	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/tendermint/abci/example/code"
 	"github.com/tendermint/abci/types"
@@ -69,109 +73,348 @@ import (
 	wire "github.com/tendermint/go-wire"
 )
 
+// lastBlockKey is the state key where the application keeps the height of the last committed block.
+// Writing it on every Commit keeps the tree non-empty, so a new version is saved for every block.
+var lastBlockKey = []byte("bftx/lastblock")
+
+// chainIDKey is the state key where the application keeps the Id of the chain it runs, which signed transactions
+// must name
+var chainIDKey = []byte("bftx/chainid")
+
 // BftApplication struct
 type BftApplication struct {
 	types.BaseApplication
 
 	state *iavl.VersionedTree
 
+	// database the state tree is kept in
+	db dbm.DB
+
 	blockHeader *types.Header
 
+	// number of recent state versions kept, 0 keeps every version
+	keepRecent int64
+
+	// oldest state version that may not be pruned yet, 0 before the first pruning
+	oldestVersion uint64
+
+	// Properties fields left out of the DeliverTx tags, keyed in lower case
+	encryptedFields map[string]bool
+
+	// parameters, parties and BF_TX records InitChain loads into the state
+	genesis GenesisState
+
+	// live parameters decoded from the state, until the next block or parameter change
+	liveParams *params.Params
+
+	// sequence of the last envelope of each signer admitted by CheckTx since the last Commit
+	mempoolSequences map[string]uint64
+
+	// usage of each account counting the transactions admitted by CheckTx since the last Commit
+	mempoolUsages map[string]Usage
+
 	// validator set
 	changes []*types.Validator
 }
 
 // NewBftApplication creates a new application
 func NewBftApplication() *BftApplication {
-	stateTree := iavl.NewVersionedTree(0, dbm.NewMemDB())
+	db := dbm.NewMemDB()
+	stateTree := iavl.NewVersionedTree(0, db)
 
 	return &BftApplication{
-		state: stateTree,
+		state:            stateTree,
+		db:               db,
+		genesis:          GenesisState{Params: params.Default()},
+		mempoolSequences: make(map[string]uint64),
+		mempoolUsages:    make(map[string]Usage),
 	}
 }
 
-// Info returns information
+// NewPersistentBftApplication creates a new application that keeps its state tree in a LevelDB database
+// inside dbDir. Every saved version is reloaded, so the application resumes from the last committed block.
+func NewPersistentBftApplication(dbDir string) (*BftApplication, error) {
+	db, err := dbm.NewGoLevelDB("bftx", dbDir)
+	if err != nil {
+		return nil, err
+	}
+
+	stateTree := iavl.NewVersionedTree(500, db)
+	if err := stateTree.Load(); err != nil {
+		return nil, err
+	}
+
+	return &BftApplication{
+		state:            stateTree,
+		db:               db,
+		genesis:          GenesisState{Params: params.Default()},
+		mempoolSequences: make(map[string]uint64),
+		mempoolUsages:    make(map[string]Usage),
+	}, nil
+}
+
+// Close closes the database of the state tree, so another application can open it. The versions saved by the
+// last Commit are kept, and the state of a block that was not committed is lost.
+func (app *BftApplication) Close() {
+	app.db.Close()
+}
+
+// Info returns information about the last committed block, so Tendermint can replay the missing ones on startup
 func (app *BftApplication) Info(req types.RequestInfo) (resInfo types.ResponseInfo) {
-	return types.ResponseInfo{Data: fmt.Sprintf(`{"size":%v}`, app.state.Size()), LastBlockAppHash: app.state.Hash()}
+	return types.ResponseInfo{
+		Data:             fmt.Sprintf(`{"size":%v}`, app.state.Size()),
+		LastBlockHeight:  int64(app.state.LatestVersion()),
+		LastBlockAppHash: app.state.Hash(),
+	}
 }
 
-// DeliverTx delivers transactions.Transactions are either "key=value" or just arbitrary bytes
+// DeliverTx delivers transactions. Validator set changes are applied to the validator set. Any other transaction
+// is a bf_tx.Envelope, applied according to its operation type: an issued or amended BF_TX is stored under its Id
+// together with the height and time of the block it was committed in, and added to the secondary indexes, and
+// operations on a committed BF_TX are added to its history.
+// Every transaction counts against the quotas of the account that signed it.
+// A transaction that does not pass its checks is rejected with an error code and leaves the state untouched.
 func (app *BftApplication) DeliverTx(tx []byte) types.ResponseDeliverTx {
-	var key, value []byte
-	parts := bytes.Split(tx, []byte("="))
-	if len(parts) == 2 {
-		key, value = parts[0], parts[1]
-	} else {
-		key, value = tx, tx
+	if isValidatorTx(tx) {
+		return app.deliverValidatorTx(tx)
 	}
-	app.state.Set(key, value)
 
+	op, resCode, resLog := app.checkTx(tx, app.sequence, app.usage)
+	if resCode != code.CodeTypeOK {
+		return types.ResponseDeliverTx{Code: resCode, Log: resLog}
+	}
+	return op.deliver(app)
+}
+
+// CheckTx checks a transaction before it is admitted to the mempool. The sequences of the signed envelopes it
+// admits, and the quota usage of their accounts, are tracked until the next Commit, so a signer can send several
+// of them in the same block but not more than its quotas allow.
+func (app *BftApplication) CheckTx(tx []byte) types.ResponseCheckTx {
+	if isValidatorTx(tx) {
+		_, resCode, resLog := app.checkValidatorTx(tx)
+		return types.ResponseCheckTx{Code: resCode, Log: resLog}
+	}
+
+	op, resCode, resLog := app.checkTx(tx, app.mempoolSequence, app.mempoolUsage)
+	if quota, ok := op.(quotaOp); ok {
+		app.mempoolUsages[quota.account] = quota.usage
+		op = quota.operation
+	}
+	if signed, ok := op.(sequencedOp); ok {
+		app.mempoolSequences[string(signed.env.Signer)] = signed.env.Sequence
+	}
+	return types.ResponseCheckTx{Code: resCode, Log: resLog}
+}
+
+// checkBFTX decodes a transaction and checks it is a well formed, valid and submitted BF_TX, signed with
+// the key of a registered party, with a new Id. It returns the BF_TX with the ABCI code and log to respond with.
+func (app *BftApplication) checkBFTX(tx []byte) (bf_tx.BF_TX, uint32, string) {
 	var bftx bf_tx.BF_TX
-	err := json.Unmarshal(tx, &bftx)
-	if err != nil {
-		// if this wasn't a dummy app, we'd do something smarter
-		panic(err)
+	if err := json.Unmarshal(tx, &bftx); err != nil {
+		return bftx, CodeTypeEncodingError, "Cannot decode BF_TX: " + err.Error()
+	}
+	if !strings.HasPrefix(bftx.Id, "BFTX") {
+		return bftx, CodeTypeEncodingError, "BF_TX has no valid Id."
+	}
+
+	// The typed values of the fields the BF_TX itself lists as encrypted are ciphertext, and not checked
+	if valid, msg := validator.ValidateFieldsExcept(bftx, bf_tx.EncryptedFields(bftx.Properties)); !valid {
+		return bftx, CodeTypeValidationError, "Invalid BF_TX: " + msg
+	}
+	if err := app.Params().CheckBFTX(bftx); err != nil {
+		return bftx, CodeTypeValidationError, "Invalid BF_TX: " + err.Error()
 	}
 
-	tags := []*types.KVPair{
-		{Key: "bftx.id", ValueType: types.KVPair_STRING, ValueString: bftx.Id},
+	if bftx.Signature == "" {
+		return bftx, CodeTypeUnsigned, "BF_TX " + bftx.Id + " is not signed."
+	}
+	if !crypto.VerifyBFTX(bftx) {
+		return bftx, CodeTypeBadSignature, "BF_TX " + bftx.Id + " does not match its signature."
+	}
+	if owner := app.revokedBy(bftx.Signer); owner != "" {
+		return bftx, CodeTypeRevokedKey, "BF_TX " + bftx.Id + " is signed with a key party " + owner + " revoked."
 	}
-	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+	if app.partyOfKey(bftx.Signer) == "" {
+		return bftx, CodeTypeUnauthorized, "BF_TX " + bftx.Id + " is not signed with the key of a registered party."
+	}
+	if state := bf_tx.State(bftx); state != bf_tx.StateSubmitted {
+		return bftx, CodeTypeNotTransmitted, "BF_TX " + bftx.Id + " is " + string(state) + ", only a Submitted BF_TX can be committed."
+	}
+
+	if app.state.Has([]byte(bftx.Id)) {
+		return bftx, CodeTypeDuplicateID, "BF_TX " + bftx.Id + " already exists."
+	}
+
+	return bftx, code.CodeTypeOK, ""
 }
 
-// CheckTx checks a transaction
-func (app *BftApplication) CheckTx(tx []byte) types.ResponseCheckTx {
-	return types.ResponseCheckTx{Code: code.CodeTypeOK}
+// BeginBlock records the header of the block being executed
+func (app *BftApplication) BeginBlock(req types.RequestBeginBlock) types.ResponseBeginBlock {
+	app.blockHeader = req.Header
+	app.liveParams = nil
+	// A chain started without the genesis file of the node takes its Id from its first block
+	if app.ChainID() == "" && req.Header != nil {
+		app.state.Set(chainIDKey, []byte(req.Header.ChainId))
+	}
+
+	// reset valset changes
+	app.changes = make([]*types.Validator, 0)
+	return types.ResponseBeginBlock{}
+}
+
+// EndBlock closes the block being executed and returns the validator set changes it made
+func (app *BftApplication) EndBlock(req types.RequestEndBlock) types.ResponseEndBlock {
+	return types.ResponseEndBlock{ValidatorUpdates: app.changes}
 }
 
 // Commit commits transactions
 func (app *BftApplication) Commit() types.ResponseCommit {
-	// Save a new version
-	var hash []byte
-	var err error
-
-	if app.state.Size() > 0 {
-		// just add one more to height (kind of arbitrarily stupid)
-		height := app.state.LatestVersion() + 1
-		hash, err = app.state.SaveVersion(height)
-		if err != nil {
-			// if this wasn't a dummy app, we'd do something smarter
+	// Save a new version for every block, so the tree version is always the block height
+	height := app.blockHeight()
+	app.state.Set(lastBlockKey, []byte(strconv.FormatInt(height, 10)))
+
+	hash, err := app.state.SaveVersion(uint64(height))
+	if err != nil {
+		// the state on disk no longer matches the chain, it is not safe to keep going
+		panic(err)
+	}
+	app.pruneVersions(height)
+	app.mempoolSequences = make(map[string]uint64)
+	app.mempoolUsages = make(map[string]Usage)
+
+	return types.ResponseCommit{Code: code.CodeTypeOK, Data: hash}
+}
+
+// ChainID returns the Id of the chain the application runs, set by InitChain from the genesis state
+func (app *BftApplication) ChainID() string {
+	_, value := app.state.Get(chainIDKey)
+	return string(value)
+}
+
+// blockHeight returns the height of the block being executed, as given by Tendermint in BeginBlock
+func (app *BftApplication) blockHeight() int64 {
+	if app.blockHeader == nil {
+		return int64(app.state.LatestVersion()) + 1
+	}
+	return app.blockHeader.Height
+}
+
+// get reads a state value at a version. Version 0 reads the current state.
+func (app *BftApplication) get(key []byte, version uint64) []byte {
+	if version == 0 {
+		_, value := app.state.Get(key)
+		return value
+	}
+	_, value := app.state.GetVersioned(key, version)
+	return value
+}
+
+// SetKeepRecent sets how many of the most recent state versions are kept. Older versions are deleted on Commit,
+// so historical queries can only go back that many blocks. Zero, the default, keeps every version.
+func (app *BftApplication) SetKeepRecent(keepRecent int64) {
+	app.keepRecent = keepRecent
+}
+
+// pruneVersions deletes every state version that has fallen out of the keep recent window, from the oldest one
+// still kept, e.g. after the window was narrowed or the node restarted with it set
+func (app *BftApplication) pruneVersions(height int64) {
+	if app.keepRecent <= 0 || height-app.keepRecent <= 0 {
+		return
+	}
+	cutoff := uint64(height - app.keepRecent)
+	if app.oldestVersion == 0 {
+		app.oldestVersion = 1
+	}
+	for ; app.oldestVersion <= cutoff; app.oldestVersion++ {
+		if !app.state.VersionExists(app.oldestVersion) {
+			continue
+		}
+		if err := app.state.DeleteVersion(app.oldestVersion); err != nil {
 			panic(err)
 		}
 	}
+}
 
-	return types.ResponseCommit{Code: code.CodeTypeOK, Data: hash}
+// blockTime returns the time of the block being executed, in seconds since the Unix epoch
+func (app *BftApplication) blockTime() int64 {
+	return app.blockHeader.GetTime()
 }
 
-//Query retrieves a transaction from the network
+// Query retrieves a BF_TX or a secondary index entry from the network. The path selects what to look up:
+// /bftx/<id>, /bol/<BolNum>, /container/<Container>, /shipper/<Shipper>, /amendments/<id>, /history/<id>,
+// /title/<id>, /party/<id>, /cosign/<id> or /nonce/<public key>. The value can be given in the path or in the query
+// data, where a public key is given as raw bytes. An empty path looks up the query data as a raw state key.
+// A non zero query height reads the state as it was committed at that block height.
+// /lineage/<id> returns the Ids of the amendment lineage of a BF_TX, from the original to the latest amendment,
+// without a proof; each of them can be proven with its own /bftx/<id> query. /genesis returns a genesis file
+// exported from the latest state, see GenesisDoc.
 func (app *BftApplication) Query(reqQuery types.RequestQuery) (resQuery types.ResponseQuery) {
-	if reqQuery.Prove {
-		value, proof, err := app.state.GetWithProof(reqQuery.Data)
-		// if this wasn't a dummy app, we'd do something smarter
-		if err != nil {
-			panic(err)
+	if id, ok := lineageQuery(reqQuery.Path, reqQuery.Data); ok {
+		return app.queryLineage(id, reqQuery.Height)
+	}
+	if reqQuery.Path == "/genesis" {
+		return app.queryGenesis()
+	}
+
+	key, err := queryKey(reqQuery.Path, reqQuery.Data)
+	if err != nil {
+		resQuery.Code = CodeTypeBadQuery
+		resQuery.Log = err.Error()
+		return
+	}
+	resQuery.Key = key
+
+	if reqQuery.Height != 0 {
+		// Historical query, answered from the version saved at that block height
+		version := uint64(reqQuery.Height)
+		if reqQuery.Height < 0 || !app.state.VersionExists(version) {
+			resQuery.Code = CodeTypeUnknownHeight
+			resQuery.Log = fmt.Sprintf("no state kept at height %d", reqQuery.Height)
+			return
 		}
-		resQuery.Index = -1 // TODO make Proof return index
-		resQuery.Key = reqQuery.Data
-		resQuery.Value = value
-		resQuery.Proof = wire.BinaryBytes(proof)
-		if value != nil {
-			resQuery.Log = "exists"
+		resQuery.Height = reqQuery.Height
+
+		if reqQuery.Prove {
+			value, proof, err := app.state.GetVersionedWithProof(key, version)
+			if err != nil {
+				resQuery.Code = CodeTypeBadQuery
+				resQuery.Log = err.Error()
+				return
+			}
+			resQuery.Index = -1 // TODO make Proof return index
+			resQuery.Value = value
+			resQuery.Proof = wire.BinaryBytes(proof)
 		} else {
-			resQuery.Log = "does not exist"
+			index, value := app.state.GetVersioned(key, version)
+			resQuery.Index = int64(index)
+			resQuery.Value = value
 		}
-		return
 	} else {
-		index, value := app.state.Get(reqQuery.Data)
-		resQuery.Index = int64(index)
-		resQuery.Value = value
-		if value != nil {
-			resQuery.Log = "exists"
+		resQuery.Height = int64(app.state.LatestVersion())
+
+		if reqQuery.Prove {
+			value, proof, err := app.state.GetWithProof(key)
+			if err != nil {
+				resQuery.Code = CodeTypeBadQuery
+				resQuery.Log = err.Error()
+				return
+			}
+			resQuery.Index = -1 // TODO make Proof return index
+			resQuery.Value = value
+			resQuery.Proof = wire.BinaryBytes(proof)
 		} else {
-			resQuery.Log = "does not exist"
+			index, value := app.state.Get(key)
+			resQuery.Index = int64(index)
+			resQuery.Value = value
 		}
-		return
 	}
+
+	if resQuery.Value != nil {
+		resQuery.Log = "exists"
+	} else {
+		resQuery.Log = "does not exist"
+	}
+	return
 }
 
 // =================================================
@@ -0,0 +1,108 @@
+// File: ./blockfreight/lib/bft/code.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+// Codes returned by CheckTx and DeliverTx when a transaction is rejected, and by Query when a query cannot be answered.
+// Zero (code.CodeTypeOK) means accepted.
+const (
+	CodeTypeEncodingError    uint32 = 1  // The transaction or its payload is not well formed JSON.
+	CodeTypeValidationError  uint32 = 2  // The BF_TX does not pass the validator rules.
+	CodeTypeUnsigned         uint32 = 3  // The BF_TX has not been signed.
+	CodeTypeNotTransmitted   uint32 = 4  // The BF_TX is not in the Submitted state of its lifecycle.
+	CodeTypeDuplicateID      uint32 = 5  // A BF_TX with the same Id already exists in the state.
+	CodeTypeBadSignature     uint32 = 6  // The transaction content does not match its signature.
+	CodeTypeUnauthorized     uint32 = 7  // The signer is not allowed to make this transaction.
+	CodeTypeBadQuery         uint32 = 8  // The query path is unknown or the state cannot answer it.
+	CodeTypeUnknownHeight    uint32 = 9  // No state is kept for the query height, it is in the future or was pruned.
+	CodeTypeUnknownOperation uint32 = 10 // The transaction envelope carries an unknown operation type.
+	CodeTypeUnknownBFTX      uint32 = 11 // The operation refers to a BF_TX that does not exist.
+	CodeTypeSuperseded       uint32 = 12 // The amended BF_TX has already been amended, only the latest version can be.
+	CodeTypeUnknownParty     uint32 = 13 // The operation refers to a party that is not registered.
+	CodeTypeSurrendered      uint32 = 14 // The bill of lading is surrendered, it can no longer be transferred.
+	CodeTypeAccomplished     uint32 = 15 // The bill of lading is accomplished, it can no longer be transferred nor amended.
+	CodeTypeIllegalState     uint32 = 16 // The lifecycle of the BF_TX does not allow the operation from its current state.
+	CodeTypeVoid             uint32 = 17 // The BF_TX is void, nothing more can be done with it.
+	CodeTypeRevokedKey       uint32 = 18 // The transaction or BF_TX is signed with a revoked party key.
+	CodeTypeNotAllowed       uint32 = 19 // The role rules do not allow the signer to make this operation.
+	CodeTypeFieldNotAllowed  uint32 = 20 // The role rules do not allow the signer to change a field the amendment changes.
+	CodeTypeBadNonce         uint32 = 21 // The envelope sequence is not the next one of its signer, it was already used or is ahead.
+	CodeTypeDuplicateCosign  uint32 = 22 // The party already co-signed the BF_TX.
+	CodeTypeTxTooLarge       uint32 = 23 // The transaction is larger than the MaxTxBytes parameter.
+	CodeTypeNotValidator     uint32 = 24 // The proposal or vote is not signed by a current validator.
+	CodeTypeUnknownProposal  uint32 = 25 // The vote refers to a proposal that does not exist.
+	CodeTypeProposalClosed   uint32 = 26 // The proposal already passed, failed or is past its voting period.
+	CodeTypeDuplicateVote    uint32 = 27 // The validator already voted for the proposal.
+	CodeTypeQuotaExceeded    uint32 = 28 // The account sent its quota of transactions or bytes for the window, it must back off.
+	CodeTypeWrongChain       uint32 = 29 // The transaction is signed for another chain than this one.
+)
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,227 @@
+// File: ./blockfreight/lib/bft/cosign.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"  // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto" // Provides useful functions to sign BF_TX.
+)
+
+// CosignPrefix is the prefix of the state entries that hold, for every BF_TX, the JSON list of the co-signatures
+// of its named parties.
+const CosignPrefix string = "cosign:"
+
+// Cosignature is the signature of a party named in a BF_TX over its document hash.
+type Cosignature struct {
+	Party     string `json:"Party"`
+	PubKey    []byte `json:"PubKey"`
+	Signature []byte `json:"Signature"`
+	Height    int64  `json:"Height"`
+}
+
+// Quorum returns the co-signature quorum the application enforces, its live Quorum parameter
+func (app *BftApplication) Quorum() int {
+	return app.Params().Quorum
+}
+
+// quorumOf returns the number of co-signatures a BF_TX needs, never more than the parties it names
+func (app *BftApplication) quorumOf(bftx bf_tx.BF_TX) int {
+	named := len(bf_tx.CoSigners(bftx))
+	if quorum := app.Quorum(); quorum > 0 && quorum < named {
+		return quorum
+	}
+	return named
+}
+
+// checkCoSigners checks the parties a BF_TX names to co-sign it are registered
+func (app *BftApplication) checkCoSigners(bftx bf_tx.BF_TX) (uint32, string) {
+	for _, id := range bf_tx.CoSigners(bftx) {
+		if _, ok := app.party(id); !ok {
+			return CodeTypeUnknownParty, "BF_TX " + bftx.Id + " names party " + id + " to co-sign it, it is not registered."
+		}
+	}
+	return code.CodeTypeOK, ""
+}
+
+// cosignOp adds the co-signature of a named party to a BF_TX
+type cosignOp struct {
+	env         bf_tx.Envelope
+	original    string
+	id          string
+	cosignature Cosignature
+}
+
+// checkCosign checks a co-signature is made by a party named in a pending or committed BF_TX, with the key that
+// signs the envelope, over its document hash, and that the party did not co-sign it yet
+func (app *BftApplication) checkCosign(env bf_tx.Envelope) (operation, uint32, string) {
+	var cosign bf_tx.Cosign
+	if err := json.Unmarshal(env.Payload, &cosign); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode co-signature: " + err.Error()
+	}
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Co-signature of BF_TX " + cosign.Id + " is not signed."
+	}
+	bftx, ok := app.getBFTX(cosign.Id, 0)
+	if !ok {
+		return nil, CodeTypeUnknownBFTX, "BF_TX " + cosign.Id + " does not exist."
+	}
+	if state := bf_tx.State(bftx); state != bf_tx.StatePending && state != bf_tx.StateCommitted {
+		return nil, CodeTypeIllegalState, "BF_TX " + cosign.Id + " is " + string(state) + ", it can no longer be co-signed."
+	}
+
+	party := app.partyOfKey(env.Signer)
+	named := false
+	for _, id := range bf_tx.CoSigners(bftx) {
+		named = named || id == party
+	}
+	if party == "" || !named {
+		return nil, CodeTypeNotAllowed, "BF_TX " + cosign.Id + " does not name the party of the signer to co-sign it."
+	}
+	for _, cosignature := range app.cosignatures(cosign.Id) {
+		if cosignature.Party == party {
+			return nil, CodeTypeDuplicateCosign, "Party " + party + " already co-signed BF_TX " + cosign.Id + "."
+		}
+	}
+	if !crypto.VerifyCosignature(bftx, env.Signer, cosign.Signature) {
+		return nil, CodeTypeBadSignature, "Co-signature of BF_TX " + cosign.Id + " does not match its document hash."
+	}
+
+	original := app.lineage(cosign.Id, 0)[0]
+	if resCode, resLog := app.authorize(env, env.Signer, original, bftx); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+
+	cosignature := Cosignature{Party: party, PubKey: env.Signer, Signature: cosign.Signature}
+	return cosignOp{env, original, cosign.Id, cosignature}, code.CodeTypeOK, ""
+}
+
+func (op cosignOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	cosignature := op.cosignature
+	cosignature.Height = app.blockHeight()
+	cosignatures := append(app.cosignatures(op.id), cosignature)
+	value, _ := json.Marshal(cosignatures)
+	app.state.Set(cosignKey(op.id), value)
+	app.addHistory(op.original, op.env, op.env.Payload)
+
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: op.id},
+		{Key: TagParty, ValueType: types.KVPair_STRING, ValueString: cosignature.Party},
+		opTag(op.env.Type),
+	}
+
+	// The BF_TX is issued, or the amendment takes effect, once the quorum of its named parties co-signed it
+	bftx, _ := app.getBFTX(op.id, 0)
+	if bf_tx.State(bftx) == bf_tx.StatePending && len(cosignatures) >= app.quorumOf(bftx) {
+		state := app.settled(op.original)
+		app.setState(op.id, state)
+		tags = append(tags, stateTag(state))
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// settled returns the state of a bill of lading, by the Id of its original BF_TX, once it no longer waits for
+// co-signatures: Transferred if it was endorsed over, Committed otherwise
+func (app *BftApplication) settled(original string) bf_tx.Lifecycle {
+	if title, ok := app.title(original); ok && len(title.Endorsements) > 0 {
+		return bf_tx.StateTransferred
+	}
+	return bf_tx.StateCommitted
+}
+
+// cosignatures returns the co-signatures a BF_TX collected
+func (app *BftApplication) cosignatures(id string) []Cosignature {
+	var cosignatures []Cosignature
+	if _, value := app.state.Get(cosignKey(id)); value != nil {
+		json.Unmarshal(value, &cosignatures)
+	}
+	return cosignatures
+}
+
+func cosignKey(id string) []byte {
+	return []byte(CosignPrefix + id)
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,478 @@
+// File: ./blockfreight/lib/bft/genesis.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/hex"  // Implements hexadecimal encoding and decoding.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"reflect"       // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"sort"          // Provides primitives for sorting slices and user-defined collections.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+	"time"          // Provides functionality for measuring and displaying time.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"     // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+	"github.com/blockfreight/go-bftx/lib/app/params"    // Defines the parameters of the Blockfreight™ Network.
+	"github.com/blockfreight/go-bftx/lib/app/validator" // Provides functions to assure the input JSON is correct.
+	"github.com/blockfreight/go-bftx/lib/pkg/common"    // Implements common functions for Blockfreight™
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto"    // Provides useful functions to sign BF_TX.
+)
+
+// GenesisState is the app_state section of a genesis file: the parameters, parties, BF_TX records and the rest of
+// the state a new chain starts with, so historical bills of lading do not have to be broadcast one at a time.
+type GenesisState struct {
+	ChainID           string                    `json:"-"`                           // Id of the chain, from the genesis file.
+	Params            params.Params             `json:"Params"`                      // Parameters, params.Default for the ones left out.
+	Parties           []bf_tx.Party             `json:"Parties,omitempty"`           // Registered parties, with their keys and revoked keys.
+	BFTX              []bf_tx.BF_TX             `json:"BFTX,omitempty"`              // BF_TX records as committed on the network.
+	Titles            map[string]Title          `json:"Titles,omitempty"`            // Titles by original BF_TX Id, every original needs one.
+	Authorities       map[string]Authority      `json:"Authorities,omitempty"`       // Authorities by original BF_TX Id, every original needs one.
+	Cosignatures      map[string][]Cosignature  `json:"Cosignatures,omitempty"`      // Co-signatures by BF_TX Id.
+	History           map[string][]HistoryEntry `json:"History,omitempty"`           // Operations applied, by BF_TX Id.
+	Nonces            map[string]uint64         `json:"Nonces,omitempty"`            // Sequence of the last envelope signed with a key, by hex encoded go-wire public key.
+	Quotas            map[string]Usage          `json:"Quotas,omitempty"`            // Usage of the accounts in their last quota window.
+	Proposals         []Proposal                `json:"Proposals,omitempty"`         // Parameter proposals, numbered from 1.
+	ValidatorSequence uint64                    `json:"ValidatorSequence,omitempty"` // Sequence of the last validator set change applied.
+}
+
+// GenesisDoc is a Tendermint genesis file with the app_state section the Blockfreight™ node loads.
+type GenesisDoc struct {
+	GenesisTime time.Time          `json:"genesis_time"`
+	ChainID     string             `json:"chain_id"`
+	Validators  []GenesisValidator `json:"validators"`
+	AppHash     string             `json:"app_hash"`
+	AppState    GenesisState       `json:"app_state"`
+}
+
+// GenesisValidator is a validator of a genesis file.
+type GenesisValidator struct {
+	PubKey tmcrypto.PubKey `json:"pub_key"`
+	Power  int64           `json:"power"`
+	Name   string          `json:"name"`
+}
+
+// LoadGenesisState reads the app_state section of a genesis file, and its chain Id.
+func LoadGenesisState(path string) (GenesisState, error) {
+	var genesis struct {
+		ChainID  string       `json:"chain_id"`
+		AppState GenesisState `json:"app_state"`
+	}
+	genesis.AppState.Params = params.Default()
+	file, err := common.ReadJSON(path)
+	if err != nil {
+		return genesis.AppState, err
+	}
+	if err := json.Unmarshal(file, &genesis); err != nil {
+		return genesis.AppState, err
+	}
+	genesis.AppState.ChainID = genesis.ChainID
+	return genesis.AppState, genesis.AppState.Params.Validate()
+}
+
+// SetGenesisState sets the genesis state InitChain loads into the state of a new chain. Parameters set with
+// SetParams afterwards replace its own.
+func (app *BftApplication) SetGenesisState(genesis GenesisState) {
+	app.genesis = genesis
+}
+
+// loadGenesis loads the genesis state into the state, and checks its records refer to each other consistently
+func (app *BftApplication) loadGenesis() error {
+	for _, party := range app.genesis.Parties {
+		if err := app.loadParty(party); err != nil {
+			return err
+		}
+	}
+	for _, party := range app.genesis.Parties {
+		if party.Principal != "" && !app.state.Has(partyKey(party.Principal)) {
+			return errors.New("Principal " + party.Principal + " of genesis party " + party.Id + " does not exist.")
+		}
+	}
+
+	for _, bftx := range app.genesis.BFTX {
+		if err := app.loadBFTX(bftx); err != nil {
+			return err
+		}
+	}
+	for _, bftx := range app.genesis.BFTX {
+		if bftx.Amendment != "" && !app.hasBFTX(bftx.Amendment) {
+			return errors.New("Genesis BF_TX " + bftx.Id + " amends " + bftx.Amendment + ", which does not exist.")
+		}
+	}
+	if err := app.checkLineages(); err != nil {
+		return err
+	}
+
+	for _, original := range sortedKeys(app.genesis.Titles) {
+		if !app.isOriginal(original) {
+			return errors.New("Genesis title " + original + " is not the title of an original BF_TX.")
+		}
+		app.setTitle(original, app.genesis.Titles[original])
+	}
+	for _, original := range sortedKeys(app.genesis.Authorities) {
+		authority := app.genesis.Authorities[original]
+		if !app.isOriginal(original) {
+			return errors.New("Genesis authority " + original + " is not the authority over an original BF_TX.")
+		}
+		if _, err := tmcrypto.PubKeyFromBytes(authority.Issuer); err != nil {
+			return errors.New("Invalid issuer key of genesis authority " + original + ": " + err.Error())
+		}
+		if authority.Party != "" && !app.state.Has(partyKey(authority.Party)) {
+			return errors.New("Party " + authority.Party + " of genesis authority " + original + " does not exist.")
+		}
+		value, _ := json.Marshal(authority)
+		app.state.Set([]byte(AuthorityPrefix+original), value)
+	}
+	// The issuer and holder of an original are never derived from the signer of the BF_TX
+	for _, bftx := range app.genesis.BFTX {
+		if bftx.Amendment == "" && (!app.state.Has([]byte(TitlePrefix+bftx.Id)) || !app.state.Has([]byte(AuthorityPrefix+bftx.Id))) {
+			return errors.New("Genesis BF_TX " + bftx.Id + " is an original without its title and authority.")
+		}
+	}
+	return app.loadRecords()
+}
+
+// checkLineages checks no genesis BF_TX is amended twice, and every one goes back to an original through the BF_TX
+// it amends, so the lineage of a bill of lading is a chain from its original and lineage always ends
+func (app *BftApplication) checkLineages() error {
+	amends := make(map[string]string, len(app.genesis.BFTX))
+	amendedBy := make(map[string]string)
+	for _, bftx := range app.genesis.BFTX {
+		amends[bftx.Id] = bftx.Amendment
+		if bftx.Amendment == "" {
+			continue
+		}
+		if other, ok := amendedBy[bftx.Amendment]; ok {
+			return errors.New("Genesis BF_TX " + bftx.Amendment + " is amended by both " + other + " and " + bftx.Id + ".")
+		}
+		amendedBy[bftx.Amendment] = bftx.Id
+	}
+	for _, bftx := range app.genesis.BFTX {
+		id := bftx.Id
+		for steps := 0; amends[id] != ""; steps++ {
+			if steps == len(amends) {
+				return errors.New("Genesis BF_TX " + bftx.Id + " has no original, its amendments go round in a cycle.")
+			}
+			id = amends[id]
+		}
+	}
+	return nil
+}
+
+// loadRecords loads the co-signatures, histories, nonces, quotas, proposals and validator set change sequence of
+// the genesis state. Entries are set in the order of their keys, the state tree depends on the order of its writes.
+func (app *BftApplication) loadRecords() error {
+	for _, id := range sortedKeys(app.genesis.Cosignatures) {
+		if !app.hasBFTX(id) {
+			return errors.New("Genesis co-signatures of " + id + " are not the co-signatures of a BF_TX.")
+		}
+		value, _ := json.Marshal(app.genesis.Cosignatures[id])
+		app.state.Set(cosignKey(id), value)
+	}
+	for _, id := range sortedKeys(app.genesis.History) {
+		if !app.hasBFTX(id) {
+			return errors.New("Genesis history of " + id + " is not the history of a BF_TX.")
+		}
+		value, _ := json.Marshal(app.genesis.History[id])
+		app.state.Set([]byte(HistoryIndexPrefix+id), value)
+	}
+	for _, key := range sortedKeys(app.genesis.Nonces) {
+		pubKey, err := hex.DecodeString(key)
+		if err == nil {
+			_, err = tmcrypto.PubKeyFromBytes(pubKey)
+		}
+		if err != nil {
+			return errors.New("Invalid key of genesis nonce " + key + ": " + err.Error())
+		}
+		app.state.Set(nonceKey(pubKey), []byte(strconv.FormatUint(app.genesis.Nonces[key], 10)))
+	}
+	for _, account := range sortedKeys(app.genesis.Quotas) {
+		value, _ := json.Marshal(app.genesis.Quotas[account])
+		app.state.Set([]byte(QuotaPrefix+account), value)
+	}
+
+	var count uint64
+	for _, proposal := range app.genesis.Proposals {
+		id, err := strconv.ParseUint(proposal.Id, 10, 64)
+		if err != nil || id == 0 || app.state.Has([]byte(ProposalPrefix+proposal.Id)) {
+			return errors.New("Genesis proposal " + proposal.Id + " has no valid Id, or is listed twice.")
+		}
+		if id > count {
+			count = id
+		}
+		value, _ := json.Marshal(proposal)
+		app.state.Set([]byte(ProposalPrefix+proposal.Id), value)
+	}
+	if count > 0 {
+		app.state.Set(proposalCountKey, []byte(strconv.FormatUint(count, 10)))
+	}
+	if app.genesis.ValidatorSequence > 0 {
+		app.state.Set(validatorSequenceKey, []byte(strconv.FormatUint(app.genesis.ValidatorSequence, 10)))
+	}
+	return nil
+}
+
+// isOriginal reports whether a BF_TX with that Id is committed and amends no other
+func (app *BftApplication) isOriginal(id string) bool {
+	bftx, ok := app.getBFTX(id, 0)
+	return ok && bftx.Amendment == ""
+}
+
+// sortedKeys returns the keys of a genesis map in order
+func sortedKeys(genesisMap interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(genesisMap).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// loadParty registers a genesis party, with its keys and revoked keys
+func (app *BftApplication) loadParty(party bf_tx.Party) error {
+	if party.Id == "" || reservedAccount(party.Id) || party.Name == "" || !validRole(party.Role) {
+		return errors.New("Genesis party " + party.Id + " needs an Id that is not reserved, a Name and a known Role.")
+	}
+	if len(party.PubKeys) == 0 {
+		return errors.New("Genesis party " + party.Id + " has no public key.")
+	}
+	if app.state.Has(partyKey(party.Id)) {
+		return errors.New("Genesis party " + party.Id + " is listed twice.")
+	}
+	for _, pubKey := range append(append([][]byte{}, party.PubKeys...), party.Revoked...) {
+		if _, err := tmcrypto.PubKeyFromBytes(pubKey); err != nil {
+			return errors.New("Invalid public key of genesis party " + party.Id + ": " + err.Error())
+		}
+		if resCode, resLog := app.checkNewKey(party.Id, pubKey); resCode != code.CodeTypeOK {
+			return errors.New(resLog)
+		}
+	}
+
+	app.setParty(party)
+	for _, pubKey := range party.PubKeys {
+		app.state.Set(append([]byte(PartyKeyPrefix), pubKey...), []byte(party.Id))
+	}
+	for _, pubKey := range party.Revoked {
+		app.state.Set(append([]byte(RevokedKeyPrefix), pubKey...), []byte(party.Id))
+	}
+	return nil
+}
+
+// loadBFTX writes a genesis BF_TX record as it was committed. A record must be signed with the key of a genesis
+// party, revoked or not: the BF_TX signed by earlier releases, with a throwaway key and no Signer, cannot be told
+// from a forgery, and are imported once the party that stands for them signed them again.
+func (app *BftApplication) loadBFTX(bftx bf_tx.BF_TX) error {
+	if !strings.HasPrefix(bftx.Id, "BFTX") {
+		return errors.New("Genesis BF_TX " + bftx.Id + " has no valid Id.")
+	}
+	if valid, msg := validator.ValidateFields(bftx); !valid {
+		return errors.New("Invalid genesis BF_TX " + bftx.Id + ": " + msg)
+	}
+	if bftx.Signature == "" {
+		return errors.New("Genesis BF_TX " + bftx.Id + " is not signed.")
+	}
+	if !crypto.VerifyBFTX(bftx) {
+		return errors.New("Genesis BF_TX " + bftx.Id + " does not match its signature.")
+	}
+	if app.partyOfKey(bftx.Signer) == "" && app.revokedBy(bftx.Signer) == "" {
+		return errors.New("Genesis BF_TX " + bftx.Id + " is not signed with the key of a genesis party.")
+	}
+	if app.state.Has([]byte(bftx.Id)) {
+		return errors.New("Genesis BF_TX " + bftx.Id + " is listed twice.")
+	}
+
+	// A record from before the network has no state on it yet
+	switch bf_tx.State(bftx) {
+	case bf_tx.StateDraft, bf_tx.StateSigned, bf_tx.StateSubmitted:
+		bftx.Lifecycle = bf_tx.StateCommitted
+	}
+	record, err := json.Marshal(bftx)
+	if err != nil {
+		return err
+	}
+	app.state.Set([]byte(bftx.Id), record)
+	app.indexBFTX(bftx)
+	return nil
+}
+
+// exportGenesis returns the chain Id, validators and genesis state of the current state, as a genesis file
+// without its time
+func (app *BftApplication) exportGenesis() GenesisDoc {
+	genesis := GenesisDoc{ChainID: app.ChainID(), AppState: GenesisState{
+		Params:            app.Params(),
+		Titles:            map[string]Title{},
+		Authorities:       map[string]Authority{},
+		Cosignatures:      map[string][]Cosignature{},
+		History:           map[string][]HistoryEntry{},
+		Nonces:            map[string]uint64{},
+		Quotas:            map[string]Usage{},
+		ValidatorSequence: app.validatorSequence(),
+	}}
+	for _, v := range app.Validators() {
+		if pubKey, err := tmcrypto.PubKeyFromBytes(v.PubKey); err == nil {
+			genesis.Validators = append(genesis.Validators, GenesisValidator{PubKey: pubKey, Power: v.Power})
+		}
+	}
+
+	app.state.IterateRange([]byte(PartyPrefix), prefixEnd([]byte(PartyPrefix)), true, func(key, value []byte) bool {
+		var party bf_tx.Party
+		if json.Unmarshal(value, &party) == nil {
+			genesis.AppState.Parties = append(genesis.AppState.Parties, party)
+		}
+		return false
+	})
+	app.state.IterateRange([]byte("BFTX"), prefixEnd([]byte("BFTX")), true, func(key, value []byte) bool {
+		var bftx bf_tx.BF_TX
+		if json.Unmarshal(value, &bftx) == nil {
+			genesis.AppState.BFTX = append(genesis.AppState.BFTX, bftx)
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(TitlePrefix), prefixEnd([]byte(TitlePrefix)), true, func(key, value []byte) bool {
+		var title Title
+		if json.Unmarshal(value, &title) == nil {
+			genesis.AppState.Titles[strings.TrimPrefix(string(key), TitlePrefix)] = title
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(AuthorityPrefix), prefixEnd([]byte(AuthorityPrefix)), true, func(key, value []byte) bool {
+		var authority Authority
+		if json.Unmarshal(value, &authority) == nil {
+			genesis.AppState.Authorities[strings.TrimPrefix(string(key), AuthorityPrefix)] = authority
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(CosignPrefix), prefixEnd([]byte(CosignPrefix)), true, func(key, value []byte) bool {
+		var cosignatures []Cosignature
+		if json.Unmarshal(value, &cosignatures) == nil {
+			genesis.AppState.Cosignatures[strings.TrimPrefix(string(key), CosignPrefix)] = cosignatures
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(HistoryIndexPrefix), prefixEnd([]byte(HistoryIndexPrefix)), true, func(key, value []byte) bool {
+		var history []HistoryEntry
+		if json.Unmarshal(value, &history) == nil {
+			genesis.AppState.History[strings.TrimPrefix(string(key), HistoryIndexPrefix)] = history
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(NoncePrefix), prefixEnd([]byte(NoncePrefix)), true, func(key, value []byte) bool {
+		if sequence, err := strconv.ParseUint(string(value), 10, 64); err == nil {
+			genesis.AppState.Nonces[hex.EncodeToString(key[len(NoncePrefix):])] = sequence
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(QuotaPrefix), prefixEnd([]byte(QuotaPrefix)), true, func(key, value []byte) bool {
+		var usage Usage
+		if json.Unmarshal(value, &usage) == nil {
+			genesis.AppState.Quotas[strings.TrimPrefix(string(key), QuotaPrefix)] = usage
+		}
+		return false
+	})
+	app.state.IterateRange([]byte(ProposalPrefix), prefixEnd([]byte(ProposalPrefix)), true, func(key, value []byte) bool {
+		var proposal Proposal
+		if json.Unmarshal(value, &proposal) == nil {
+			genesis.AppState.Proposals = append(genesis.AppState.Proposals, proposal)
+		}
+		return false
+	})
+	return genesis
+}
+
+// queryGenesis answers a /genesis query with the genesis file exported from the latest state, without a proof
+func (app *BftApplication) queryGenesis() (resQuery types.ResponseQuery) {
+	resQuery.Height = int64(app.state.LatestVersion())
+	value, err := json.Marshal(app.exportGenesis())
+	if err != nil {
+		resQuery.Code = CodeTypeEncodingError
+		resQuery.Log = err.Error()
+		return
+	}
+	resQuery.Value = value
+	resQuery.Log = "exists"
+	return
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,260 @@
+// File: ./blockfreight/lib/bft/governance.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// ProposalPrefix is the prefix of the state entries that hold the parameter proposals, under their Id.
+const ProposalPrefix string = "proposal:"
+
+// proposalCountKey is the state key where the application keeps the number of proposals made, the Id of the last one
+var proposalCountKey = []byte("bftx/proposals")
+
+// Tags emitted by DeliverTx for the proposals and votes
+const (
+	TagProposal       string = "proposal.id"
+	TagProposalStatus string = "proposal.status"
+)
+
+// Statuses of a proposal. An Open proposal past its Deadline is expired and no longer takes votes.
+const (
+	ProposalOpen   = "Open"
+	ProposalPassed = "Passed" // Its changes were applied to the parameters.
+	ProposalFailed = "Failed" // It passed, but its changes no longer validated against the parameters.
+)
+
+// Proposal is a change of parameters of the network, and the validators that voted for it.
+type Proposal struct {
+	Id          string                     `json:"Id"`
+	Proposer    []byte                     `json:"Proposer"`
+	Changes     map[string]json.RawMessage `json:"Changes"`
+	Description string                     `json:"Description"`
+	Height      int64                      `json:"Height"`   // Height of the block the proposal was made in.
+	Deadline    int64                      `json:"Deadline"` // Last block height it takes votes at.
+	Votes       [][]byte                   `json:"Votes"`    // go-wire encoded public keys of the validators that voted for it.
+	Status      string                     `json:"Status"`
+	Log         string                     `json:"Log,omitempty"` // Why a Failed proposal could not be applied.
+}
+
+// proposalOp records a new proposal, with the vote of its proposer
+type proposalOp struct {
+	env      bf_tx.Envelope
+	proposal bf_tx.ParamProposal
+}
+
+// checkProposeParams checks a proposal is made by a validator, and its changes validate against the live parameters
+func (app *BftApplication) checkProposeParams(env bf_tx.Envelope) (operation, uint32, string) {
+	var proposal bf_tx.ParamProposal
+	if err := json.Unmarshal(env.Payload, &proposal); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode proposal: " + err.Error()
+	}
+	if resCode, resLog := app.checkValidatorSigner(env); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if len(proposal.Changes) == 0 {
+		return nil, CodeTypeValidationError, "Proposal changes no parameter."
+	}
+	if _, err := app.Params().Apply(proposal.Changes); err != nil {
+		return nil, CodeTypeValidationError, "Invalid proposal: " + err.Error()
+	}
+	return proposalOp{env, proposal}, code.CodeTypeOK, ""
+}
+
+func (op proposalOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	_, count := app.state.Get(proposalCountKey)
+	last, _ := strconv.ParseUint(string(count), 10, 64)
+	id := strconv.FormatUint(last+1, 10)
+	app.state.Set(proposalCountKey, []byte(id))
+
+	proposal := Proposal{
+		Id:          id,
+		Proposer:    op.env.Signer,
+		Changes:     op.proposal.Changes,
+		Description: op.proposal.Description,
+		Height:      app.blockHeight(),
+		Deadline:    app.blockHeight() + app.Params().VotingPeriod,
+		Votes:       [][]byte{op.env.Signer},
+		Status:      ProposalOpen,
+	}
+	return app.tally(proposal, op.env)
+}
+
+// voteOp adds the vote of a validator to an open proposal
+type voteOp struct {
+	env      bf_tx.Envelope
+	proposal Proposal
+}
+
+// checkVote checks a vote is made by a validator that did not vote yet, for an open proposal
+func (app *BftApplication) checkVote(env bf_tx.Envelope) (operation, uint32, string) {
+	var vote bf_tx.Vote
+	if err := json.Unmarshal(env.Payload, &vote); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode vote: " + err.Error()
+	}
+	if resCode, resLog := app.checkValidatorSigner(env); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	proposal, ok := app.proposal(vote.Proposal)
+	if !ok {
+		return nil, CodeTypeUnknownProposal, "Proposal " + vote.Proposal + " does not exist."
+	}
+	if proposal.Status != ProposalOpen {
+		return nil, CodeTypeProposalClosed, "Proposal " + proposal.Id + " already " + proposal.Status + "."
+	}
+	if app.blockHeight() > proposal.Deadline {
+		return nil, CodeTypeProposalClosed, "Proposal " + proposal.Id + " expired at height " + strconv.FormatInt(proposal.Deadline, 10) + "."
+	}
+	if hasKey(proposal.Votes, env.Signer) {
+		return nil, CodeTypeDuplicateVote, "The signer already voted for proposal " + proposal.Id + "."
+	}
+	return voteOp{env, proposal}, code.CodeTypeOK, ""
+}
+
+func (op voteOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	proposal := op.proposal
+	proposal.Votes = append(proposal.Votes, op.env.Signer)
+	return app.tally(proposal, op.env)
+}
+
+// checkValidatorSigner checks an envelope is signed by a current validator
+func (app *BftApplication) checkValidatorSigner(env bf_tx.Envelope) (uint32, string) {
+	if env.Signer == nil {
+		return CodeTypeUnsigned, "Operation " + string(env.Type) + " is not signed."
+	}
+	if !app.state.Has(validatorKey(env.Signer)) {
+		return CodeTypeNotValidator, "Operation " + string(env.Type) + " must be signed by a current validator."
+	}
+	return code.CodeTypeOK, ""
+}
+
+// tally counts the voting power of the validators that voted for a proposal, applies its changes to the parameters
+// once they hold the VoteThreshold of the power, and stores it
+func (app *BftApplication) tally(proposal Proposal, env bf_tx.Envelope) types.ResponseDeliverTx {
+	var power, total int64
+	for _, v := range app.Validators() {
+		total += v.Power
+		if hasKey(proposal.Votes, v.PubKey) {
+			power += v.Power
+		}
+	}
+
+	live := app.Params()
+	if total > 0 && power*100 >= int64(live.VoteThreshold)*total {
+		proposal.Status = ProposalPassed
+		changed, err := live.Apply(proposal.Changes)
+		if err == nil {
+			err = app.setParams(changed)
+		}
+		if err != nil {
+			proposal.Status = ProposalFailed
+			proposal.Log = err.Error()
+		}
+	}
+	value, _ := json.Marshal(proposal)
+	app.state.Set([]byte(ProposalPrefix+proposal.Id), value)
+
+	tags := []*types.KVPair{
+		{Key: TagProposal, ValueType: types.KVPair_STRING, ValueString: proposal.Id},
+		{Key: TagProposalStatus, ValueType: types.KVPair_STRING, ValueString: proposal.Status},
+		opTag(env.Type),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// proposal returns a proposal by its Id
+func (app *BftApplication) proposal(id string) (Proposal, bool) {
+	var proposal Proposal
+	_, value := app.state.Get([]byte(ProposalPrefix + id))
+	if value == nil || json.Unmarshal(value, &proposal) != nil {
+		return proposal, false
+	}
+	return proposal, true
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
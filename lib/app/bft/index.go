@@ -0,0 +1,185 @@
+// File: ./blockfreight/lib/bft/index.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// Prefixes of the secondary index entries kept in the state. Each entry holds the JSON list of the Ids
+// of the BF_TX that have that value, so a lookup by any of them can be proven like a lookup by Id.
+const (
+	BolIndexPrefix        string = "bol:"
+	ContainerIndexPrefix  string = "container:"
+	ShipperIndexPrefix    string = "shipper:"
+	AmendmentsIndexPrefix string = "amendments:"
+)
+
+// queryRoutes maps the first element of a query path to the prefix of the state keys it looks up
+var queryRoutes = map[string]string{
+	"bftx":       "",
+	"bol":        BolIndexPrefix,
+	"container":  ContainerIndexPrefix,
+	"shipper":    ShipperIndexPrefix,
+	"amendments": AmendmentsIndexPrefix,
+	"history":    HistoryIndexPrefix,
+	"party":      PartyPrefix,
+	"title":      TitlePrefix,
+	"nonce":      NoncePrefix,
+	"cosign":     CosignPrefix,
+	"params":     ParamsPrefix,
+	"proposal":   ProposalPrefix,
+	"quota":      QuotaPrefix,
+}
+
+// queryKeys maps the query paths that look up a single state entry to its key
+var queryKeys = map[string][]byte{
+	"/chain_id":            chainIDKey,
+	"/validators/sequence": validatorSequenceKey,
+}
+
+// queryKey returns the state key a query looks up
+func queryKey(path string, data []byte) ([]byte, error) {
+	if path == "" {
+		return data, nil
+	}
+	if key, ok := queryKeys[path]; ok {
+		return key, nil
+	}
+
+	route := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	prefix, ok := queryRoutes[route[0]]
+	if !ok {
+		return nil, errors.New("Unknown query path " + path)
+	}
+
+	value := data
+	if len(route) == 2 && route[1] != "" {
+		value = []byte(route[1])
+	}
+	if len(value) == 0 {
+		return nil, errors.New("Query path " + path + " needs a value to look up")
+	}
+
+	return append([]byte(prefix), value...), nil
+}
+
+// indexBFTX adds a delivered BF_TX to the secondary indexes
+func (app *BftApplication) indexBFTX(bftx bf_tx.BF_TX) {
+	app.addToIndex(BolIndexPrefix, bftx.Properties.BolNum, bftx.Id)
+	for _, container := range bf_tx.ContainerNumbers(bftx.Properties) {
+		app.addToIndex(ContainerIndexPrefix, container, bftx.Id)
+	}
+	app.addToIndex(ShipperIndexPrefix, bftx.Properties.Shipper.Ref(), bftx.Id)
+	if bftx.Amendment != "" {
+		app.addToIndex(AmendmentsIndexPrefix, bftx.Amendment, bftx.Id)
+	}
+}
+
+// addToIndex appends an Id to the index entry of a value. Empty values are not indexed.
+func (app *BftApplication) addToIndex(prefix string, value string, id string) {
+	if value == "" {
+		return
+	}
+	key := []byte(prefix + value)
+
+	ids := app.indexEntry(key, 0)
+	for _, existing := range ids {
+		if existing == id {
+			return
+		}
+	}
+
+	entry, _ := json.Marshal(append(ids, id))
+	app.state.Set(key, entry)
+}
+
+// indexEntry returns the Ids stored in an index entry at a state version. Version 0 reads the current state.
+func (app *BftApplication) indexEntry(key []byte, version uint64) []string {
+	var ids []string
+	if entry := app.get(key, version); entry != nil {
+		json.Unmarshal(entry, &ids)
+	}
+	return ids
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,183 @@
+// File: ./blockfreight/lib/bft/lifecycle.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// checkTransition checks the lifecycle of a committed BF_TX allows it to move to a new state
+func (app *BftApplication) checkTransition(id string, to bf_tx.Lifecycle) (uint32, string) {
+	bftx, ok := app.getBFTX(id, 0)
+	if !ok {
+		return CodeTypeUnknownBFTX, "BF_TX " + id + " does not exist."
+	}
+	if _, err := bf_tx.Transition(bftx, to); err != nil {
+		return transitionCode(err.(bf_tx.TransitionError))
+	}
+	return code.CodeTypeOK, ""
+}
+
+// transitionCode returns the ABCI code and log of an illegal transition, telling apart the final states
+func transitionCode(err bf_tx.TransitionError) (uint32, string) {
+	switch err.From {
+	case bf_tx.StateAmended:
+		return CodeTypeSuperseded, "BF_TX " + err.Id + " is amended, only its latest version can be " + string(err.To) + "."
+	case bf_tx.StateSurrendered:
+		return CodeTypeSurrendered, "BF_TX " + err.Id + " is surrendered, it cannot be " + string(err.To) + "."
+	case bf_tx.StateAccomplished:
+		return CodeTypeAccomplished, "BF_TX " + err.Id + " is accomplished, it cannot be " + string(err.To) + "."
+	case bf_tx.StateVoid:
+		return CodeTypeVoid, "BF_TX " + err.Id + " is void."
+	}
+	return CodeTypeIllegalState, err.Error()
+}
+
+// setState moves a committed BF_TX to a new state, once checkTransition allowed it
+func (app *BftApplication) setState(id string, state bf_tx.Lifecycle) {
+	bftx, _ := app.getBFTX(id, 0)
+	bftx.Lifecycle = state
+	record, _ := json.Marshal(bftx)
+	app.state.Set([]byte(id), record)
+}
+
+// latest returns the Id of the latest version of the bill of lading a BF_TX belongs to
+func (app *BftApplication) latest(id string) string {
+	lineage := app.lineage(id, 0)
+	return lineage[len(lineage)-1]
+}
+
+// stateTag tags a transaction with the state it moved a BF_TX to
+func stateTag(state bf_tx.Lifecycle) *types.KVPair {
+	return &types.KVPair{Key: TagState, ValueType: types.KVPair_STRING, ValueString: string(state)}
+}
+
+// voidOp cancels a bill of lading that was not negotiated yet
+type voidOp struct {
+	env      bf_tx.Envelope
+	original string
+	latest   string
+}
+
+// checkVoid checks a void cancels a bill of lading its issuer still holds, and that the role rules allow its signer,
+// a party with authority over it by default, to make it
+func (app *BftApplication) checkVoid(env bf_tx.Envelope) (operation, uint32, string) {
+	var void bf_tx.Void
+	if err := json.Unmarshal(env.Payload, &void); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode void: " + err.Error()
+	}
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Void of BF_TX " + void.Id + " is not signed."
+	}
+	if !app.hasBFTX(void.Id) {
+		return nil, CodeTypeUnknownBFTX, "BF_TX " + void.Id + " does not exist."
+	}
+
+	original, latest := app.lineage(void.Id, 0)[0], app.latest(void.Id)
+	if resCode, resLog := app.checkTransition(latest, bf_tx.StateVoid); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	bftx, _ := app.getBFTX(latest, 0)
+	if resCode, resLog := app.authorize(env, env.Signer, original, bftx); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+
+	return voidOp{env, original, latest}, code.CodeTypeOK, ""
+}
+
+func (op voidOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	app.setState(op.latest, bf_tx.StateVoid)
+	app.addHistory(op.original, op.env, op.env.Payload)
+
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: op.original},
+		opTag(op.env.Type),
+		stateTag(bf_tx.StateVoid),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,158 @@
+// File: ./blockfreight/lib/bft/nonces.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"errors"  // Implements functions to manipulate errors.
+	"strconv" // Implements conversions to and from string representations of basic data types.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// NoncePrefix is the prefix of the state entries that hold, for every key that signed an envelope, the sequence
+// of the last one committed.
+const NoncePrefix string = "nonce:"
+
+// sequencedOp bumps the sequence of the signer of an operation when it is delivered
+type sequencedOp struct {
+	operation
+	env bf_tx.Envelope
+}
+
+func (op sequencedOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	app.state.Set(nonceKey(op.env.Signer), []byte(strconv.FormatUint(op.env.Sequence, 10)))
+	return op.operation.deliver(app)
+}
+
+// checkSequence checks a signed envelope, or validator set change, carries the sequence that follows the last one
+// applied, so a committed one cannot be applied again
+func checkSequence(sequence uint64, last uint64) (uint32, string) {
+	next := strconv.FormatUint(last+1, 10)
+	if sequence <= last {
+		return CodeTypeBadNonce, "Sequence " + strconv.FormatUint(sequence, 10) + " was already used, the next one is " + next + "."
+	}
+	if sequence > last+1 {
+		return CodeTypeBadNonce, "Sequence " + strconv.FormatUint(sequence, 10) + " is ahead, the next one is " + next + "."
+	}
+	return code.CodeTypeOK, ""
+}
+
+// sequence returns the sequence of the last committed envelope signed with a key, 0 if it never signed one
+func (app *BftApplication) sequence(pubKey []byte) uint64 {
+	_, value := app.state.Get(nonceKey(pubKey))
+	sequence, _ := strconv.ParseUint(string(value), 10, 64)
+	return sequence
+}
+
+// mempoolSequence returns the sequence of the last envelope signed with a key that passed CheckTx, so a signer
+// can have several envelopes in the mempool before the next block
+func (app *BftApplication) mempoolSequence(pubKey []byte) uint64 {
+	if sequence, ok := app.mempoolSequences[string(pubKey)]; ok {
+		return sequence
+	}
+	return app.sequence(pubKey)
+}
+
+func nonceKey(pubKey []byte) []byte {
+	return append([]byte(NoncePrefix), pubKey...)
+}
+
+// NextSequence returns the sequence the next envelope signed with a key must carry, from the response to a
+// /nonce query with the key as query data, or the sequence of the next validator set change, from the response
+// to a /validators/sequence query.
+func NextSequence(resQuery types.ResponseQuery) (uint64, error) {
+	if resQuery.Code != code.CodeTypeOK {
+		return 0, errors.New(resQuery.Log)
+	}
+	if resQuery.Value == nil {
+		return 1, nil
+	}
+	last, err := strconv.ParseUint(string(resQuery.Value), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,330 @@
+// File: ./blockfreight/lib/bft/ops.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"  // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto" // Provides useful functions to sign BF_TX.
+)
+
+// HistoryIndexPrefix is the prefix of the state entries that hold, for every BF_TX, the JSON list of the
+// operations applied to it.
+const HistoryIndexPrefix string = "history:"
+
+// HistoryEntry records an operation applied to a BF_TX.
+type HistoryEntry struct {
+	Type    bf_tx.OpType    `json:"Type"`
+	Height  int64           `json:"Height"`
+	Time    int64           `json:"Time"`
+	Signer  []byte          `json:"Signer,omitempty"`
+	Payload json.RawMessage `json:"Payload,omitempty"` // The operation payload, left out for issue and amend.
+}
+
+// operation is a transaction that passed its checks and can be applied to the state.
+type operation interface {
+	deliver(app *BftApplication) types.ResponseDeliverTx
+}
+
+// checkTx decodes a transaction envelope and runs the checks of its operation type. A signed envelope must carry
+// the sequence that follows lastSequence of its signer, and the transaction must fit in the quotas of its account
+// on top of its usage.
+func (app *BftApplication) checkTx(tx []byte, lastSequence func(pubKey []byte) uint64, usage func(account string) Usage) (operation, uint32, string) {
+	if maxTxBytes := app.Params().MaxTxBytes; maxTxBytes > 0 && len(tx) > maxTxBytes {
+		return nil, CodeTypeTxTooLarge, "Transaction of " + strconv.Itoa(len(tx)) + " bytes exceeds the MaxTxBytes parameter, " + strconv.Itoa(maxTxBytes) + " bytes."
+	}
+	env, err := bf_tx.DecodeEnvelope(tx)
+	if err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode transaction: " + err.Error()
+	}
+	op, resCode, resLog := app.checkEnvelope(env, lastSequence)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	return app.checkQuota(op, len(tx), usage)
+}
+
+// checkEnvelope checks the signature and sequence of a signed envelope, then its operation
+func (app *BftApplication) checkEnvelope(env bf_tx.Envelope, lastSequence func(pubKey []byte) uint64) (operation, uint32, string) {
+	if env.Signer == nil && env.Signature == nil {
+		return app.checkOperation(env)
+	}
+
+	if !crypto.VerifyEnvelope(env) {
+		return nil, CodeTypeBadSignature, "Transaction does not match its signature."
+	}
+	if env.ChainID != app.ChainID() {
+		return nil, CodeTypeWrongChain, "Transaction is signed for chain " + env.ChainID + ", not " + app.ChainID() + "."
+	}
+	if owner := app.revokedBy(env.Signer); owner != "" {
+		return nil, CodeTypeRevokedKey, "Transaction is signed with a key party " + owner + " revoked."
+	}
+	if resCode, resLog := checkSequence(env.Sequence, lastSequence(env.Signer)); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	op, resCode, resLog := app.checkOperation(env)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	return sequencedOp{op, env}, code.CodeTypeOK, ""
+}
+
+// checkOperation runs the checks of the operation type of an envelope.
+func (app *BftApplication) checkOperation(env bf_tx.Envelope) (operation, uint32, string) {
+	switch env.Type {
+	case bf_tx.OpIssue, bf_tx.OpAmend:
+		return app.checkIssue(env)
+	case bf_tx.OpStatusUpdate:
+		return app.checkStatusUpdate(env)
+	case bf_tx.OpTransfer:
+		return app.checkTransfer(env)
+	case bf_tx.OpSurrender:
+		return app.checkSurrender(env)
+	case bf_tx.OpRelease:
+		return app.checkRelease(env)
+	case bf_tx.OpVoid:
+		return app.checkVoid(env)
+	case bf_tx.OpRegisterParty:
+		return app.checkRegisterParty(env)
+	case bf_tx.OpRotateKey:
+		return app.checkRotateKey(env)
+	case bf_tx.OpRevokeKey:
+		return app.checkRevokeKey(env)
+	case bf_tx.OpCosign:
+		return app.checkCosign(env)
+	case bf_tx.OpProposeParams:
+		return app.checkProposeParams(env)
+	case bf_tx.OpVote:
+		return app.checkVote(env)
+	case bf_tx.OpApproveParty:
+		return app.checkApproveParty(env)
+	}
+	return nil, CodeTypeUnknownOperation, "Unknown operation " + string(env.Type) + "."
+}
+
+// issueOp issues a new BF_TX, or an amendment of a committed one
+type issueOp struct {
+	env  bf_tx.Envelope
+	bftx bf_tx.BF_TX
+}
+
+func (app *BftApplication) checkIssue(env bf_tx.Envelope) (operation, uint32, string) {
+	bftx, resCode, resLog := app.checkBFTX(env.Payload)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+
+	if env.Type == bf_tx.OpIssue && bftx.Amendment != "" {
+		return nil, CodeTypeValidationError, "BF_TX " + bftx.Id + " amends " + bftx.Amendment + ", it must be sent as an amend operation."
+	}
+	if resCode, resLog := app.checkCoSigners(bftx); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if env.Type == bf_tx.OpAmend {
+		if resCode, resLog := app.checkAmendment(env, bftx); resCode != code.CodeTypeOK {
+			return nil, resCode, resLog
+		}
+	} else {
+		// A bare BF_TX has no envelope signer, it is issued by the signer of the BF_TX
+		signer := env.Signer
+		if signer == nil {
+			signer = bftx.Signer
+		}
+		if resCode, resLog := app.authorize(env, signer, "", bftx); resCode != code.CodeTypeOK {
+			return nil, resCode, resLog
+		}
+	}
+
+	return issueOp{env, bftx}, code.CodeTypeOK, ""
+}
+
+func (op issueOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	bftx := op.bftx
+
+	// Record when the BF_TX hit the chain. An amendment takes the place of the BF_TX it amends in the lifecycle,
+	// but a BF_TX that names parties to co-sign it, an amendment too, waits for their quorum.
+	bftx.BlockHeight = app.blockHeight()
+	bftx.BlockTime = app.blockTime()
+	bftx.Lifecycle = bf_tx.StateCommitted
+	if op.env.Type == bf_tx.OpAmend {
+		predecessor, _ := app.getBFTX(bftx.Amendment, 0)
+		bftx.Lifecycle = app.settled(app.lineage(predecessor.Id, 0)[0])
+		app.setState(predecessor.Id, bf_tx.StateAmended)
+	}
+	if app.quorumOf(bftx) > 0 {
+		bftx.Lifecycle = bf_tx.StatePending
+	}
+	record, err := json.Marshal(bftx)
+	if err != nil {
+		return types.ResponseDeliverTx{Code: CodeTypeEncodingError, Log: "Cannot encode BF_TX: " + err.Error()}
+	}
+	app.state.Set([]byte(bftx.Id), record)
+	app.indexBFTX(bftx)
+	if op.env.Type == bf_tx.OpIssue {
+		// A bare BF_TX is issued, and held, by the signer of the BF_TX, which checkBFTX verified
+		issuer := op.env.Signer
+		if issuer == nil {
+			issuer = bftx.Signer
+		}
+		app.setIssuer(bftx.Id, issuer)
+		app.setTitle(bftx.Id, Title{Holder: app.holderOf(issuer)})
+	}
+	app.addHistory(bftx.Id, op.env, nil)
+
+	tags := append(app.deliverTags(bftx), opTag(op.env.Type))
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// recordOp records an operation on a committed BF_TX in its history
+type recordOp struct {
+	env bf_tx.Envelope
+	id  string
+}
+
+func (app *BftApplication) checkStatusUpdate(env bf_tx.Envelope) (operation, uint32, string) {
+	var update bf_tx.StatusUpdate
+	if err := json.Unmarshal(env.Payload, &update); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode status update: " + err.Error()
+	}
+	if update.Status == "" {
+		return nil, CodeTypeValidationError, "Status update has no status."
+	}
+	return app.checkRecordOp(env, update.Id)
+}
+
+// checkRecordOp checks an operation on a committed BF_TX is signed, the BF_TX exists, and the role rules allow
+// the signer on its bill of lading
+func (app *BftApplication) checkRecordOp(env bf_tx.Envelope, id string) (operation, uint32, string) {
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Operation " + string(env.Type) + " on BF_TX " + id + " is not signed."
+	}
+	if !app.hasBFTX(id) {
+		return nil, CodeTypeUnknownBFTX, "BF_TX " + id + " does not exist."
+	}
+	original := app.lineage(id, 0)[0]
+	bftx, _ := app.getBFTX(app.latest(original), 0)
+	if resCode, resLog := app.authorize(env, env.Signer, original, bftx); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	return recordOp{env, id}, code.CodeTypeOK, ""
+}
+
+func (op recordOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	app.addHistory(op.id, op.env, op.env.Payload)
+
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: op.id},
+		opTag(op.env.Type),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// hasBFTX reports whether a BF_TX with that Id is committed
+func (app *BftApplication) hasBFTX(id string) bool {
+	return strings.HasPrefix(id, "BFTX") && app.state.Has([]byte(id))
+}
+
+// addHistory appends an operation to the history of a BF_TX
+func (app *BftApplication) addHistory(id string, env bf_tx.Envelope, payload json.RawMessage) {
+	key := []byte(HistoryIndexPrefix + id)
+	var history []HistoryEntry
+	if _, value := app.state.Get(key); value != nil {
+		json.Unmarshal(value, &history)
+	}
+
+	history = append(history, HistoryEntry{
+		Type:    env.Type,
+		Height:  app.blockHeight(),
+		Time:    app.blockTime(),
+		Signer:  env.Signer,
+		Payload: payload,
+	})
+	value, _ := json.Marshal(history)
+	app.state.Set(key, value)
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,407 @@
+// File: ./blockfreight/lib/bft/parties.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// PartyPrefix is the prefix of the state keys the registered parties are kept under.
+const PartyPrefix string = "party:"
+
+// PartyKeyPrefix is the prefix of the state entries that map a public key to the Id of the party it belongs to.
+const PartyKeyPrefix string = "partykey:"
+
+// RevokedKeyPrefix is the prefix of the state entries that map a revoked public key to the Id of the party that revoked it.
+const RevokedKeyPrefix string = "revokedkey:"
+
+// TagParty is the tag holding the Id of a registered party.
+const TagParty string = "party.id"
+
+// registerPartyOp adds a party to the registry
+type registerPartyOp struct {
+	env   bf_tx.Envelope
+	party bf_tx.Party
+}
+
+// checkRegisterParty checks a party registration is complete, new, and signed with one of the party keys. The
+// party does not act with a privileged role, nor for its principal, until they approve it.
+func (app *BftApplication) checkRegisterParty(env bf_tx.Envelope) (operation, uint32, string) {
+	var party bf_tx.Party
+	if err := json.Unmarshal(env.Payload, &party); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode party: " + err.Error()
+	}
+	if party.Id == "" || party.Name == "" || party.Role == "" {
+		return nil, CodeTypeValidationError, "Party needs an Id, a Name and a Role."
+	}
+	if reservedAccount(party.Id) {
+		return nil, CodeTypeValidationError, "Party Id " + party.Id + " is reserved."
+	}
+	if !validRole(party.Role) {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " has an unknown role " + party.Role + "."
+	}
+	if len(party.PubKeys) == 0 {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " has no public key."
+	}
+	if len(party.Revoked) > 0 {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " cannot be registered with revoked keys."
+	}
+	if party.RoleApproved || party.PrincipalApproved {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " cannot be registered approved, it must be approved afterwards."
+	}
+	if party.Principal != "" && !app.state.Has(partyKey(party.Principal)) {
+		return nil, CodeTypeUnknownParty, "Principal " + party.Principal + " of party " + party.Id + " does not exist."
+	}
+	for _, pubKey := range party.PubKeys {
+		if _, err := tmcrypto.PubKeyFromBytes(pubKey); err != nil {
+			return nil, CodeTypeEncodingError, "Invalid public key of party " + party.Id + ": " + err.Error()
+		}
+	}
+
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Registration of party " + party.Id + " is not signed."
+	}
+	if !hasKey(party.PubKeys, env.Signer) {
+		return nil, CodeTypeUnauthorized, "Registration of party " + party.Id + " must be signed with one of its keys."
+	}
+
+	if app.state.Has(partyKey(party.Id)) {
+		return nil, CodeTypeDuplicateID, "Party " + party.Id + " already exists."
+	}
+	for _, pubKey := range party.PubKeys {
+		if resCode, resLog := app.checkNewKey(party.Id, pubKey); resCode != code.CodeTypeOK {
+			return nil, resCode, resLog
+		}
+	}
+
+	return registerPartyOp{env, party}, code.CodeTypeOK, ""
+}
+
+func (op registerPartyOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	app.setParty(op.party)
+	for _, pubKey := range op.party.PubKeys {
+		app.state.Set(append([]byte(PartyKeyPrefix), pubKey...), []byte(op.party.Id))
+	}
+
+	tags := []*types.KVPair{
+		{Key: TagParty, ValueType: types.KVPair_STRING, ValueString: op.party.Id},
+		opTag(op.env.Type),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// approvePartyOp approves the privileged role of a party, or that it acts for its principal
+type approvePartyOp struct {
+	env       bf_tx.Envelope
+	party     bf_tx.Party
+	principal bool
+}
+
+// checkApproveParty checks a party approval is signed by a validator, for a party with a privileged role that was
+// not approved yet, or with a key of the principal the party names, which did not approve it yet.
+func (app *BftApplication) checkApproveParty(env bf_tx.Envelope) (operation, uint32, string) {
+	var approval bf_tx.PartyApproval
+	if err := json.Unmarshal(env.Payload, &approval); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode party approval: " + err.Error()
+	}
+	party, ok := app.party(approval.Party)
+	if !ok {
+		return nil, CodeTypeUnknownParty, "Party " + approval.Party + " does not exist."
+	}
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Approval of party " + party.Id + " is not signed."
+	}
+
+	if principal, ok := app.party(party.Principal); ok && hasKey(principal.PubKeys, env.Signer) {
+		if party.PrincipalApproved {
+			return nil, CodeTypeDuplicateVote, "Principal " + principal.Id + " already approved party " + party.Id + "."
+		}
+		return approvePartyOp{env, party, true}, code.CodeTypeOK, ""
+	}
+	if !app.state.Has(validatorKey(env.Signer)) {
+		return nil, CodeTypeUnauthorized, "Party " + party.Id + " must be approved by a validator, or by its principal."
+	}
+	if !app.privileged(party.Role) {
+		return nil, CodeTypeValidationError, "Role " + party.Role + " of party " + party.Id + " needs no approval."
+	}
+	if party.RoleApproved {
+		return nil, CodeTypeDuplicateVote, "Role " + party.Role + " of party " + party.Id + " is already approved."
+	}
+	return approvePartyOp{env, party, false}, code.CodeTypeOK, ""
+}
+
+func (op approvePartyOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	party := op.party
+	if op.principal {
+		party.PrincipalApproved = true
+	} else {
+		party.RoleApproved = true
+	}
+	app.setParty(party)
+
+	tags := []*types.KVPair{
+		{Key: TagParty, ValueType: types.KVPair_STRING, ValueString: party.Id},
+		opTag(op.env.Type),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// privileged reports whether a role is one of the PrivilegedRoles parameter, which need the approval of a validator
+func (app *BftApplication) privileged(role string) bool {
+	for _, privileged := range app.Params().PrivilegedRoles {
+		if role == privileged {
+			return true
+		}
+	}
+	return false
+}
+
+// roleOf returns the role a party acts with: its role, once it was approved if it is privileged
+func (app *BftApplication) roleOf(party bf_tx.Party) string {
+	if app.privileged(party.Role) && !party.RoleApproved {
+		return ""
+	}
+	return party.Role
+}
+
+// keyOp adds a key to a party, or revokes one of its keys
+type keyOp struct {
+	env    bf_tx.Envelope
+	party  bf_tx.Party
+	add    []byte
+	revoke []byte
+}
+
+// checkRotateKey checks a key rotation adds a new key to a party, and only revokes one of its keys.
+func (app *BftApplication) checkRotateKey(env bf_tx.Envelope) (operation, uint32, string) {
+	var rotation bf_tx.KeyRotation
+	if err := json.Unmarshal(env.Payload, &rotation); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode key rotation: " + err.Error()
+	}
+	party, resCode, resLog := app.checkKeyOp(env, rotation.Party)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if _, err := tmcrypto.PubKeyFromBytes(rotation.PubKey); err != nil {
+		return nil, CodeTypeEncodingError, "Invalid public key of party " + party.Id + ": " + err.Error()
+	}
+	if resCode, resLog := app.checkNewKey(party.Id, rotation.PubKey); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if rotation.Revoke != nil && !hasKey(party.PubKeys, rotation.Revoke) {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " has no such key to revoke."
+	}
+
+	return keyOp{env, party, rotation.PubKey, rotation.Revoke}, code.CodeTypeOK, ""
+}
+
+// checkRevokeKey checks a key revocation revokes one of the keys of a party, and leaves it at least another one.
+func (app *BftApplication) checkRevokeKey(env bf_tx.Envelope) (operation, uint32, string) {
+	var revocation bf_tx.KeyRevocation
+	if err := json.Unmarshal(env.Payload, &revocation); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode key revocation: " + err.Error()
+	}
+	party, resCode, resLog := app.checkKeyOp(env, revocation.Party)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if !hasKey(party.PubKeys, revocation.PubKey) {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " has no such key to revoke."
+	}
+	if len(party.PubKeys) == 1 {
+		return nil, CodeTypeValidationError, "Party " + party.Id + " cannot revoke its last key, rotate it instead."
+	}
+
+	return keyOp{env: env, party: party, revoke: revocation.PubKey}, code.CodeTypeOK, ""
+}
+
+// checkKeyOp checks an operation on the keys of a party is signed with one of its keys
+func (app *BftApplication) checkKeyOp(env bf_tx.Envelope, id string) (bf_tx.Party, uint32, string) {
+	party, ok := app.party(id)
+	if !ok {
+		return party, CodeTypeUnknownParty, "Party " + id + " does not exist."
+	}
+	if env.Signer == nil {
+		return party, CodeTypeUnsigned, "Operation " + string(env.Type) + " on party " + id + " is not signed."
+	}
+	if !hasKey(party.PubKeys, env.Signer) {
+		return party, CodeTypeUnauthorized, "Operation " + string(env.Type) + " on party " + id + " must be signed with one of its keys."
+	}
+	return party, code.CodeTypeOK, ""
+}
+
+// checkNewKey checks a key added to a party is not registered, nor was revoked, by any party
+func (app *BftApplication) checkNewKey(id string, pubKey []byte) (uint32, string) {
+	if owner := app.partyOfKey(pubKey); owner != "" {
+		return CodeTypeDuplicateID, "A public key of party " + id + " already belongs to party " + owner + "."
+	}
+	if owner := app.revokedBy(pubKey); owner != "" {
+		return CodeTypeRevokedKey, "A public key of party " + id + " was revoked by party " + owner + "."
+	}
+	return code.CodeTypeOK, ""
+}
+
+func (op keyOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	party := op.party
+	if op.add != nil {
+		party.PubKeys = append(party.PubKeys, op.add)
+		app.state.Set(append([]byte(PartyKeyPrefix), op.add...), []byte(party.Id))
+	}
+	if op.revoke != nil {
+		pubKeys := make([][]byte, 0, len(party.PubKeys))
+		for _, pubKey := range party.PubKeys {
+			if !bytes.Equal(pubKey, op.revoke) {
+				pubKeys = append(pubKeys, pubKey)
+			}
+		}
+		party.PubKeys = pubKeys
+		party.Revoked = append(party.Revoked, op.revoke)
+		app.state.Remove(append([]byte(PartyKeyPrefix), op.revoke...))
+		app.state.Set(append([]byte(RevokedKeyPrefix), op.revoke...), []byte(party.Id))
+	}
+	app.setParty(party)
+
+	tags := []*types.KVPair{
+		{Key: TagParty, ValueType: types.KVPair_STRING, ValueString: party.Id},
+		opTag(op.env.Type),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// party returns a registered party
+func (app *BftApplication) party(id string) (bf_tx.Party, bool) {
+	var party bf_tx.Party
+	_, record := app.state.Get(partyKey(id))
+	if record == nil || json.Unmarshal(record, &party) != nil {
+		return party, false
+	}
+	return party, true
+}
+
+func (app *BftApplication) setParty(party bf_tx.Party) {
+	record, _ := json.Marshal(party)
+	app.state.Set(partyKey(party.Id), record)
+}
+
+func partyKey(id string) []byte {
+	return []byte(PartyPrefix + id)
+}
+
+// partyOfKey returns the Id of the registered party a public key belongs to, or an empty string
+func (app *BftApplication) partyOfKey(pubKey []byte) string {
+	_, id := app.state.Get(append([]byte(PartyKeyPrefix), pubKey...))
+	return string(id)
+}
+
+// revokedBy returns the Id of the party that revoked a public key, or an empty string
+func (app *BftApplication) revokedBy(pubKey []byte) string {
+	_, id := app.state.Get(append([]byte(RevokedKeyPrefix), pubKey...))
+	return string(id)
+}
+
+// validRole reports whether a party role is one of bf_tx.Roles
+func validRole(role string) bool {
+	for _, known := range bf_tx.Roles {
+		if role == known {
+			return true
+		}
+	}
+	return false
+}
+
+// hasKey reports whether a go-wire encoded public key is in a list of keys
+func hasKey(pubKeys [][]byte, pubKey []byte) bool {
+	for _, key := range pubKeys {
+		if bytes.Equal(key, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
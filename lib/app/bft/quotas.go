@@ -0,0 +1,221 @@
+// File: ./blockfreight/lib/bft/quotas.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/hex"  // Implements hexadecimal encoding and decoding.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+	"strings"       // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// QuotaPrefix is the prefix of the state entries that hold, for every account, the transactions it sent in the
+// current quota window.
+const QuotaPrefix string = "quota:"
+
+// UnregisteredAccount is the account the keys no party holds share, so fresh keys cannot multiply the quota. No
+// party can register with it as Id, nor with an Id under it.
+const UnregisteredAccount string = "unregistered"
+
+// RegistrationAccount returns the account the party registrations signed with a key no party holds count against,
+// one per key, so the keys that fill the UnregisteredAccount cannot hold the registrations back.
+func RegistrationAccount(pubKey []byte) string {
+	return UnregisteredAccount + "/" + hex.EncodeToString(pubKey)
+}
+
+// reservedAccount reports whether an Id is the UnregisteredAccount, or a RegistrationAccount, which no party can take
+func reservedAccount(id string) bool {
+	return id == UnregisteredAccount || strings.HasPrefix(id, UnregisteredAccount+"/")
+}
+
+// Usage is what an account sent in a quota window.
+type Usage struct {
+	Window int64 `json:"Window"` // Height of the first block of the window.
+	Txs    int   `json:"Txs"`
+	Bytes  int   `json:"Bytes"`
+}
+
+// quotaOp adds a transaction to the usage of its account when it is delivered
+type quotaOp struct {
+	operation
+	account string
+	usage   Usage
+}
+
+func (op quotaOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	value, _ := json.Marshal(op.usage)
+	app.state.Set([]byte(QuotaPrefix+op.account), value)
+	return op.operation.deliver(app)
+}
+
+// checkQuota checks the account of an operation can send another transaction of size bytes on top of the usage
+// returned for it, within the QuotaTxs and QuotaBytes parameters, QuotaUnregisteredTxs and QuotaUnregisteredBytes
+// for the UnregisteredAccount, or QuotaRegistrationTxs and QuotaRegistrationBytes for a RegistrationAccount.
+func (app *BftApplication) checkQuota(op operation, size int, usage func(account string) Usage) (operation, uint32, string) {
+	account := app.accountOf(op)
+	if account == "" {
+		return op, code.CodeTypeOK, ""
+	}
+
+	quotas := app.Params()
+	maxTxs, maxBytes := quotas.QuotaTxs, quotas.QuotaBytes
+	switch {
+	case account == UnregisteredAccount:
+		maxTxs, maxBytes = quotas.QuotaUnregisteredTxs, quotas.QuotaUnregisteredBytes
+	case reservedAccount(account):
+		maxTxs, maxBytes = quotas.QuotaRegistrationTxs, quotas.QuotaRegistrationBytes
+	}
+	used := usage(account)
+	used.Txs++
+	used.Bytes += size
+	retry := "retry from height " + strconv.FormatInt(used.Window+quotas.QuotaWindow, 10) + "."
+	if maxTxs > 0 && used.Txs > maxTxs {
+		return nil, CodeTypeQuotaExceeded, "Account " + account + " sent its quota of " + strconv.Itoa(maxTxs) + " transactions in " + strconv.FormatInt(quotas.QuotaWindow, 10) + " blocks, " + retry
+	}
+	if maxBytes > 0 && used.Bytes > maxBytes {
+		return nil, CodeTypeQuotaExceeded, "Account " + account + " would exceed its quota of " + strconv.Itoa(maxBytes) + " bytes in " + strconv.FormatInt(quotas.QuotaWindow, 10) + " blocks, " + retry
+	}
+	return quotaOp{op, account, used}, code.CodeTypeOK, ""
+}
+
+// accountOf returns the account an operation counts against: the registered party of its signer, the
+// RegistrationAccount of its key for a party registration, or the UnregisteredAccount when no party holds its key.
+// A bare BF_TX counts against the signer of the BF_TX. The proposals, votes and approvals of the validators count
+// against no account, as the validators govern the quotas, and neither do the validator set changes, which do not
+// come in an envelope.
+func (app *BftApplication) accountOf(op operation) string {
+	var signer []byte
+	var opType bf_tx.OpType
+	switch op := op.(type) {
+	case sequencedOp:
+		signer, opType = op.env.Signer, op.env.Type
+	case issueOp:
+		signer = op.bftx.Signer
+	}
+	if len(signer) == 0 {
+		return ""
+	}
+	switch opType {
+	case bf_tx.OpProposeParams, bf_tx.OpVote, bf_tx.OpApproveParty:
+		if app.state.Has(validatorKey(signer)) {
+			return ""
+		}
+	}
+	if party := app.partyOfKey(signer); party != "" {
+		return party
+	}
+	if opType == bf_tx.OpRegisterParty {
+		return RegistrationAccount(signer)
+	}
+	return UnregisteredAccount
+}
+
+// usage returns what an account sent in the quota window of the block being executed. The windows are
+// QuotaWindow blocks long, counted from height 1.
+func (app *BftApplication) usage(account string) Usage {
+	height := app.blockHeight()
+	window := height - (height-1)%app.Params().QuotaWindow
+
+	var used Usage
+	if _, value := app.state.Get([]byte(QuotaPrefix + account)); value != nil {
+		json.Unmarshal(value, &used)
+	}
+	if used.Window != window {
+		return Usage{Window: window}
+	}
+	return used
+}
+
+// mempoolUsage returns what an account sent in the current quota window, counting the transactions CheckTx
+// admitted since the last Commit
+func (app *BftApplication) mempoolUsage(account string) Usage {
+	if used, ok := app.mempoolUsages[account]; ok && used.Window == app.usage(account).Window {
+		return used
+	}
+	return app.usage(account)
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
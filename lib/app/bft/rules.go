@@ -0,0 +1,149 @@
+// File: ./blockfreight/lib/bft/rules.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/authz" // Defines the role rules that authorize the operations on a BF_TX.
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// Rules returns the role rules the application enforces, its live Rules parameter
+func (app *BftApplication) Rules() authz.Rules {
+	return app.Params().Rules
+}
+
+// authorize checks the role rules allow the signer of an operation on a bill of lading, by the Id of its
+// original BF_TX. The BF_TX is its latest version, or the one being issued.
+func (app *BftApplication) authorize(env bf_tx.Envelope, signer []byte, original string, bftx bf_tx.BF_TX) (uint32, string) {
+	if err := app.Rules().Authorize(env.Type, app.signerOf(signer, original, bftx)); err != nil {
+		return CodeTypeNotAllowed, err.Error()
+	}
+	return code.CodeTypeOK, ""
+}
+
+// authorizeAmendment checks the role rules allow the signer of an amendment, in the state of the BF_TX it amends,
+// and every field it changes
+func (app *BftApplication) authorizeAmendment(env bf_tx.Envelope, original string, predecessor bf_tx.BF_TX, bftx bf_tx.BF_TX) (uint32, string) {
+	signer := app.signerOf(env.Signer, original, predecessor)
+	err := app.Rules().AuthorizeAmendment(signer, bf_tx.State(predecessor), predecessor.Properties, bftx.Properties)
+	switch err.(type) {
+	case nil:
+		return code.CodeTypeOK, ""
+	case authz.FieldError:
+		return CodeTypeFieldNotAllowed, err.Error()
+	}
+	return CodeTypeNotAllowed, err.Error()
+}
+
+// signerOf describes a key to the role rules: the role of its party, and its relations with a bill of lading
+func (app *BftApplication) signerOf(pubKey []byte, original string, bftx bf_tx.BF_TX) authz.Signer {
+	var signer authz.Signer
+	party, registered := app.party(app.partyOfKey(pubKey))
+	if registered {
+		signer.Role = app.roleOf(party)
+		// The shipper is the registered party the BF_TX names in its Party, never one whose Id matches its Name
+		if party.Id == bftx.Properties.Shipper.Party {
+			signer.Relations = append(signer.Relations, authz.RelationShipper)
+		}
+		for _, id := range bf_tx.CoSigners(bftx) {
+			if party.Id == id {
+				signer.Relations = append(signer.Relations, authz.RelationCosigner)
+			}
+		}
+		// An agent acts for the carrier it names as principal once the carrier approved it
+		principal, ok := app.party(party.Principal)
+		if ok && party.PrincipalApproved && signer.Role == bf_tx.RoleAgent && app.roleOf(principal) == bf_tx.RoleCarrier {
+			signer.Relations = append(signer.Relations, authz.RelationAgentForMaster)
+		}
+	}
+
+	if original == "" {
+		return signer
+	}
+	if app.hasAuthority(original, pubKey) {
+		signer.Relations = append(signer.Relations, authz.RelationIssuer)
+	}
+	if title, ok := app.title(original); ok && app.isHolder(title.Holder, pubKey) {
+		signer.Relations = append(signer.Relations, authz.RelationHolder)
+	}
+	return signer
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
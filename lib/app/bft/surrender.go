@@ -0,0 +1,213 @@
+// File: ./blockfreight/lib/bft/surrender.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// Settlement records the surrender of a bill of lading, or the release of its cargo.
+type Settlement struct {
+	Signer []byte `json:"Signer"`
+	Height int64  `json:"Height"`
+	Time   int64  `json:"Time"`
+}
+
+// Accomplished reports whether the cargo of a bill of lading was released. Nothing more can be done with it.
+func (title Title) Accomplished() bool {
+	return title.Release != nil
+}
+
+// settlementOp surrenders a bill of lading, or releases its cargo
+type settlementOp struct {
+	env      bf_tx.Envelope
+	original string
+	latest   string
+	to       Holder // The carrier or DeliverAgent the bill of lading is surrendered to.
+}
+
+// checkSurrender checks a surrender is made to the carrier or its DeliverAgent, and that the role rules allow its
+// signer, the final holder by default, to make it.
+func (app *BftApplication) checkSurrender(env bf_tx.Envelope) (operation, uint32, string) {
+	var surrender bf_tx.Surrender
+	if err := json.Unmarshal(env.Payload, &surrender); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode surrender: " + err.Error()
+	}
+
+	original, resCode, resLog := app.checkSettlement(env, surrender.Id, bf_tx.StateSurrendered)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	latest, _ := app.getBFTX(app.latest(original), 0)
+	if resCode, resLog := app.authorize(env, env.Signer, original, latest); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+
+	authority, _ := app.authority(original)
+	to := app.holderOf(authority.Issuer)
+	if surrender.To != "" {
+		if surrender.To != authority.Party && surrender.To != latest.Properties.DeliverAgent.Party {
+			return nil, CodeTypeValidationError, "BF_TX " + original + " can only be surrendered to its carrier or DeliverAgent."
+		}
+		if !app.state.Has(partyKey(surrender.To)) {
+			return nil, CodeTypeUnknownParty, "Party " + surrender.To + " does not exist."
+		}
+		to = Holder{Party: surrender.To}
+	}
+
+	return settlementOp{env, original, latest.Id, to}, code.CodeTypeOK, ""
+}
+
+// checkRelease checks the role rules allow the signer of a release, by default the carrier or the party the bill of
+// lading was surrendered to, to make it.
+func (app *BftApplication) checkRelease(env bf_tx.Envelope) (operation, uint32, string) {
+	var release bf_tx.Release
+	if err := json.Unmarshal(env.Payload, &release); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode release: " + err.Error()
+	}
+
+	original, resCode, resLog := app.checkSettlement(env, release.Id, bf_tx.StateAccomplished)
+	if resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	latest, _ := app.getBFTX(app.latest(original), 0)
+	if resCode, resLog := app.authorize(env, env.Signer, original, latest); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+
+	return settlementOp{env: env, original: original, latest: latest.Id}, code.CodeTypeOK, ""
+}
+
+// checkSettlement checks a surrender or release is signed and refers to a bill of lading whose lifecycle
+// allows it to move to the settled state
+func (app *BftApplication) checkSettlement(env bf_tx.Envelope, id string, to bf_tx.Lifecycle) (string, uint32, string) {
+	if env.Signer == nil {
+		return "", CodeTypeUnsigned, "Operation " + string(env.Type) + " on BF_TX " + id + " is not signed."
+	}
+	if !app.hasBFTX(id) {
+		return "", CodeTypeUnknownBFTX, "BF_TX " + id + " does not exist."
+	}
+	original := app.lineage(id, 0)[0]
+	if _, ok := app.title(original); !ok {
+		return original, CodeTypeUnauthorized, "BF_TX " + original + " has no holder."
+	}
+	if resCode, resLog := app.checkTransition(app.latest(original), to); resCode != code.CodeTypeOK {
+		return original, resCode, resLog
+	}
+	return original, code.CodeTypeOK, ""
+}
+
+func (op settlementOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	title, _ := app.title(op.original)
+	settlement := &Settlement{Signer: op.env.Signer, Height: app.blockHeight(), Time: app.blockTime()}
+	state := bf_tx.StateAccomplished
+	if op.env.Type == bf_tx.OpSurrender {
+		state = bf_tx.StateSurrendered
+		title.Endorsements = append(title.Endorsements, Endorsement{
+			From:   title.Holder,
+			To:     op.to,
+			Signer: op.env.Signer,
+			Height: settlement.Height,
+			Time:   settlement.Time,
+		})
+		title.Holder = op.to
+		title.Surrender = settlement
+	} else {
+		title.Release = settlement
+	}
+	app.setTitle(op.original, title)
+	app.setState(op.latest, state)
+	app.addHistory(op.original, op.env, op.env.Payload)
+
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: op.original},
+		opTag(op.env.Type),
+		stateTag(state),
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,216 @@
+// File: ./blockfreight/lib/bft/tags.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"errors"  // Implements functions to manipulate errors.
+	"reflect" // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"sort"    // Provides primitives for sorting slices and user-defined collections.
+	"strings" // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/types"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// Tags emitted by DeliverTx besides the Properties fields, which can be searched with TxSearch
+const (
+	TagID    string = "bftx.id"
+	TagState string = "bftx.state"
+	TagOp    string = "bftx.op"
+)
+
+// TagFields are the Properties fields emitted as DeliverTx tags, under the key given by TagKey
+var TagFields = []string{
+	"Shipper",
+	"Consignee",
+	"PortOfLoading",
+	"PortOfDischarge",
+	"Vessel",
+	"Container",
+	"HouseBill",
+}
+
+// TagKey returns the tag key of a Properties field, e.g. bftx.portofloading for PortOfLoading
+func TagKey(field string) string {
+	return "bftx." + strings.ToLower(field)
+}
+
+// SetEncryptedFields sets the Properties fields that are encrypted, as listed in the encryption config. Their
+// values are not emitted as tags, like those of the fields a BF_TX lists as encrypted in its EncryptionMetaData,
+// so they cannot be searched for nor leak into the tx index.
+func (app *BftApplication) SetEncryptedFields(fields []string) {
+	app.encryptedFields = make(map[string]bool, len(fields))
+	for _, field := range fields {
+		app.encryptedFields[strings.ToLower(field)] = true
+	}
+}
+
+// deliverTags returns the tags of a delivered BF_TX
+func (app *BftApplication) deliverTags(bftx bf_tx.BF_TX) []*types.KVPair {
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: bftx.Id},
+		stateTag(bf_tx.State(bftx)),
+	}
+
+	encrypted := bf_tx.EncryptedFields(bftx.Properties)
+	properties := reflect.ValueOf(bftx.Properties)
+	for _, field := range TagFields {
+		if app.encryptedFields[strings.ToLower(field)] || encrypted[strings.ToLower(field)] {
+			continue
+		}
+		if field == "Container" {
+			// One tag per container of the bill of lading, as they are indexed
+			for _, container := range bf_tx.ContainerNumbers(bftx.Properties) {
+				tags = append(tags, &types.KVPair{Key: TagKey(field), ValueType: types.KVPair_STRING, ValueString: container})
+			}
+			continue
+		}
+		value := tagValue(properties.FieldByName(field))
+		if value == "" {
+			continue
+		}
+		tags = append(tags, &types.KVPair{Key: TagKey(field), ValueType: types.KVPair_STRING, ValueString: value})
+	}
+	return tags
+}
+
+// tagValue returns the text a Properties field is tagged with, the Ref of a party
+func tagValue(field reflect.Value) string {
+	if party, ok := field.Interface().(bf_tx.TradeParty); ok {
+		return party.Ref()
+	}
+	return field.String()
+}
+
+// opTag returns the tag of the operation a transaction carries
+func opTag(opType bf_tx.OpType) *types.KVPair {
+	return &types.KVPair{Key: TagOp, ValueType: types.KVPair_STRING, ValueString: string(opType)}
+}
+
+// SearchQuery builds the TxSearch query that matches all the given filters. The filters are keyed by
+// Properties field name as in TagFields, or by "Id", "State" and "Op".
+func SearchQuery(filters map[string]string) (string, error) {
+	keys := make([]string, 0, len(filters))
+	for field, value := range filters {
+		if value == "" {
+			continue
+		}
+		if strings.Contains(value, "'") {
+			return "", errors.New("Search value of " + field + " cannot contain a quote")
+		}
+		keys = append(keys, field)
+	}
+	if len(keys) == 0 {
+		return "", errors.New("Search needs at least one filter")
+	}
+	sort.Strings(keys)
+
+	conditions := make([]string, 0, len(keys))
+	for _, field := range keys {
+		key, ok := searchTag(field)
+		if !ok {
+			return "", errors.New("Unknown search filter " + field)
+		}
+		conditions = append(conditions, key+"='"+filters[field]+"'")
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// searchTag returns the tag key a search filter matches
+func searchTag(field string) (string, bool) {
+	switch strings.ToLower(field) {
+	case "id":
+		return TagID, true
+	case "state":
+		return TagState, true
+	case "op":
+		return TagOp, true
+	}
+	for _, tagField := range TagFields {
+		if strings.EqualFold(field, tagField) {
+			return TagKey(tagField), true
+		}
+	}
+	return "", false
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,244 @@
+// File: ./blockfreight/lib/bft/title.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// TitlePrefix is the prefix of the state entries that hold the title of every issued bill of lading, under the
+// Id of its original BF_TX.
+const TitlePrefix string = "title:"
+
+// TagHolder is the tag holding the party a bill of lading is transferred to.
+const TagHolder string = "bftx.holder"
+
+// Holder is who holds a bill of lading: a registered party for an order bill, or the owner of a key for a bearer bill.
+type Holder struct {
+	Party string `json:"Party,omitempty"` // Id of the registered party.
+	Key   []byte `json:"Key,omitempty"`   // go-wire encoded public key, when the holder is not a registered party.
+}
+
+// Endorsement records the transfer of a bill of lading from a holder to the next.
+type Endorsement struct {
+	From   Holder `json:"From"`
+	To     Holder `json:"To"`
+	Signer []byte `json:"Signer"`
+	Height int64  `json:"Height"`
+	Time   int64  `json:"Time"`
+}
+
+// Title is the current holder of a bill of lading and the chain of endorsements that led to it, ending with its
+// surrender and the release of its cargo at destination.
+type Title struct {
+	Holder       Holder        `json:"Holder"`
+	Endorsements []Endorsement `json:"Endorsements"`
+	Surrender    *Settlement   `json:"Surrender,omitempty"`
+	Release      *Settlement   `json:"Release,omitempty"`
+}
+
+// holderOf returns the holder a key stands for: its registered party, or the key itself
+func (app *BftApplication) holderOf(pubKey []byte) Holder {
+	if party := app.partyOfKey(pubKey); party != "" {
+		return Holder{Party: party}
+	}
+	return Holder{Key: pubKey}
+}
+
+// isHolder reports whether a key may act as the holder
+func (app *BftApplication) isHolder(holder Holder, pubKey []byte) bool {
+	if holder.Party != "" {
+		return app.partyOfKey(pubKey) == holder.Party
+	}
+	return holder.Key != nil && bytes.Equal(holder.Key, pubKey)
+}
+
+// title returns the title of a bill of lading, by the Id of its original BF_TX
+func (app *BftApplication) title(original string) (Title, bool) {
+	var title Title
+	_, value := app.state.Get([]byte(TitlePrefix + original))
+	if value == nil || json.Unmarshal(value, &title) != nil {
+		return title, false
+	}
+	return title, true
+}
+
+func (app *BftApplication) setTitle(original string, title Title) {
+	value, _ := json.Marshal(title)
+	app.state.Set([]byte(TitlePrefix+original), value)
+}
+
+// transferOp endorses a bill of lading over to its next holder
+type transferOp struct {
+	env      bf_tx.Envelope
+	id       string // Latest version of the bill of lading, whose state changes.
+	original string
+	to       Holder
+}
+
+// checkTransfer checks a transfer names one endorsee, or a bearer key, and that the role rules allow its signer,
+// the current holder by default, to make it.
+func (app *BftApplication) checkTransfer(env bf_tx.Envelope) (operation, uint32, string) {
+	var transfer bf_tx.Transfer
+	if err := json.Unmarshal(env.Payload, &transfer); err != nil {
+		return nil, CodeTypeEncodingError, "Cannot decode transfer: " + err.Error()
+	}
+
+	var to Holder
+	switch {
+	case transfer.To != "" && transfer.Bearer == nil:
+		if !app.state.Has(partyKey(transfer.To)) {
+			return nil, CodeTypeUnknownParty, "Party " + transfer.To + " does not exist."
+		}
+		to = Holder{Party: transfer.To}
+	case transfer.To == "" && transfer.Bearer != nil:
+		if _, err := tmcrypto.PubKeyFromBytes(transfer.Bearer); err != nil {
+			return nil, CodeTypeEncodingError, "Invalid bearer public key: " + err.Error()
+		}
+		to = Holder{Key: transfer.Bearer}
+	default:
+		return nil, CodeTypeValidationError, "Transfer needs either an endorsee party or a bearer key."
+	}
+
+	if env.Signer == nil {
+		return nil, CodeTypeUnsigned, "Transfer of BF_TX " + transfer.Id + " is not signed."
+	}
+	if !app.hasBFTX(transfer.Id) {
+		return nil, CodeTypeUnknownBFTX, "BF_TX " + transfer.Id + " does not exist."
+	}
+	original := app.lineage(transfer.Id, 0)[0]
+	title, ok := app.title(original)
+	if !ok {
+		return nil, CodeTypeUnauthorized, "BF_TX " + original + " has no holder, it cannot be transferred."
+	}
+	latest := app.latest(original)
+	if resCode, resLog := app.checkTransition(latest, bf_tx.StateTransferred); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	bftx, _ := app.getBFTX(latest, 0)
+	if resCode, resLog := app.authorize(env, env.Signer, original, bftx); resCode != code.CodeTypeOK {
+		return nil, resCode, resLog
+	}
+	if sameHolder(title.Holder, to) {
+		return nil, CodeTypeValidationError, "BF_TX " + original + " is already held by the endorsee."
+	}
+
+	return transferOp{env, latest, original, to}, code.CodeTypeOK, ""
+}
+
+func (op transferOp) deliver(app *BftApplication) types.ResponseDeliverTx {
+	title, _ := app.title(op.original)
+	title.Endorsements = append(title.Endorsements, Endorsement{
+		From:   title.Holder,
+		To:     op.to,
+		Signer: op.env.Signer,
+		Height: app.blockHeight(),
+		Time:   app.blockTime(),
+	})
+	title.Holder = op.to
+	app.setTitle(op.original, title)
+	app.setState(op.id, bf_tx.StateTransferred)
+	app.addHistory(op.original, op.env, op.env.Payload)
+
+	tags := []*types.KVPair{
+		{Key: TagID, ValueType: types.KVPair_STRING, ValueString: op.original},
+		opTag(op.env.Type),
+		stateTag(bf_tx.StateTransferred),
+	}
+	if op.to.Party != "" {
+		tags = append(tags, &types.KVPair{Key: TagHolder, ValueType: types.KVPair_STRING, ValueString: op.to.Party})
+	}
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK, Tags: tags}
+}
+
+// sameHolder reports whether two holders are the same party or key
+func sameHolder(a Holder, b Holder) bool {
+	return a.Party == b.Party && bytes.Equal(a.Key, b.Key)
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,333 @@
+// File: ./blockfreight/lib/bft/validators.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package bft
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+
+	// ===============
+	// Tendermint Core
+	// ===============
+	"github.com/tendermint/abci/example/code"
+	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
+)
+
+// ValidatorSetChangePrefix marks a transaction as a validator set change instead of a BF_TX
+const ValidatorSetChangePrefix string = "val:"
+
+// ValidatorApprovalPrefix is the prefix of the state entries that hold, under the sequence of the next validator
+// set change, the changes proposed for it and the validators that approved each one.
+const ValidatorApprovalPrefix string = "valapproval:"
+
+// validatorSequenceKey is the state key where the application keeps the sequence of the last validator set change
+// applied
+var validatorSequenceKey = []byte("bftx/valsequence")
+
+// ValidatorSetChange adds, updates or removes (Power 0) a validator. It is the approval of a validator that is
+// already in the set, and the change is applied once the validators that approved it hold the VoteThreshold
+// parameter of the voting power.
+type ValidatorSetChange struct {
+	ChainID   string `json:"ChainID"`   // Chain the change is made on, so it cannot be replayed on another chain.
+	Sequence  uint64 `json:"Sequence"`  // Sequence that follows the last validator set change applied.
+	PubKey    []byte `json:"PubKey"`    // go-wire encoded public key of the validator to change.
+	Power     int64  `json:"Power"`     // New voting power of the validator, 0 to remove it.
+	Signer    []byte `json:"Signer"`    // go-wire encoded public key of the validator approving the change.
+	Signature []byte `json:"Signature"` // go-wire encoded signature of the signer over SignBytes.
+}
+
+// ValidatorApproval is a validator set change proposed for a sequence, and the validators that approved it
+type ValidatorApproval struct {
+	PubKey  []byte   `json:"PubKey"`
+	Power   int64    `json:"Power"`
+	Signers [][]byte `json:"Signers"` // go-wire encoded public keys of the validators that approved it.
+}
+
+// SignBytes returns the bytes the approving validator signs
+func (change ValidatorSetChange) SignBytes() []byte {
+	signBytes, _ := json.Marshal(struct {
+		ChainID  string `json:"ChainID"`
+		Sequence uint64 `json:"Sequence"`
+		PubKey   []byte `json:"PubKey"`
+		Power    int64  `json:"Power"`
+	}{change.ChainID, change.Sequence, change.PubKey, change.Power})
+	return signBytes
+}
+
+// MakeValSetChangeTx builds the transaction to broadcast for a signed validator set change
+func MakeValSetChangeTx(change ValidatorSetChange) ([]byte, error) {
+	content, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(ValidatorSetChangePrefix), content...), nil
+}
+
+func isValidatorTx(tx []byte) bool {
+	return bytes.HasPrefix(tx, []byte(ValidatorSetChangePrefix))
+}
+
+func validatorKey(pubKey []byte) []byte {
+	return append([]byte(ValidatorSetChangePrefix), pubKey...)
+}
+
+// Validators returns the current validator set
+func (app *BftApplication) Validators() (validators []*types.Validator) {
+	app.state.IterateRange([]byte(ValidatorSetChangePrefix), prefixEnd([]byte(ValidatorSetChangePrefix)), true, func(key, value []byte) bool {
+		validator := new(types.Validator)
+		if err := types.ReadMessage(bytes.NewBuffer(value), validator); err == nil {
+			validators = append(validators, validator)
+		}
+		return false
+	})
+	return
+}
+
+// InitChain seeds the validator set with the validators in the genesis file, and the state with the genesis state
+// set with SetGenesisState: the chain Id, the parameters, the parties, the BF_TX records and their titles,
+// authorities, co-signatures and histories, the nonces, the quotas and the proposals
+func (app *BftApplication) InitChain(req types.RequestInitChain) types.ResponseInitChain {
+	if app.genesis.ChainID != "" {
+		app.state.Set(chainIDKey, []byte(app.genesis.ChainID))
+	}
+	for _, v := range req.Validators {
+		if err := app.setValidator(v); err != nil {
+			panic(err)
+		}
+	}
+	if err := app.setParams(app.genesis.Params); err != nil {
+		panic(err)
+	}
+	if err := app.loadGenesis(); err != nil {
+		panic(err)
+	}
+	return types.ResponseInitChain{}
+}
+
+// checkValidatorTx decodes a validator set change and checks it is made on this chain for the next sequence, is
+// signed by a current validator that did not approve it yet, and leaves at least one validator in the set.
+func (app *BftApplication) checkValidatorTx(tx []byte) (ValidatorSetChange, uint32, string) {
+	var change ValidatorSetChange
+	if err := json.Unmarshal(tx[len(ValidatorSetChangePrefix):], &change); err != nil {
+		return change, CodeTypeEncodingError, "Cannot decode validator set change: " + err.Error()
+	}
+	if _, err := tmcrypto.PubKeyFromBytes(change.PubKey); err != nil {
+		return change, CodeTypeEncodingError, "Invalid validator public key: " + err.Error()
+	}
+	if change.Power < 0 {
+		return change, CodeTypeValidationError, "Validator power cannot be negative."
+	}
+	if change.ChainID != app.ChainID() {
+		return change, CodeTypeWrongChain, "Validator set change is made on chain " + change.ChainID + ", not on " + app.ChainID() + "."
+	}
+	if resCode, resLog := checkSequence(change.Sequence, app.validatorSequence()); resCode != code.CodeTypeOK {
+		return change, resCode, resLog
+	}
+
+	if !app.state.Has(validatorKey(change.Signer)) {
+		return change, CodeTypeUnauthorized, "Validator set changes must be signed by a current validator."
+	}
+	signer, err := tmcrypto.PubKeyFromBytes(change.Signer)
+	if err != nil {
+		return change, CodeTypeEncodingError, "Invalid signer public key: " + err.Error()
+	}
+	signature, err := tmcrypto.SignatureFromBytes(change.Signature)
+	if err != nil || !signer.VerifyBytes(change.SignBytes(), signature) {
+		return change, CodeTypeBadSignature, "Validator set change does not match its signature."
+	}
+	if approval, ok := app.validatorApproval(change); ok && hasKey(approval.Signers, change.Signer) {
+		return change, CodeTypeDuplicateVote, "The signer already approved this validator set change."
+	}
+
+	if change.Power == 0 {
+		if !app.state.Has(validatorKey(change.PubKey)) {
+			return change, CodeTypeValidationError, "Cannot remove a validator that is not in the set."
+		}
+		if len(app.Validators()) == 1 {
+			return change, CodeTypeValidationError, "Cannot remove the last validator."
+		}
+	}
+
+	return change, code.CodeTypeOK, ""
+}
+
+// deliverValidatorTx records the approval of a validator set change, and applies the change once the validators
+// that approved it hold the VoteThreshold of the voting power. EndBlock then reports it to Tendermint, and the
+// other changes proposed for its sequence are dropped.
+func (app *BftApplication) deliverValidatorTx(tx []byte) types.ResponseDeliverTx {
+	change, resCode, resLog := app.checkValidatorTx(tx)
+	if resCode != code.CodeTypeOK {
+		return types.ResponseDeliverTx{Code: resCode, Log: resLog}
+	}
+
+	approvals := app.validatorApprovals(change.Sequence)
+	approval, ok := app.validatorApproval(change)
+	if !ok {
+		approval = ValidatorApproval{PubKey: change.PubKey, Power: change.Power}
+		approvals = append(approvals, approval)
+	}
+	approval.Signers = append(approval.Signers, change.Signer)
+	for i := range approvals {
+		if bytes.Equal(approvals[i].PubKey, change.PubKey) && approvals[i].Power == change.Power {
+			approvals[i] = approval
+		}
+	}
+
+	var power, total int64
+	for _, v := range app.Validators() {
+		total += v.Power
+		if hasKey(approval.Signers, v.PubKey) {
+			power += v.Power
+		}
+	}
+	if power*100 < int64(app.Params().VoteThreshold)*total {
+		value, _ := json.Marshal(approvals)
+		app.state.Set(validatorApprovalKey(change.Sequence), value)
+		return types.ResponseDeliverTx{Code: code.CodeTypeOK}
+	}
+
+	v := &types.Validator{PubKey: change.PubKey, Power: change.Power}
+	if err := app.setValidator(v); err != nil {
+		return types.ResponseDeliverTx{Code: CodeTypeEncodingError, Log: "Cannot encode validator: " + err.Error()}
+	}
+	app.changes = append(app.changes, v)
+	app.state.Remove(validatorApprovalKey(change.Sequence))
+	app.state.Set(validatorSequenceKey, []byte(strconv.FormatUint(change.Sequence, 10)))
+
+	return types.ResponseDeliverTx{Code: code.CodeTypeOK}
+}
+
+// validatorSequence returns the sequence of the last validator set change applied, 0 if none was
+func (app *BftApplication) validatorSequence() uint64 {
+	_, value := app.state.Get(validatorSequenceKey)
+	sequence, _ := strconv.ParseUint(string(value), 10, 64)
+	return sequence
+}
+
+// validatorApprovals returns the validator set changes proposed for a sequence, with their approvals
+func (app *BftApplication) validatorApprovals(sequence uint64) []ValidatorApproval {
+	var approvals []ValidatorApproval
+	if _, value := app.state.Get(validatorApprovalKey(sequence)); value != nil {
+		json.Unmarshal(value, &approvals)
+	}
+	return approvals
+}
+
+// validatorApproval returns the approvals of a validator set change, if it was proposed already
+func (app *BftApplication) validatorApproval(change ValidatorSetChange) (ValidatorApproval, bool) {
+	for _, approval := range app.validatorApprovals(change.Sequence) {
+		if bytes.Equal(approval.PubKey, change.PubKey) && approval.Power == change.Power {
+			return approval, true
+		}
+	}
+	return ValidatorApproval{}, false
+}
+
+func validatorApprovalKey(sequence uint64) []byte {
+	return []byte(ValidatorApprovalPrefix + strconv.FormatUint(sequence, 10))
+}
+
+// setValidator stores a validator in the state, or removes it if its power is 0
+func (app *BftApplication) setValidator(v *types.Validator) error {
+	key := validatorKey(v.PubKey)
+	if v.Power == 0 {
+		app.state.Remove(key)
+		return nil
+	}
+
+	value := bytes.NewBuffer(make([]byte, 0))
+	if err := types.WriteMessage(v, value); err != nil {
+		return err
+	}
+	app.state.Set(key, value.Bytes())
+	return nil
+}
+
+// prefixEnd returns the first key after all the keys starting with prefix
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
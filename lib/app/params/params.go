@@ -0,0 +1,278 @@
+// File: ./blockfreight/lib/params/params.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+// Package params is a package that defines the parameters of the Blockfreight™ Network, which the validators change
+// by vote without a software release, and the checks they make on a BF_TX.
+package params
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
+	"errors"        // Implements functions to manipulate errors.
+	"reflect"       // Implements run-time reflection, allowing a program to manipulate objects with arbitrary types.
+	"strconv"       // Implements conversions to and from string representations of basic data types.
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/authz" // Defines the role rules that authorize the operations on a BF_TX.
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+// AnyMode is the RequiredFields key of the fields every BF_TX must fill, whatever its ContainerMode.
+const AnyMode = "*"
+
+// Params are the parameters of the Blockfreight™ Network. They are kept in the state, one per name, and the
+// validators change them by voting on proposals. A parameter left out of a JSON object keeps its default value.
+type Params struct {
+	MaxTxBytes             int                 `json:"MaxTxBytes"`             // Largest transaction CheckTx admits, 0 for no limit.
+	RequiredFields         map[string][]string `json:"RequiredFields"`         // Properties fields a BF_TX must fill, by ContainerMode or AnyMode.
+	Incoterms              []string            `json:"Incoterms"`              // INCOTerms a BF_TX may use, any without them.
+	Rules                  authz.Rules         `json:"Rules"`                  // Role rules, e.g. which roles may issue.
+	PrivilegedRoles        []string            `json:"PrivilegedRoles"`        // Roles a party only acts with once a validator approved it.
+	Quorum                 int                 `json:"Quorum"`                 // Named parties that must co-sign a BF_TX, 0 for all of them.
+	VoteThreshold          int                 `json:"VoteThreshold"`          // Percentage of the validator voting power that passes a proposal.
+	VotingPeriod           int64               `json:"VotingPeriod"`           // Blocks a proposal stays open for votes.
+	QuotaWindow            int64               `json:"QuotaWindow"`            // Blocks of the window the account quotas count over.
+	QuotaTxs               int                 `json:"QuotaTxs"`               // Transactions a party may send in a window, 0 for no limit.
+	QuotaBytes             int                 `json:"QuotaBytes"`             // Transaction bytes a party may send in a window, 0 for no limit.
+	QuotaUnregisteredTxs   int                 `json:"QuotaUnregisteredTxs"`   // Transactions the keys no party holds may send in a window, all together, 0 for no limit.
+	QuotaUnregisteredBytes int                 `json:"QuotaUnregisteredBytes"` // Transaction bytes the keys no party holds may send in a window, all together, 0 for no limit.
+	QuotaRegistrationTxs   int                 `json:"QuotaRegistrationTxs"`   // Party registrations a key no party holds may send in a window, 0 for no limit.
+	QuotaRegistrationBytes int                 `json:"QuotaRegistrationBytes"` // Registration bytes a key no party holds may send in a window, 0 for no limit.
+}
+
+// Default returns the parameters of a network whose genesis does not set them.
+func Default() Params {
+	return Params{
+		MaxTxBytes:             1 << 20,
+		RequiredFields:         map[string][]string{},
+		Rules:                  authz.DefaultRules(),
+		PrivilegedRoles:        []string{bf_tx.RoleCarrier},
+		VoteThreshold:          67,
+		VotingPeriod:           10000,
+		QuotaWindow:            100,
+		QuotaTxs:               1000,
+		QuotaBytes:             10 << 20,
+		QuotaUnregisteredTxs:   100,
+		QuotaUnregisteredBytes: 1 << 20,
+		QuotaRegistrationTxs:   10,
+		QuotaRegistrationBytes: 64 << 10,
+	}
+}
+
+// Names returns the names of the parameters, the keys of their values in the state and in a proposal.
+func Names() []string {
+	var names []string
+	paramsType := reflect.TypeOf(Params{})
+	for i := 0; i < paramsType.NumField(); i++ {
+		names = append(names, paramsType.Field(i).Name)
+	}
+	return names
+}
+
+// Values returns the JSON value of every parameter, by name.
+func (params Params) Values() (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+	paramsValue := reflect.ValueOf(params)
+	for i := 0; i < paramsValue.NumField(); i++ {
+		value, err := json.Marshal(paramsValue.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values[paramsValue.Type().Field(i).Name] = value
+	}
+	return values, nil
+}
+
+// FromValues returns the parameters with the JSON values given by name, and the default value of the others.
+func FromValues(values map[string]json.RawMessage) (Params, error) {
+	params := Default()
+	paramsValue := reflect.ValueOf(&params).Elem()
+	for name, value := range values {
+		field := paramsValue.FieldByName(name)
+		if !field.IsValid() {
+			return params, errors.New("Unknown parameter " + name + ".")
+		}
+		decoded := reflect.New(field.Type())
+		if err := json.Unmarshal(value, decoded.Interface()); err != nil {
+			return params, errors.New("Invalid value of parameter " + name + ": " + err.Error())
+		}
+		field.Set(decoded.Elem())
+	}
+	return params, nil
+}
+
+// UnmarshalJSON decodes parameters, giving the ones left out their default value.
+func (params *Params) UnmarshalJSON(data []byte) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	decoded, err := FromValues(values)
+	*params = decoded
+	return err
+}
+
+// Apply returns the parameters with the changes of a proposal, new JSON values by name, once they validate.
+func (params Params) Apply(changes map[string]json.RawMessage) (Params, error) {
+	values, err := params.Values()
+	if err != nil {
+		return params, err
+	}
+	for name, value := range changes {
+		if _, ok := values[name]; !ok {
+			return params, errors.New("Unknown parameter " + name + ".")
+		}
+		values[name] = value
+	}
+	changed, err := FromValues(values)
+	if err != nil {
+		return params, err
+	}
+	return changed, changed.Validate()
+}
+
+// Validate checks the parameters are in range, and only refer to known Properties fields, roles and relations.
+func (params Params) Validate() error {
+	if params.MaxTxBytes < 0 {
+		return errors.New("Parameter MaxTxBytes cannot be negative.")
+	}
+	properties := reflect.TypeOf(bf_tx.Properties{})
+	for mode, fields := range params.RequiredFields {
+		for _, field := range fields {
+			if _, ok := properties.FieldByName(field); !ok {
+				return errors.New("Parameter RequiredFields requires unknown field " + field + " for container mode " + mode + ".")
+			}
+		}
+	}
+	if len(params.Rules) == 0 {
+		return errors.New("Parameter Rules cannot be empty.")
+	}
+	if err := params.Rules.Validate(); err != nil {
+		return err
+	}
+	for _, role := range params.PrivilegedRoles {
+		if !contains(bf_tx.Roles, role) {
+			return errors.New("Parameter PrivilegedRoles has an unknown role " + role + ".")
+		}
+	}
+	if params.Quorum < 0 {
+		return errors.New("Parameter Quorum cannot be negative.")
+	}
+	if params.VoteThreshold < 1 || params.VoteThreshold > 100 {
+		return errors.New("Parameter VoteThreshold must be a percentage between 1 and 100, not " + strconv.Itoa(params.VoteThreshold) + ".")
+	}
+	if params.VotingPeriod < 1 {
+		return errors.New("Parameter VotingPeriod must be at least 1 block.")
+	}
+	if params.QuotaWindow < 1 {
+		return errors.New("Parameter QuotaWindow must be at least 1 block.")
+	}
+	if params.QuotaTxs < 0 || params.QuotaBytes < 0 || params.QuotaUnregisteredTxs < 0 || params.QuotaUnregisteredBytes < 0 {
+		return errors.New("Parameters QuotaTxs, QuotaBytes, QuotaUnregisteredTxs and QuotaUnregisteredBytes cannot be negative.")
+	}
+	if params.QuotaRegistrationTxs < 0 || params.QuotaRegistrationBytes < 0 {
+		return errors.New("Parameters QuotaRegistrationTxs and QuotaRegistrationBytes cannot be negative.")
+	}
+	return nil
+}
+
+// CheckBFTX returns an error if a BF_TX leaves out a field the parameters require for its ContainerMode, or uses
+// INCOTerms they do not allow.
+func (params Params) CheckBFTX(bftx bf_tx.BF_TX) error {
+	properties := reflect.ValueOf(bftx.Properties)
+	for _, mode := range []string{AnyMode, bftx.Properties.ContainerMode} {
+		for _, field := range params.RequiredFields[mode] {
+			value := properties.FieldByName(field)
+			if !value.IsValid() || reflect.DeepEqual(value.Interface(), reflect.Zero(value.Type()).Interface()) {
+				return errors.New("BF_TX " + bftx.Id + " needs a " + field + " with container mode " + bftx.Properties.ContainerMode + ".")
+			}
+		}
+	}
+	if len(params.Incoterms) > 0 && !contains(params.Incoterms, bftx.Properties.INCOTerms) {
+		return errors.New("BF_TX " + bftx.Id + " uses INCOTerms " + bftx.Properties.INCOTerms + ", which the network does not allow.")
+	}
+	return nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
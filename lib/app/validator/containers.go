@@ -0,0 +1,201 @@
+// File: ./blockfreight/lib/validator/containers.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package validator
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"regexp"  // Implements regular expression search.
+	"strconv" // Implements conversions to and from string representations of basic data types.
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+var (
+	containerNumberPattern = regexp.MustCompile(`^[A-Z]{3}[UJZ][0-9]{7}$`)
+	hsCodePattern          = regexp.MustCompile(`^[0-9]{4}(\.?[0-9]{2}){1,3}$`)
+)
+
+// validateContainers checks the containers of a BF_TX and their cargo items, and that their totals are the
+// packages, gross weight and volume the BF_TX states, except for the fields encrypted with saber.
+func validateContainers(properties bf_tx.Properties, encrypted map[string]bool) (bool, string) {
+	for i, container := range properties.Containers {
+		field := "bftx.Properties.Containers[" + strconv.Itoa(i) + "]"
+		if container.Container != "" && !containerNumber(container.Container) {
+			return false, field + ".Container is not an ISO 6346 container number."
+		}
+		if valid, err := validateCargo(field, container.Packages, container.GrossWeight, container.Volume, properties); !valid {
+			return false, err
+		}
+
+		for j, item := range container.Items {
+			itemField := field + ".Items[" + strconv.Itoa(j) + "]"
+			if item.HSCode != "" && !hsCodePattern.MatchString(item.HSCode) {
+				return false, itemField + ".HSCode is not a Harmonized System code."
+			}
+			if valid, err := validateCargo(itemField, item.Packages, item.GrossWeight, item.Volume, properties); !valid {
+				return false, err
+			}
+		}
+		if len(container.Items) == 0 {
+			continue
+		}
+		totals, err := bf_tx.ItemTotals(container.Items)
+		if err != nil {
+			return false, field + ".Items do not add up, " + err.Error()
+		}
+		if mismatches := totals.Mismatches(bf_tx.CargoTotals{Packages: container.Packages, GrossWeight: container.GrossWeight, Volume: container.Volume}); len(mismatches) > 0 {
+			return false, field + "." + mismatches[0] + " is not the total of its Items."
+		}
+	}
+
+	if len(properties.Containers) == 0 {
+		return true, ""
+	}
+	if properties.Container != "" && !encrypted["container"] && !containsContainer(properties.Containers, properties.Container) {
+		return false, "bftx.Properties.Container is not one of its Containers."
+	}
+	totals, err := bf_tx.ContainerTotals(properties.Containers)
+	if err != nil {
+		return false, "bftx.Properties.Containers do not add up, " + err.Error()
+	}
+	stated := bf_tx.CargoTotals{Packages: properties.Packages}
+	if !encrypted["grossweight"] {
+		stated.GrossWeight = properties.GrossWeight
+	}
+	if !encrypted["volume"] {
+		stated.Volume = properties.Volume
+	}
+	if mismatches := totals.Mismatches(stated); len(mismatches) > 0 {
+		return false, "bftx.Properties." + mismatches[0] + " is not the total of its Containers."
+	}
+	return true, ""
+}
+
+// validateCargo checks the package count, gross weight and volume of a container or cargo item
+func validateCargo(field string, packages bf_tx.Count, weight bf_tx.Quantity, volume bf_tx.Quantity, properties bf_tx.Properties) (bool, string) {
+	if !packages.Valid() {
+		return false, field + ".Packages is not a package count."
+	}
+	if !weight.Valid() {
+		return false, field + ".GrossWeight is not a decimal quantity."
+	}
+	if !unitMatches(weight, properties.UnitOfWeight) {
+		return false, field + ".GrossWeight is not in the bftx.Properties.UnitOfWeight."
+	}
+	if !volume.Valid() {
+		return false, field + ".Volume is not a decimal quantity."
+	}
+	if !unitMatches(volume, properties.UnitOfVolume) {
+		return false, field + ".Volume is not in the bftx.Properties.UnitOfVolume."
+	}
+	return true, ""
+}
+
+// containerNumber reports whether a container number has the ISO 6346 form and check digit, e.g. CSQU3054383
+func containerNumber(number string) bool {
+	if !containerNumberPattern.MatchString(number) {
+		return false
+	}
+
+	// Letters count from 10, skipping the multiples of 11, and each character is weighted by 2 to its position
+	sum := 0
+	for i, char := range number[:10] {
+		value := int(char - '0')
+		if char >= 'A' {
+			value = 10
+			for letter := 'A'; letter < char; letter++ {
+				if value++; value%11 == 0 {
+					value++
+				}
+			}
+		}
+		sum += value << uint(i)
+	}
+	return sum%11%10 == int(number[10]-'0')
+}
+
+// containsContainer reports whether a container number is one of the containers
+func containsContainer(containers []bf_tx.Container, number string) bool {
+	for _, container := range containers {
+		if container.Container == number {
+			return true
+		}
+	}
+	return false
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -0,0 +1,210 @@
+// File: ./blockfreight/lib/validator/parties.go
+// Summary: Application code for Blockfreight™ | The blockchain of global freight.
+// License: MIT License
+// Company: Blockfreight, Inc.
+// Author: Julian Nunez, Neil Tran, Julian Smith, Gian Felipe & contributors
+// Site: https://blockfreight.com
+// Support: <support@blockfreight.com>
+
+// Copyright © 2017 Blockfreight, Inc. All Rights Reserved.
+
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN
+// CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// =================================================================================================================================================
+// =================================================================================================================================================
+//
+// BBBBBBBBBBBb     lll                                kkk             ffff                         iii                  hhh            ttt
+// BBBB``````BBBB   lll                                kkk            fff                           ```                  hhh            ttt
+// BBBB      BBBB   lll      oooooo        ccccccc     kkk    kkkk  fffffff  rrr  rrr    eeeee      iii     gggggg ggg   hhh  hhhhh   tttttttt
+// BBBBBBBBBBBB     lll    ooo    oooo    ccc    ccc   kkk   kkk    fffffff  rrrrrrrr eee    eeee   iii   gggg   ggggg   hhhh   hhhh  tttttttt
+// BBBBBBBBBBBBBB   lll   ooo      ooo   ccc           kkkkkkk        fff    rrrr    eeeeeeeeeeeee  iii  gggg      ggg   hhh     hhh    ttt
+// BBBB       BBB   lll   ooo      ooo   ccc           kkkk kkkk      fff    rrr     eeeeeeeeeeeee  iii   ggg      ggg   hhh     hhh    ttt
+// BBBB      BBBB   lll   oooo    oooo   cccc    ccc   kkk   kkkk     fff    rrr      eee      eee  iii    ggg    gggg   hhh     hhh    tttt    ....
+// BBBBBBBBBBBBB    lll     oooooooo       ccccccc     kkk     kkkk   fff    rrr       eeeeeeeee    iii     gggggg ggg   hhh     hhh     ttttt  ....
+//                                                                                                        ggg      ggg
+//   Blockfreight™ | The blockchain of global freight.                                                      ggggggggg
+//
+// =================================================================================================================================================
+// =================================================================================================================================================
+
+package validator
+
+import (
+	// =======================
+	// Golang Standard library
+	// =======================
+	"regexp"  // Implements regular expression search.
+	"strings" // Implements simple functions to manipulate UTF-8 encoded strings.
+
+	// ======================
+	// Blockfreight™ packages
+	// ======================
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+)
+
+var (
+	leiPattern   = regexp.MustCompile(`^[0-9A-Z]{18}[0-9]{2}$`)
+	glnPattern   = regexp.MustCompile(`^[0-9]{13}$`)
+	eoriPattern  = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z]{1,15}$`)
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// countryCodes are the ISO 3166-1 alpha-2 codes of the countries and territories
+var countryCodes = codeSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS BT BV BW BY BZ
+	CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO
+	FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE
+	JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO
+	MP MQ MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW
+	PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM
+	TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+
+// validateParties checks the parties of a BF_TX, except for the fields encrypted with saber, and that only its
+// consignee is consigned to order, with a party to notify of the arrival of the goods.
+func validateParties(properties bf_tx.Properties, encrypted map[string]bool) (bool, string) {
+	parties := []struct {
+		field string
+		party *bf_tx.TradeParty
+	}{
+		{"Shipper", &properties.Shipper},
+		{"Consignee", &properties.Consignee},
+		{"NotifyAddress", &properties.NotifyAddress},
+		{"DeliverAgent", &properties.DeliverAgent},
+		{"ReceiveAgent", &properties.ReceiveAgent},
+		{"MasterInfo.Details", properties.MasterInfo.Details},
+		{"AgentForMaster.Details", properties.AgentForMaster.Details},
+		{"AgentForOwner.Details", properties.AgentForOwner.Details},
+	}
+	for _, party := range parties {
+		field := "bftx.Properties." + party.field
+		if party.party == nil || encrypted[strings.ToLower(strings.Split(party.field, ".")[0])] {
+			continue
+		}
+		if valid, err := validateParty(field, *party.party); !valid {
+			return false, err
+		}
+		if party.party.ToOrder && party.field != "Consignee" {
+			return false, field + " is consigned to order, only the bftx.Properties.Consignee can be."
+		}
+		if party.party.Party != "" && strings.HasSuffix(party.field, ".Details") {
+			return false, field + ".Party must be left out, the Party beside the Details names the co-signer."
+		}
+	}
+
+	if properties.Consignee.ToOrder && properties.NotifyAddress.Empty() {
+		return false, "bftx.Properties.Consignee is consigned to order, it needs a bftx.Properties.NotifyAddress."
+	}
+	return true, ""
+}
+
+// validateParty checks the name, country and identifiers of a party
+func validateParty(field string, party bf_tx.TradeParty) (bool, string) {
+	if party.Name == "" && !party.ToOrder && !party.Empty() {
+		return false, field + ".Name is left out."
+	}
+	if party.Country != "" && !countryCodes[party.Country] {
+		return false, field + ".Country is not an ISO 3166-1 alpha-2 country code."
+	}
+	if party.LEI != "" && !lei(party.LEI) {
+		return false, field + ".LEI is not an ISO 17442 Legal Entity Identifier."
+	}
+	if party.GLN != "" && !gln(party.GLN) {
+		return false, field + ".GLN is not a GS1 Global Location Number."
+	}
+	if party.EORI != "" && !eoriPattern.MatchString(party.EORI) {
+		return false, field + ".EORI is not an EORI number."
+	}
+	if party.Email != "" && !emailPattern.MatchString(party.Email) {
+		return false, field + ".Email is not an email address."
+	}
+	return true, ""
+}
+
+// lei reports whether a Legal Entity Identifier has the ISO 17442 form and check digits, e.g. 5493001KJTIIGC8Y1R12
+func lei(code string) bool {
+	if !leiPattern.MatchString(code) {
+		return false
+	}
+
+	// ISO 7064 MOD 97-10: the number the characters spell, letters counting from A as 10, is 1 modulo 97
+	remainder := 0
+	for _, char := range code {
+		if char >= 'A' {
+			remainder = (remainder*100 + int(char-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(char-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+// gln reports whether a Global Location Number has 13 digits and the GS1 check digit, e.g. 4006381333931
+func gln(code string) bool {
+	if !glnPattern.MatchString(code) {
+		return false
+	}
+
+	// The digits before the check digit are weighted 3 and 1 alternately, from the rightmost one
+	sum := 0
+	for i := 11; i >= 0; i-- {
+		weight := 1
+		if (11-i)%2 == 0 {
+			weight = 3
+		}
+		sum += int(code[i]-'0') * weight
+	}
+	return (10-sum%10)%10 == int(code[12]-'0')
+}
+
+// codeSet returns the set of the codes of a list separated by white space
+func codeSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(list) {
+		set[code] = true
+	}
+	return set
+}
+
+// =================================================
+// Blockfreight™ | The blockchain of global freight.
+// =================================================
+
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBB                    BBBBBBBBBBBBBBBBBBB
+// BBBBBBB                       BBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBB         BBBBBBBBBBBBBBBB
+// BBBBBBB                     BBBBBBBBBBBBBBBBBB
+// BBBBBBB                        BBBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBBB       BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBBB        BBBBBBBBBBBBBB
+// BBBBBBB       BBBBBBBBB        BBB       BBBBB
+// BBBBBBB                       BBBB       BBBBB
+// BBBBBBB                    BBBBBBB       BBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+// BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB
+
+// ==================================================
+// Blockfreight™ | The blockchain for global freight.
+// ==================================================
@@ -87,8 +87,14 @@ func ValidateBFTX(bftx bf_tx.BF_TX) (string, error) {
 
 // ValidateFields is a function that receives the BF_TX, validates every field in the BF_TX and return true or false, and a message if some field is wrong.
 func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
-	if reflect.TypeOf(bftx.Properties.Shipper) != reflect.TypeOf("s") {
-		return false, "bftx.Properties.Shipperis not a string."
+	return ValidateFieldsExcept(bftx, nil)
+}
+
+// ValidateFieldsExcept validates the BF_TX like ValidateFields, but does not check the typed values of the fields
+// it is given, keyed in lower case. The fields encrypted with saber hold ciphertext instead of their values.
+func ValidateFieldsExcept(bftx bf_tx.BF_TX, encrypted map[string]bool) (bool, string) {
+	if valid, err := validateParties(bftx.Properties, encrypted); !valid {
+		return false, err
 	}
 	// if reflect.TypeOf(bftx.Properties.BolNum).Kind() != reflect.Int {
 	// 	return false, "bftx.Properties.BolNum is not a number."
@@ -96,9 +102,6 @@ func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
 	// if reflect.TypeOf(bftx.Properties.RefNum).Kind() != reflect.Int {
 	// 	return false, "bftx.Properties.RefNum is not a number."
 	// }
-	if reflect.TypeOf(bftx.Properties.Consignee) != reflect.TypeOf("s") {
-		return false, "bftx.Properties.Consignee is not a string."
-	}
 	// if reflect.TypeOf(bftx.Properties.Vessel).Kind() != reflect.Int {
 	// 	return false, "bftx.Properties.Vessel is not a number."
 	// }
@@ -111,29 +114,29 @@ func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
 	if reflect.TypeOf(bftx.Properties.UnitOfVolume) != reflect.TypeOf("s") {
 		return false, "bftx.Properties.UnitOfVolume is not a string."
 	}
-	if reflect.TypeOf(bftx.Properties.NotifyAddress) != reflect.TypeOf("s") {
-		return false, "bftx.Properties.NotifyAddress is not a string."
-	}
 	if reflect.TypeOf(bftx.Properties.DescOfGoods) != reflect.TypeOf("s") {
 		return false, "bftx.Properties.DescOfGoods is not a string."
 	}
-	// if reflect.TypeOf(bftx.Properties.GrossWeight).Kind() != reflect.Float64 {
-	// 	return false, "bftx.Properties.GrossWeight is not a float64."
-	// }
-	// if reflect.TypeOf(bftx.Properties.FreightPayableAmt).Kind() != reflect.Int {
-	// 	return false, "bftx.Properties.FreightPayableAmt is not an int."
-	// }
-	// if reflect.TypeOf(bftx.Properties.FreightAdvAmt).Kind() != reflect.Int {
-	// 	return false, "bftx.Properties.FreightAdvAmt is not an int."
-	// }
+	if !encrypted["grossweight"] && !bftx.Properties.GrossWeight.Valid() {
+		return false, "bftx.Properties.GrossWeight is not a decimal quantity."
+	}
+	if !encrypted["grossweight"] && !unitMatches(bftx.Properties.GrossWeight, bftx.Properties.UnitOfWeight) {
+		return false, "bftx.Properties.GrossWeight is not in the bftx.Properties.UnitOfWeight."
+	}
+	if !encrypted["freightpayableamt"] && !bftx.Properties.FreightPayableAmt.Valid() {
+		return false, "bftx.Properties.FreightPayableAmt is not a decimal amount of an ISO 4217 currency."
+	}
+	if !encrypted["freightadvamt"] && !bftx.Properties.FreightAdvAmt.Valid() {
+		return false, "bftx.Properties.FreightAdvAmt is not a decimal amount of an ISO 4217 currency."
+	}
 	if reflect.TypeOf(bftx.Properties.GeneralInstructions) != reflect.TypeOf("s") {
 		return false, "bftx.Properties.GeneralInstructions is not a string."
 	}
-	if reflect.TypeOf(bftx.Properties.DateShipped) != reflect.TypeOf("s") {
-		return false, "bftx.Properties.DateShipped is not a string."
+	if !encrypted["dateshipped"] && !bftx.Properties.DateShipped.Valid() {
+		return false, "bftx.Properties.DateShipped is not an ISO 8601 date."
 	}
-	if reflect.TypeOf(bftx.Properties.IssueDetails.DateOfIssue) != reflect.TypeOf("s") {
-		return false, "bftx.Properties.IssueDetails.Properties.PlaceOfIssue is not a string."
+	if !encrypted["dateofissue"] && !bftx.Properties.IssueDetails.DateOfIssue.Valid() {
+		return false, "bftx.Properties.IssueDetails.Properties.DateOfIssue is not an ISO 8601 date."
 	}
 	if reflect.TypeOf(bftx.Properties.IssueDetails.PlaceOfIssue) != reflect.TypeOf("s") {
 		return false, "bftx.Properties.IssueDetails.Properties.PlaceOfIssue is not a string."
@@ -181,15 +184,9 @@ func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
 	if reflect.TypeOf(bftx.Properties.EncryptionMetaData).Kind() != reflect.String {
 		return false, "bftx.Properties.EncryptionMetaData is not a string."
 	}
-	if reflect.TypeOf(bftx.Properties.Consignee).Kind() != reflect.String {
-		return false, "bftx.Properties.Consignee is not a string."
-	}
 	if reflect.TypeOf(bftx.Properties.HouseBill).Kind() != reflect.String {
 		return false, "bftx.Properties.HouseBill is not a string."
 	}
-	if reflect.TypeOf(bftx.Properties.ReceiveAgent).Kind() != reflect.String {
-		return false, "bftx.Properties.ReceiveAgent is not a string."
-	}
 	if reflect.TypeOf(bftx.Properties.Destination).Kind() != reflect.String {
 		return false, "bftx.Properties.Destination is not a string."
 	}
@@ -199,27 +196,27 @@ func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
 	if reflect.TypeOf(bftx.Properties.UnitOfWeight).Kind() != reflect.String {
 		return false, "bftx.Properties.UnitOfWeight is not a string."
 	}
-	// if reflect.TypeOf(bftx.Properties.Volume).Kind() != reflect.Float64 {
-	// 	return false, "bftx.Properties.Volume is not a float."
-	// }
+	if !encrypted["volume"] && !bftx.Properties.Volume.Valid() {
+		return false, "bftx.Properties.Volume is not a decimal quantity."
+	}
+	if !encrypted["volume"] && !unitMatches(bftx.Properties.Volume, bftx.Properties.UnitOfVolume) {
+		return false, "bftx.Properties.Volume is not in the bftx.Properties.UnitOfVolume."
+	}
 	if reflect.TypeOf(bftx.Properties.Container).Kind() != reflect.String {
 		return false, "bftx.Properties.Container is not a string."
 	}
 	if reflect.TypeOf(bftx.Properties.ContainerSeal).Kind() != reflect.String {
 		return false, "bftx.Properties.ContainerSeal is not a string."
 	}
-	// if reflect.TypeOf(bftx.Properties.Packages).Kind() != reflect.Int {
-	// 	return false, "bftx.Properties.Packages is not a string."
-	// }
+	if !encrypted["packages"] && !bftx.Properties.Packages.Valid() {
+		return false, "bftx.Properties.Packages is not a package count."
+	}
 	if reflect.TypeOf(bftx.Properties.PackType).Kind() != reflect.String {
 		return false, "bftx.Properties.PackType is not a string."
 	}
 	if reflect.TypeOf(bftx.Properties.INCOTerms).Kind() != reflect.String {
 		return false, "bftx.Properties.INCOTerms is not a string."
 	}
-	if reflect.TypeOf(bftx.Properties.DeliverAgent).Kind() != reflect.String {
-		return false, "bftx.Properties.DeliverAgent is not a string."
-	}
 	if reflect.TypeOf(bftx.Properties.ContainerMode).Kind() != reflect.String {
 		return false, "bftx.Properties.ContainerMode is not a string."
 	}
@@ -227,7 +224,12 @@ func ValidateFields(bftx bf_tx.BF_TX) (bool, string) {
 		return false, "bftx.Properties.ContainerType is not a string."
 	}
 
-	return true, ""
+	return validateContainers(bftx.Properties, encrypted)
+}
+
+// unitMatches reports whether a quantity is in the unit the BF_TX gives for it, when both give one
+func unitMatches(quantity bf_tx.Quantity, unit string) bool {
+	return quantity.Unit == "" || unit == "" || quantity.Unit == unit
 }
 
 // =================================================
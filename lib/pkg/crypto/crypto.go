@@ -49,23 +49,19 @@ import (
 	// =======================
 	// Golang Standard library
 	// =======================
+	"bytes" // Implements functions for the manipulation of byte slices.
 	"context"
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/md5" // Implements the Elliptic Curve Digital Signature Algorithm, as defined in FIPS 186-3.
+	"crypto/md5"    // Implements the MD5 hash algorithm as defined in RFC 1321.
+	"crypto/sha256" // Implements the SHA224 and SHA256 hash algorithms as defined in FIPS 180-4.
+	"encoding/hex"  // Implements hexadecimal encoding and decoding.
+	"io"            // Provides basic interfaces to I/O primitives.
 	"log"
-	// Implements several standard elliptic curves over prime fields.
-	// Implements the MD5 hash algorithm as defined in RFC 1321.
-	"crypto/rand" // Implements a cryptographically secure pseudorandom number generator.
-	"hash"        // Provides interfaces for hash functions.
-	"io"          // Provides basic interfaces to I/O primitives.
-	"math/big"    // Implements arbitrary-precision arithmetic (big numbers).
-	"strconv"     // Implements conversions to and from string representations of basic data types.
 
 	// ======================
 	// Blockfreight™ packages
 	// ======================
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx" // Defines the Blockfreight™ Transaction (BF_TX) transaction standard and provides some useful functions to work with the BF_TX.
+	tmcrypto "github.com/tendermint/go-crypto"
 	"google.golang.org/grpc"
 )
 
@@ -73,55 +69,117 @@ const (
 	address = "localhost:22222"
 )
 
-// SignBFTX has the whole process of signing each BF_TX.
-func SignBFTX(bftx bf_tx.BF_TX) (bf_tx.BF_TX, error) {
-
-	content, err := bf_tx.BFTXContent(bftx)
+// SignBFTX signs a BF_TX with the key of a registered party, so the network can tell who signed it.
+func SignBFTX(bftx bf_tx.BF_TX, privKey tmcrypto.PrivKey) (bf_tx.BF_TX, error) {
+	signed, err := bf_tx.Transition(bftx, bf_tx.StateSigned)
 	if err != nil {
 		return bftx, err
 	}
 
-	pubkeyCurve := elliptic.P256() //see http://golang.org/pkg/crypto/elliptic/#P256
-
-	privatekey := new(ecdsa.PrivateKey)
-	privatekey, err = ecdsa.GenerateKey(pubkeyCurve, rand.Reader) // this generates a public & private key pair
+	signed.Signer = privKey.PubKey().Bytes()
+	content, err := signContent(signed)
 	if err != nil {
 		return bftx, err
 	}
-	pubkey := privatekey.PublicKey
-
-	// Sign ecdsa style
-	var h hash.Hash
-	h = md5.New()
-	r := big.NewInt(0)
-	s := big.NewInt(0)
 
+	h := md5.New()
 	io.WriteString(h, content)
-	signhash := h.Sum(nil)
+	signed.Signhash = h.Sum(nil)
+	signed.Signature = hex.EncodeToString(privKey.Sign([]byte(content)).Bytes())
+
+	return signed, nil
+}
 
-	r, s, err = ecdsa.Sign(rand.Reader, privatekey, signhash)
+// VerifyBFTX reports whether a BF_TX is signed by its Signer, over its canonical encoding, and still has the content
+// it had when it was signed.
+func VerifyBFTX(bftx bf_tx.BF_TX) bool {
+	if bftx.Signature == "" || len(bftx.Signhash) == 0 {
+		return false
+	}
+	signer, err := tmcrypto.PubKeyFromBytes(bftx.Signer)
 	if err != nil {
-		return bftx, err
+		return false
+	}
+	signatureBytes, err := hex.DecodeString(bftx.Signature)
+	if err != nil {
+		return false
+	}
+	signature, err := tmcrypto.SignatureFromBytes(signatureBytes)
+	if err != nil {
+		return false
 	}
 
-	signature := r.Bytes()
-	signature = append(signature, s.Bytes()...)
+	content, err := signContent(bftx)
+	if err != nil {
+		return false
+	}
+	h := md5.New()
+	io.WriteString(h, content)
+	return bytes.Equal(h.Sum(nil), bftx.Signhash) && signer.VerifyBytes([]byte(content), signature)
+}
+
+// signContent returns the content a BF_TX is signed over, its canonical encoding
+func signContent(bftx bf_tx.BF_TX) (string, error) {
+	content, err := bf_tx.CanonicalBFTX(bftx)
+	return string(content), err
+}
 
-	sign := ""
-	for i, _ := range signature {
-		sign += strconv.Itoa(int(signature[i]))
+// DocumentHash returns the hash of a signed BF_TX the parties named in it co-sign: the SHA-256 of the content
+// its sender signed.
+func DocumentHash(bftx bf_tx.BF_TX) ([]byte, error) {
+	content, err := signContent(bftx)
+	if err != nil {
+		return nil, err
 	}
+	hash := sha256.Sum256([]byte(content))
+	return hash[:], nil
+}
 
-	// Verification
-	verifystatus := ecdsa.Verify(&pubkey, signhash, r, s)
+// CosignBFTX returns the go-wire encoded signature of a party key over the document hash of a BF_TX.
+func CosignBFTX(bftx bf_tx.BF_TX, privKey tmcrypto.PrivKey) ([]byte, error) {
+	hash, err := DocumentHash(bftx)
+	if err != nil {
+		return nil, err
+	}
+	return privKey.Sign(hash).Bytes(), nil
+}
 
-	//Set Private Key and Sign to BF_TX
-	bftx.PrivateKey = *privatekey
-	bftx.Signhash = signhash
-	bftx.Signature = sign
-	bftx.Verified = verifystatus
+// VerifyCosignature reports whether a go-wire encoded signature is the signature of a public key over the
+// document hash of a BF_TX.
+func VerifyCosignature(bftx bf_tx.BF_TX, pubKey []byte, signatureBytes []byte) bool {
+	signer, err := tmcrypto.PubKeyFromBytes(pubKey)
+	if err != nil {
+		return false
+	}
+	signature, err := tmcrypto.SignatureFromBytes(signatureBytes)
+	if err != nil {
+		return false
+	}
+	hash, err := DocumentHash(bftx)
+	if err != nil {
+		return false
+	}
+	return signer.VerifyBytes(hash, signature)
+}
+
+// SignEnvelope signs a transaction envelope with the private key of its sender.
+func SignEnvelope(env bf_tx.Envelope, privKey tmcrypto.PrivKey) bf_tx.Envelope {
+	env.Signer = privKey.PubKey().Bytes()
+	env.Signature = privKey.Sign(env.SignBytes()).Bytes()
+	return env
+}
 
-	return bftx, nil
+// VerifyEnvelope reports whether a transaction envelope is signed by its Signer.
+func VerifyEnvelope(env bf_tx.Envelope) bool {
+	signer, err := tmcrypto.PubKeyFromBytes(env.Signer)
+	if err != nil {
+		return false
+	}
+	signature, err := tmcrypto.SignatureFromBytes(env.Signature)
+	if err != nil {
+		return false
+	}
+	return signer.VerifyBytes(env.SignBytes(), signature)
 }
 
 func CryptoTransaction(content string) []byte {
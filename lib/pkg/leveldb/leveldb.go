@@ -50,6 +50,7 @@ import (
 	// =======================
 	// Golang Standard library
 	// =======================
+	"bytes"         // Implements functions for the manipulation of byte slices.
 	"encoding/json" // Implements encoding and decoding of JSON as defined in RFC 4627.
 	"errors"        // Implements functions to manipulate errors.
 
@@ -135,9 +136,14 @@ func GetBfTx(id string) (bf_tx.BF_TX, error) {
 	return bftx, nil
 }
 
-// Verify is a function that receives a content and look for a BF_TX that has the same content.
-func Verify(jcontent string) ([]byte, error) {
-	var bftx bf_tx.BF_TX
+// Verify is a function that receives a BF_TX and looks for a stored BF_TX with the same document, compared by
+// their bf_tx.HashBFTX hash, so the Id, signature and state attributes do not count.
+func Verify(jbftx bf_tx.BF_TX) ([]byte, error) {
+	jhash, err := bf_tx.HashBFTX(jbftx)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := OpenDB(dbPath)
 	defer CloseDB(db)
 	if err != nil {
@@ -150,18 +156,16 @@ func Verify(jcontent string) ([]byte, error) {
 		value := iter.Value()
 
 		// Get a BF_TX by id
+		var bftx bf_tx.BF_TX
 		json.Unmarshal(value, &bftx)
 
-		// Reinitialize the BF_TX
-		bftx = bf_tx.Reinitialize(bftx)
-
-		// Get the BF_TX old_content in string format
-		content, err := bf_tx.BFTXContent(bftx)
+		// Hash the BF_TX document
+		hash, err := bf_tx.HashBFTX(bftx)
 		if err != nil {
 			return nil, err
 		}
 
-		if jcontent == content {
+		if bytes.Equal(jhash, hash) {
 			iter.Release()
 			//strconv.Atoi(string(buf))
 			return key, nil
@@ -172,6 +176,99 @@ func Verify(jcontent string) ([]byte, error) {
 	return nil, iter.Error()
 }
 
+// MigrationReport is the result of Migrate: the BF_TX of the DB by the schema version they were stored in, the
+// legacy amendment links it turned around or dropped, and the ones that could not be migrated.
+type MigrationReport struct {
+	Total    int
+	Current  int                         // BF_TX already stored in the current schema version.
+	Migrated map[bf_tx.SchemaVersion]int // BF_TX rewritten in the current schema version, by the version they were stored in.
+	Relinked map[string]string           // BF_TX that now name the BF_TX they amend, by id, from its legacy forward link.
+	Unlinked map[string]string           // Legacy forward links dropped, by the id of the BF_TX that had them, to a BF_TX missing from the DB or amending another one.
+	Failed   map[string]string           // Error of each BF_TX that could not be migrated, by id.
+}
+
+// Migrate is a function that rewrites every BF_TX stored in the DB in an earlier schema version in the current one,
+// all of them in one batch, and returns what it did. A BF_TX that can not be decoded is reported and left as is.
+// The legacy forward link of an amended BF_TX becomes the backward link of the BF_TX that amends it.
+func Migrate() (MigrationReport, error) {
+	report := MigrationReport{
+		Migrated: make(map[bf_tx.SchemaVersion]int),
+		Relinked: make(map[string]string),
+		Unlinked: make(map[string]string),
+		Failed:   make(map[string]string),
+	}
+	db, err := OpenDB(dbPath)
+	defer CloseDB(db)
+	if err != nil {
+		return report, err
+	}
+
+	// The BF_TX each legacy forward link names amends the one that has it
+	amends := make(map[string]string)
+	iter := db.NewIterator(nil, nil)
+	for iter.Next() {
+		successor, err := bf_tx.LegacyAmendedBy(iter.Value())
+		if err != nil || successor == "" {
+			continue
+		}
+		if _, ok := amends[successor]; ok {
+			report.Unlinked[string(iter.Key())] = successor
+			continue
+		}
+		amends[successor] = string(iter.Key())
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return report, err
+	}
+
+	batch := new(leveldb.Batch)
+	iter = db.NewIterator(nil, nil)
+	for iter.Next() {
+		report.Total++
+		id := string(iter.Key())
+		content, version, err := bf_tx.MigrateBFTX(iter.Value())
+		if err != nil {
+			report.Failed[id] = err.Error()
+			continue
+		}
+		relinked := false
+		if predecessor, ok := amends[id]; ok {
+			delete(amends, id)
+			var bftx bf_tx.BF_TX
+			json.Unmarshal(content, &bftx)
+			if bftx.Amendment != "" && bftx.Amendment != predecessor {
+				report.Unlinked[predecessor] = id
+			} else if bftx.Amendment == "" {
+				bftx.Amendment = predecessor
+				if content, err = json.Marshal(bftx); err != nil {
+					report.Failed[id] = err.Error()
+					continue
+				}
+				report.Relinked[id] = predecessor
+				relinked = true
+			}
+		}
+		if version == bf_tx.CurrentSchema {
+			report.Current++
+		} else {
+			report.Migrated[version]++
+		}
+		if version != bf_tx.CurrentSchema || relinked {
+			batch.Put(iter.Key(), content)
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return report, err
+	}
+	// Forward links to a BF_TX the DB does not hold are dropped
+	for successor, predecessor := range amends {
+		report.Unlinked[predecessor] = successor
+	}
+	return report, db.Write(batch, nil)
+}
+
 // =================================================
 // Blockfreight™ | The blockchain of global freight.
 // =================================================
@@ -20,6 +20,8 @@ It has these top-level messages:
 	BFTXEncryptionConfig
 	ENCRYPTIONFIELD
 	PUBLICKEYS
+	CONTAINER
+	CARGOITEM
 */
 package saberservice
 
@@ -249,6 +251,7 @@ type BFTX_Payload struct {
 	DeliverAgent        string          `protobuf:"bytes,33,opt,name=DeliverAgent" json:"DeliverAgent,omitempty"`
 	ContainerMode       string          `protobuf:"bytes,34,opt,name=ContainerMode" json:"ContainerMode,omitempty"`
 	ContainerType       string          `protobuf:"bytes,35,opt,name=ContainerType" json:"ContainerType,omitempty"`
+	Containers          []*CONTAINER    `protobuf:"bytes,36,rep,name=Containers" json:"Containers,omitempty"`
 }
 
 func (m *BFTX_Payload) Reset()                    { *m = BFTX_Payload{} }
@@ -501,6 +504,13 @@ func (m *BFTX_Payload) GetContainerType() string {
 	return ""
 }
 
+func (m *BFTX_Payload) GetContainers() []*CONTAINER {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
 type ISSUEDETAILS struct {
 	PlaceOfIssue string `protobuf:"bytes,1,opt,name=PlaceOfIssue" json:"PlaceOfIssue,omitempty"`
 	DateOfIssue  string `protobuf:"bytes,2,opt,name=DateOfIssue" json:"DateOfIssue,omitempty"`
@@ -529,6 +539,7 @@ type MASTERINFO struct {
 	FirstName string `protobuf:"bytes,1,opt,name=FirstName" json:"FirstName,omitempty"`
 	LastName  string `protobuf:"bytes,2,opt,name=LastName" json:"LastName,omitempty"`
 	Sig       string `protobuf:"bytes,3,opt,name=Sig" json:"Sig,omitempty"`
+	Details   string `protobuf:"bytes,4,opt,name=Details" json:"Details,omitempty"`
 }
 
 func (m *MASTERINFO) Reset()                    { *m = MASTERINFO{} }
@@ -557,10 +568,18 @@ func (m *MASTERINFO) GetSig() string {
 	return ""
 }
 
+func (m *MASTERINFO) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
 type AGENTFORMASTER struct {
 	FirstName string `protobuf:"bytes,1,opt,name=FirstName" json:"FirstName,omitempty"`
 	LastName  string `protobuf:"bytes,2,opt,name=LastName" json:"LastName,omitempty"`
 	Sig       string `protobuf:"bytes,3,opt,name=Sig" json:"Sig,omitempty"`
+	Details   string `protobuf:"bytes,4,opt,name=Details" json:"Details,omitempty"`
 }
 
 func (m *AGENTFORMASTER) Reset()                    { *m = AGENTFORMASTER{} }
@@ -589,11 +608,19 @@ func (m *AGENTFORMASTER) GetSig() string {
 	return ""
 }
 
+func (m *AGENTFORMASTER) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
 type AGENTFOROWNER struct {
 	FirstName             string `protobuf:"bytes,1,opt,name=FirstName" json:"FirstName,omitempty"`
 	LastName              string `protobuf:"bytes,2,opt,name=LastName" json:"LastName,omitempty"`
 	Sig                   string `protobuf:"bytes,3,opt,name=Sig" json:"Sig,omitempty"`
 	ConditionsForCarriage string `protobuf:"bytes,4,opt,name=ConditionsForCarriage" json:"ConditionsForCarriage,omitempty"`
+	Details               string `protobuf:"bytes,5,opt,name=Details" json:"Details,omitempty"`
 }
 
 func (m *AGENTFOROWNER) Reset()                    { *m = AGENTFOROWNER{} }
@@ -629,6 +656,13 @@ func (m *AGENTFOROWNER) GetConditionsForCarriage() string {
 	return ""
 }
 
+func (m *AGENTFOROWNER) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
 type BFTXEncryptionConfig struct {
 	Version          string             `protobuf:"bytes,1,opt,name=version" json:"version,omitempty"`
 	Group            string             `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
@@ -725,6 +759,134 @@ func (m *PUBLICKEYS) GetKeyfile() string {
 	return ""
 }
 
+type CONTAINER struct {
+	Container     string       `protobuf:"bytes,1,opt,name=Container" json:"Container,omitempty"`
+	ContainerSeal string       `protobuf:"bytes,2,opt,name=ContainerSeal" json:"ContainerSeal,omitempty"`
+	ContainerType string       `protobuf:"bytes,3,opt,name=ContainerType" json:"ContainerType,omitempty"`
+	Packages      string       `protobuf:"bytes,4,opt,name=Packages" json:"Packages,omitempty"`
+	GrossWeight   string       `protobuf:"bytes,5,opt,name=GrossWeight" json:"GrossWeight,omitempty"`
+	Volume        string       `protobuf:"bytes,6,opt,name=Volume" json:"Volume,omitempty"`
+	Items         []*CARGOITEM `protobuf:"bytes,7,rep,name=Items" json:"Items,omitempty"`
+}
+
+func (m *CONTAINER) Reset()                    { *m = CONTAINER{} }
+func (m *CONTAINER) String() string            { return proto.CompactTextString(m) }
+func (*CONTAINER) ProtoMessage()               {}
+func (*CONTAINER) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *CONTAINER) GetContainer() string {
+	if m != nil {
+		return m.Container
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetContainerSeal() string {
+	if m != nil {
+		return m.ContainerSeal
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetContainerType() string {
+	if m != nil {
+		return m.ContainerType
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetPackages() string {
+	if m != nil {
+		return m.Packages
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetGrossWeight() string {
+	if m != nil {
+		return m.GrossWeight
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
+func (m *CONTAINER) GetItems() []*CARGOITEM {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+type CARGOITEM struct {
+	DescOfGoods     string `protobuf:"bytes,1,opt,name=DescOfGoods" json:"DescOfGoods,omitempty"`
+	HSCode          string `protobuf:"bytes,2,opt,name=HSCode" json:"HSCode,omitempty"`
+	MarksAndNumbers string `protobuf:"bytes,3,opt,name=MarksAndNumbers" json:"MarksAndNumbers,omitempty"`
+	Packages        string `protobuf:"bytes,4,opt,name=Packages" json:"Packages,omitempty"`
+	PackType        string `protobuf:"bytes,5,opt,name=PackType" json:"PackType,omitempty"`
+	GrossWeight     string `protobuf:"bytes,6,opt,name=GrossWeight" json:"GrossWeight,omitempty"`
+	Volume          string `protobuf:"bytes,7,opt,name=Volume" json:"Volume,omitempty"`
+}
+
+func (m *CARGOITEM) Reset()                    { *m = CARGOITEM{} }
+func (m *CARGOITEM) String() string            { return proto.CompactTextString(m) }
+func (*CARGOITEM) ProtoMessage()               {}
+func (*CARGOITEM) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *CARGOITEM) GetDescOfGoods() string {
+	if m != nil {
+		return m.DescOfGoods
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetHSCode() string {
+	if m != nil {
+		return m.HSCode
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetMarksAndNumbers() string {
+	if m != nil {
+		return m.MarksAndNumbers
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetPackages() string {
+	if m != nil {
+		return m.Packages
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetPackType() string {
+	if m != nil {
+		return m.PackType
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetGrossWeight() string {
+	if m != nil {
+		return m.GrossWeight
+	}
+	return ""
+}
+
+func (m *CARGOITEM) GetVolume() string {
+	if m != nil {
+		return m.Volume
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*BFTX_EncodeRequest)(nil), "saberservice.BFTX_Encode_request")
 	proto.RegisterType((*BFTX_DecodeRequest)(nil), "saberservice.BFTX_Decode_request")
@@ -738,6 +900,8 @@ func init() {
 	proto.RegisterType((*BFTXEncryptionConfig)(nil), "saberservice.BFTX_encryptionConfig")
 	proto.RegisterType((*ENCRYPTIONFIELD)(nil), "saberservice.ENCRYPTIONFIELD")
 	proto.RegisterType((*PUBLICKEYS)(nil), "saberservice.PUBLICKEYS")
+	proto.RegisterType((*CONTAINER)(nil), "saberservice.CONTAINER")
+	proto.RegisterType((*CARGOITEM)(nil), "saberservice.CARGOITEM")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -852,82 +1016,91 @@ var _BFSaberService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("saber.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1231 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xdb, 0x6e, 0x1b, 0x37,
-	0x13, 0xfe, 0x65, 0xe7, 0x24, 0x5a, 0x76, 0x1c, 0x26, 0xce, 0xcf, 0x3a, 0x87, 0x3a, 0x9b, 0xa2,
-	0xf0, 0x45, 0x61, 0x14, 0x69, 0x0b, 0x14, 0x45, 0x11, 0x40, 0xd6, 0xc1, 0x5d, 0xd8, 0x96, 0x84,
-	0x95, 0x92, 0xd8, 0x40, 0x81, 0x80, 0xd6, 0x8e, 0x64, 0xc2, 0xab, 0xa5, 0x4a, 0x72, 0xd5, 0xaa,
-	0x6f, 0xd1, 0xbb, 0xbe, 0x47, 0x6f, 0xfa, 0x02, 0x7d, 0x9b, 0x3e, 0x44, 0x31, 0x24, 0xb5, 0xda,
-	0x55, 0x04, 0xe4, 0x26, 0x77, 0xfb, 0x7d, 0x33, 0xf3, 0x0d, 0x67, 0xc8, 0xe1, 0x92, 0x6c, 0x69,
-	0x7e, 0x05, 0xea, 0x68, 0xaa, 0xa4, 0x91, 0xb4, 0x66, 0x81, 0x06, 0x35, 0x13, 0x43, 0x08, 0xfe,
-	0xac, 0x90, 0x87, 0xc7, 0xed, 0xc1, 0xc5, 0xfb, 0x56, 0x3a, 0x94, 0x31, 0xbc, 0x57, 0xf0, 0x4b,
-	0x06, 0xda, 0xd0, 0x1f, 0x49, 0xf5, 0x6a, 0x64, 0x7e, 0x33, 0x8a, 0xa7, 0x9a, 0x55, 0x0e, 0x2a,
-	0x87, 0x5b, 0xaf, 0x9e, 0x1f, 0x15, 0x23, 0x8f, 0x6c, 0x94, 0xb5, 0xf3, 0xa1, 0x11, 0x32, 0x8d,
-	0x96, 0x01, 0xb4, 0x41, 0x08, 0x82, 0xa1, 0x4c, 0x47, 0x62, 0xcc, 0x36, 0x6c, 0xf8, 0xcb, 0x35,
-	0xe1, 0x90, 0x0e, 0xd5, 0x7c, 0x8a, 0xd1, 0x0d, 0xeb, 0x1a, 0x15, 0xc2, 0x82, 0x89, 0x5f, 0x59,
-	0x13, 0x3e, 0xe1, 0xca, 0x18, 0xb9, 0x7b, 0x0a, 0xf3, 0x0e, 0x9f, 0x80, 0x5d, 0x56, 0x35, 0x5a,
-	0xc0, 0xe0, 0x9f, 0x0d, 0xb2, 0xbb, 0x1a, 0x49, 0x7f, 0x20, 0xa4, 0xa7, 0xe4, 0x14, 0x94, 0x11,
-	0xb0, 0xc8, 0xb6, 0xbf, 0x26, 0x5b, 0x8f, 0xcf, 0x13, 0xc9, 0xe3, 0xa8, 0xe0, 0x4d, 0x77, 0xc8,
-	0x46, 0x18, 0xfb, 0x2c, 0x1b, 0x61, 0x4c, 0xbf, 0x43, 0x2d, 0x31, 0xe3, 0x06, 0x4e, 0x61, 0xce,
-	0x36, 0xad, 0xd6, 0x5e, 0x59, 0xab, 0x2f, 0xc6, 0xe9, 0x29, 0xcc, 0xa3, 0x82, 0x23, 0xdd, 0x27,
-	0xf7, 0x90, 0xbe, 0xe6, 0xfa, 0x9a, 0xdd, 0xb2, 0x62, 0x39, 0xa6, 0x4f, 0x49, 0x15, 0xbf, 0xb9,
-	0xc9, 0x14, 0xb0, 0xdb, 0xd6, 0xb8, 0x24, 0x30, 0xf2, 0x2d, 0x28, 0x31, 0x12, 0x10, 0xb3, 0x3b,
-	0x07, 0x95, 0xc3, 0x7b, 0x51, 0x8e, 0xe9, 0x01, 0xd9, 0x1a, 0x60, 0x9d, 0x13, 0x61, 0x0c, 0xc4,
-	0xec, 0xae, 0x35, 0x17, 0x29, 0xd4, 0xae, 0x4f, 0x20, 0x8d, 0x27, 0x90, 0x1a, 0x76, 0xcf, 0x69,
-	0xe7, 0x04, 0xf6, 0xd1, 0xaf, 0x91, 0x55, 0x5d, 0x1f, 0x3d, 0x0c, 0x42, 0x72, 0xd7, 0x97, 0x41,
-	0x1f, 0x91, 0xdb, 0x8d, 0x4c, 0xcd, 0xc0, 0x36, 0xae, 0x1a, 0x39, 0x40, 0x6b, 0xa4, 0x72, 0xe1,
-	0xdb, 0x52, 0xb9, 0x40, 0x74, 0x69, 0x9b, 0x51, 0x8d, 0x2a, 0x97, 0x88, 0x9a, 0xbe, 0xca, 0x4a,
-	0x33, 0xf8, 0x8b, 0x90, 0x5a, 0xb1, 0xbd, 0x98, 0xb5, 0x7f, 0x2d, 0xa6, 0x53, 0x50, 0x5e, 0x72,
-	0x01, 0xe9, 0x63, 0x72, 0xe7, 0x58, 0x26, 0x9d, 0x6c, 0xe2, 0x95, 0x3d, 0x42, 0x3e, 0x82, 0x11,
-	0xf2, 0x2e, 0x87, 0x47, 0xc8, 0xbf, 0x05, 0xad, 0x21, 0xf1, 0xd9, 0x3c, 0xa2, 0x5f, 0x90, 0xed,
-	0x9e, 0x54, 0xa6, 0x3b, 0x3a, 0x93, 0x3c, 0x16, 0xe9, 0xd8, 0x77, 0xb5, 0x4c, 0xd2, 0x43, 0x72,
-	0xdf, 0x11, 0x4d, 0xa1, 0x87, 0xd7, 0x5c, 0x8d, 0xc1, 0x36, 0xb8, 0x1a, 0xad, 0xd2, 0x34, 0x20,
-	0xb5, 0x37, 0xa9, 0x30, 0xdd, 0xd1, 0x5b, 0x99, 0x64, 0x13, 0xb0, 0x8d, 0xae, 0x46, 0x25, 0x0e,
-	0x73, 0x76, 0xa4, 0x11, 0xa3, 0x79, 0x3d, 0x8e, 0x15, 0x68, 0xed, 0xbb, 0x5d, 0x26, 0x71, 0xc7,
-	0x9a, 0xa0, 0x87, 0xdd, 0xd1, 0x89, 0x94, 0xb1, 0xf6, 0x5d, 0x2f, 0x52, 0xe8, 0x71, 0xa2, 0xa4,
-	0xd6, 0xef, 0x40, 0x8c, 0xaf, 0x0d, 0x23, 0xce, 0xa3, 0x40, 0xd1, 0xaf, 0xc8, 0x83, 0xb6, 0xb2,
-	0x9f, 0x3d, 0x3e, 0xe7, 0x57, 0x09, 0xd4, 0x27, 0x86, 0x6d, 0x59, 0xbf, 0x0f, 0x0d, 0xb8, 0x2e,
-	0x4f, 0xd6, 0xe3, 0x19, 0x7a, 0xd6, 0xdc, 0xba, 0x4a, 0x24, 0xfd, 0x9a, 0x3c, 0x3c, 0x81, 0x14,
-	0x14, 0x4f, 0xc2, 0x54, 0x1b, 0x95, 0xd9, 0xc1, 0xd1, 0x6c, 0xdb, 0xfa, 0xae, 0x33, 0xd9, 0x4a,
-	0xb8, 0x01, 0xb7, 0x75, 0x31, 0xdb, 0xf1, 0x95, 0x2c, 0x29, 0xfa, 0x9a, 0xd4, 0x42, 0xad, 0x33,
-	0x68, 0x82, 0xe1, 0x22, 0xd1, 0xec, 0xfe, 0xba, 0xc1, 0x0b, 0xfb, 0xfd, 0x37, 0xad, 0x66, 0x6b,
-	0x50, 0x0f, 0xcf, 0xfa, 0x51, 0xc9, 0x1f, 0x77, 0xb7, 0x93, 0x4d, 0x8e, 0x65, 0xc2, 0x76, 0xdd,
-	0xee, 0x3a, 0x44, 0xbf, 0x27, 0xe4, 0x9c, 0x6b, 0x03, 0x2a, 0x4c, 0x47, 0x92, 0x3d, 0xb0, 0xaa,
-	0xac, 0xac, 0x7a, 0x5e, 0xef, 0x0f, 0x5a, 0x51, 0xd8, 0x69, 0x77, 0xa3, 0x82, 0x2f, 0x6d, 0x92,
-	0x9d, 0xfa, 0x18, 0x52, 0xd3, 0x96, 0xca, 0xb1, 0x8c, 0xda, 0xe8, 0xa7, 0xe5, 0xe8, 0xfa, 0x49,
-	0xab, 0x33, 0x68, 0x77, 0x23, 0xa7, 0x12, 0xad, 0xc4, 0xd0, 0x3a, 0xd9, 0x5e, 0x30, 0xdd, 0x5f,
-	0x53, 0x50, 0xec, 0xa1, 0x15, 0x79, 0xb2, 0x5e, 0xa4, 0xfb, 0xae, 0xd3, 0x8a, 0xa2, 0x72, 0x04,
-	0x3d, 0x22, 0xb4, 0x95, 0xdf, 0x9a, 0xe7, 0x60, 0x78, 0x93, 0x1b, 0xce, 0x1e, 0xd9, 0x32, 0xd7,
-	0x58, 0x70, 0x8c, 0x1b, 0x32, 0xd5, 0x62, 0x9c, 0x02, 0xb0, 0x3d, 0x37, 0xc6, 0x39, 0x81, 0xd6,
-	0x9f, 0x64, 0xa6, 0xe1, 0x58, 0x24, 0x09, 0x7b, 0xec, 0xac, 0x39, 0x81, 0x87, 0x37, 0x82, 0x21,
-	0x88, 0x19, 0xd8, 0x35, 0xb0, 0xff, 0xbb, 0xc3, 0x5b, 0xe4, 0xfc, 0xb1, 0x34, 0x22, 0xe5, 0x98,
-	0x96, 0xb1, 0xfc, 0x58, 0x2e, 0x28, 0x1c, 0x96, 0x73, 0xae, 0x6e, 0x74, 0x3d, 0x8d, 0x3b, 0xd9,
-	0x04, 0xeb, 0x64, 0x9f, 0xb9, 0x61, 0x59, 0xa1, 0x97, 0xc3, 0xe2, 0x4f, 0xf0, 0x7e, 0x71, 0x58,
-	0xfc, 0x11, 0xc6, 0xc1, 0x75, 0xa3, 0xf4, 0xc4, 0x0f, 0xae, 0x1b, 0x22, 0x57, 0xa7, 0xe1, 0x02,
-	0xdb, 0xfa, 0x34, 0xaf, 0xd3, 0x11, 0x78, 0x94, 0x73, 0xd0, 0x07, 0x9e, 0xb0, 0x67, 0xee, 0x28,
-	0x97, 0x48, 0xbc, 0x30, 0x7b, 0x7c, 0x78, 0xc3, 0xc7, 0xa0, 0xd9, 0x73, 0x77, 0xd5, 0x2e, 0xf0,
-	0xc2, 0x36, 0x98, 0x4f, 0x81, 0x7d, 0xbe, 0xb4, 0x21, 0xc6, 0xdc, 0x61, 0xa7, 0xd1, 0x1d, 0x80,
-	0x9a, 0x68, 0x76, 0xe0, 0x72, 0xe7, 0x04, 0x56, 0xd5, 0x84, 0x44, 0xcc, 0x40, 0xb9, 0x2e, 0xbe,
-	0x70, 0x55, 0x15, 0xb9, 0xd2, 0xfa, 0xce, 0x65, 0x0c, 0x2c, 0x58, 0x59, 0x1f, 0x92, 0x25, 0x2f,
-	0xbb, 0x90, 0x97, 0x2b, 0x5e, 0x48, 0x06, 0x03, 0x52, 0x2b, 0x8e, 0x06, 0xe6, 0xef, 0x25, 0x7c,
-	0x08, 0xdd, 0x91, 0x9d, 0x11, 0x7f, 0x73, 0x96, 0xb8, 0xc5, 0x48, 0x2e, 0x5c, 0x36, 0x96, 0x23,
-	0xe9, 0xa9, 0xe0, 0x82, 0x90, 0xe5, 0x68, 0x60, 0xc5, 0x6d, 0xa1, 0xb4, 0xb1, 0x3f, 0x52, 0x27,
-	0xb8, 0x24, 0xb0, 0x57, 0x67, 0xdc, 0x1b, 0x9d, 0x54, 0x8e, 0xe9, 0x2e, 0xd9, 0xec, 0x8b, 0xb1,
-	0xbf, 0x8d, 0xf1, 0x33, 0xf8, 0x99, 0xec, 0x94, 0xc7, 0xe6, 0x93, 0xaa, 0xff, 0x51, 0x21, 0xdb,
-	0xa5, 0x81, 0xfa, 0x94, 0xea, 0xf4, 0x5b, 0xb2, 0xd7, 0x90, 0x69, 0x2c, 0xec, 0xc5, 0xd6, 0x96,
-	0xaa, 0xc1, 0x95, 0x12, 0x7c, 0x0c, 0xfe, 0xaf, 0xb2, 0xde, 0x18, 0xfc, 0x5b, 0x21, 0x7b, 0x6b,
-	0xdf, 0x3f, 0xf8, 0x83, 0x9b, 0x81, 0xd2, 0x38, 0x49, 0xfe, 0x07, 0xe7, 0x21, 0xfe, 0x4b, 0xc7,
-	0x4a, 0x66, 0x53, 0xbf, 0x28, 0x07, 0xe8, 0x73, 0x42, 0x14, 0x0c, 0xc5, 0x54, 0x40, 0x6a, 0x34,
-	0xdb, 0x3c, 0xd8, 0x3c, 0xac, 0x46, 0x05, 0x86, 0x86, 0x64, 0x77, 0x99, 0x63, 0x24, 0x20, 0x89,
-	0x35, 0xbb, 0x75, 0xb0, 0x79, 0xb8, 0xf5, 0xea, 0x59, 0xf9, 0xce, 0x69, 0x75, 0x1a, 0xd1, 0x65,
-	0x6f, 0x10, 0x76, 0x3b, 0xed, 0xb0, 0x75, 0xd6, 0x8c, 0x3e, 0x08, 0xc3, 0xbb, 0x73, 0x9a, 0x5d,
-	0x25, 0x62, 0x78, 0x03, 0x73, 0xcd, 0x6e, 0x5b, 0x91, 0x95, 0xbb, 0xb3, 0xf7, 0xe6, 0xf8, 0x2c,
-	0x6c, 0x9c, 0xb6, 0x2e, 0xfb, 0x51, 0xc1, 0x37, 0x78, 0x47, 0xee, 0xaf, 0xc8, 0xe3, 0x1e, 0x58,
-	0xd9, 0xb4, 0xb0, 0x07, 0x39, 0x41, 0xbf, 0x24, 0x3b, 0x3c, 0x33, 0xd7, 0x52, 0x89, 0xdf, 0x21,
-	0xce, 0x34, 0x28, 0xb6, 0x61, 0x2b, 0x5b, 0x61, 0x83, 0xd7, 0x84, 0x2c, 0x53, 0xe2, 0xcd, 0x80,
-	0xac, 0x88, 0xbd, 0xa0, 0x47, 0xd8, 0xd3, 0x1b, 0x98, 0x8f, 0x44, 0x92, 0x3f, 0xf9, 0x3c, 0x7c,
-	0xf5, 0x77, 0x85, 0xec, 0x1c, 0xb7, 0xfb, 0x58, 0x42, 0xdf, 0x95, 0x40, 0x23, 0xb2, 0x55, 0x78,
-	0x0e, 0xd3, 0x17, 0x6b, 0xde, 0x7a, 0xe5, 0x97, 0xf2, 0xfe, 0x47, 0x1e, 0x9f, 0xc1, 0xff, 0x72,
-	0x4d, 0xf7, 0x90, 0x5d, 0xab, 0x59, 0x7e, 0xe3, 0x7e, 0x5c, 0xf3, 0xea, 0x8e, 0x7d, 0xcc, 0x7f,
-	0xf3, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xaa, 0x01, 0x7c, 0x9a, 0xdb, 0x0b, 0x00, 0x00,
+	// 1370 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0x5b, 0x6e, 0xdb, 0x46,
+	0x17, 0xfe, 0x29, 0xc5, 0x76, 0x34, 0x96, 0x1d, 0x67, 0x12, 0x27, 0xf3, 0x3b, 0x97, 0xdf, 0x61,
+	0x82, 0x1f, 0x7e, 0x68, 0x8d, 0x22, 0x6d, 0xd1, 0xa2, 0x28, 0x02, 0xc8, 0xba, 0x38, 0x84, 0x6d,
+	0x49, 0xa0, 0x94, 0x8b, 0x9f, 0x82, 0xb1, 0x78, 0x24, 0x0f, 0x4c, 0x91, 0xea, 0xcc, 0x50, 0xad,
+	0xba, 0x92, 0xee, 0xa1, 0x1b, 0xe8, 0x06, 0xba, 0x90, 0xbe, 0x67, 0x05, 0x7d, 0x2a, 0xe6, 0x22,
+	0x8a, 0xa4, 0xd9, 0x04, 0x05, 0x82, 0xbe, 0xf1, 0xfb, 0xce, 0xfd, 0x70, 0xce, 0xe1, 0x10, 0x6d,
+	0x0a, 0x7a, 0x01, 0xfc, 0x70, 0xc6, 0x63, 0x19, 0xe3, 0xba, 0x06, 0x02, 0xf8, 0x9c, 0x8d, 0xc0,
+	0xfd, 0xc5, 0x41, 0x77, 0x8e, 0x3a, 0xc3, 0xb7, 0xef, 0xda, 0xd1, 0x28, 0x0e, 0xe0, 0x1d, 0x87,
+	0x1f, 0x12, 0x10, 0x12, 0x7f, 0x8f, 0x6a, 0x17, 0x63, 0xf9, 0x93, 0xe4, 0x34, 0x12, 0xc4, 0xd9,
+	0x77, 0x0e, 0x36, 0x9f, 0x3f, 0x3e, 0xcc, 0x5a, 0x1e, 0x6a, 0x2b, 0x2d, 0xa7, 0x23, 0xc9, 0xe2,
+	0xc8, 0x5f, 0x19, 0xe0, 0x26, 0x42, 0x0a, 0x8c, 0xe2, 0x68, 0xcc, 0x26, 0xa4, 0xa2, 0xcd, 0x9f,
+	0x96, 0x98, 0x43, 0x34, 0xe2, 0x8b, 0x99, 0xb2, 0x6e, 0x6a, 0x55, 0x3f, 0x63, 0xe6, 0x4e, 0x6d,
+	0x66, 0x2d, 0xf8, 0x84, 0x99, 0x11, 0xb4, 0x71, 0x02, 0x8b, 0x2e, 0x9d, 0x82, 0x4e, 0xab, 0xe6,
+	0x2f, 0xa1, 0xfb, 0x7b, 0x05, 0xed, 0x14, 0x2d, 0xf1, 0x77, 0x08, 0xf5, 0x79, 0x3c, 0x03, 0x2e,
+	0x19, 0x2c, 0xa3, 0xed, 0x95, 0x44, 0xeb, 0xd3, 0x45, 0x18, 0xd3, 0xc0, 0xcf, 0x68, 0xe3, 0x6d,
+	0x54, 0xf1, 0x02, 0x1b, 0xa5, 0xe2, 0x05, 0xf8, 0x6b, 0xe5, 0x8b, 0xcd, 0xa9, 0x84, 0x13, 0x58,
+	0x90, 0xaa, 0xf6, 0xb5, 0x9b, 0xf7, 0x35, 0x60, 0x93, 0xe8, 0x04, 0x16, 0x7e, 0x46, 0x11, 0xef,
+	0xa1, 0x9b, 0x8a, 0xbe, 0xa4, 0xe2, 0x92, 0xdc, 0xd0, 0xce, 0x52, 0x8c, 0x1f, 0xa2, 0x9a, 0x7a,
+	0xa6, 0x32, 0xe1, 0x40, 0xd6, 0xb4, 0x70, 0x45, 0x28, 0xcb, 0xd7, 0xc0, 0xd9, 0x98, 0x41, 0x40,
+	0xd6, 0xf7, 0x9d, 0x83, 0x9b, 0x7e, 0x8a, 0xf1, 0x3e, 0xda, 0x1c, 0xaa, 0x3a, 0xa7, 0x4c, 0x4a,
+	0x08, 0xc8, 0x86, 0x16, 0x67, 0x29, 0xe5, 0xbb, 0x31, 0x85, 0x28, 0x98, 0x42, 0x24, 0xc9, 0x4d,
+	0xe3, 0x3b, 0x25, 0x54, 0x1f, 0x6d, 0x8e, 0xa4, 0x66, 0xfa, 0x68, 0xa1, 0xeb, 0xa1, 0x0d, 0x5b,
+	0x06, 0xbe, 0x8b, 0xd6, 0x9a, 0x09, 0x9f, 0x83, 0x6e, 0x5c, 0xcd, 0x37, 0x00, 0xd7, 0x91, 0xf3,
+	0xd6, 0xb6, 0xc5, 0x79, 0xab, 0xd0, 0xb9, 0x6e, 0x46, 0xcd, 0x77, 0xce, 0x15, 0x6a, 0xd9, 0x2a,
+	0x9d, 0x96, 0xfb, 0x1e, 0xa1, 0x7a, 0xb6, 0xbd, 0x2a, 0xea, 0xe0, 0x92, 0xcd, 0x66, 0xc0, 0xad,
+	0xcb, 0x25, 0xc4, 0xf7, 0xd0, 0xfa, 0x51, 0x1c, 0x76, 0x93, 0xa9, 0xf5, 0x6c, 0x91, 0xe2, 0x7d,
+	0x18, 0x2b, 0xde, 0xc4, 0xb0, 0x48, 0xf1, 0xaf, 0x41, 0x08, 0x08, 0x6d, 0x34, 0x8b, 0xf0, 0x33,
+	0xb4, 0xd5, 0x8f, 0xb9, 0xec, 0x8d, 0x4f, 0x63, 0x1a, 0xb0, 0x68, 0x62, 0xbb, 0x9a, 0x27, 0xf1,
+	0x01, 0xba, 0x65, 0x88, 0x16, 0x13, 0xa3, 0x4b, 0xca, 0x27, 0xa0, 0x1b, 0x5c, 0xf3, 0x8b, 0x34,
+	0x76, 0x51, 0xfd, 0x55, 0xc4, 0x64, 0x6f, 0xfc, 0x3a, 0x0e, 0x93, 0x29, 0xe8, 0x46, 0xd7, 0xfc,
+	0x1c, 0xa7, 0x62, 0x76, 0x63, 0xc9, 0xc6, 0x8b, 0x46, 0x10, 0x70, 0x10, 0xc2, 0x76, 0x3b, 0x4f,
+	0xaa, 0x37, 0xd6, 0x02, 0x31, 0xea, 0x8d, 0x8f, 0xe3, 0x38, 0x10, 0xb6, 0xeb, 0x59, 0x4a, 0x69,
+	0x1c, 0xf3, 0x58, 0x88, 0x37, 0xc0, 0x26, 0x97, 0x92, 0x20, 0xa3, 0x91, 0xa1, 0xf0, 0x67, 0xe8,
+	0x76, 0x87, 0xeb, 0xc7, 0x3e, 0x5d, 0xd0, 0x8b, 0x10, 0x1a, 0x53, 0x49, 0x36, 0xb5, 0xde, 0x75,
+	0x81, 0xca, 0xcb, 0x92, 0x8d, 0x60, 0xae, 0x34, 0xeb, 0x26, 0xaf, 0x1c, 0x89, 0xbf, 0x40, 0x77,
+	0x8e, 0x21, 0x02, 0x4e, 0x43, 0x2f, 0x12, 0x92, 0x27, 0x7a, 0x70, 0x04, 0xd9, 0xd2, 0xba, 0x65,
+	0x22, 0x5d, 0x09, 0x95, 0x60, 0x5e, 0x5d, 0x40, 0xb6, 0x6d, 0x25, 0x2b, 0x0a, 0xbf, 0x40, 0x75,
+	0x4f, 0x88, 0x04, 0x5a, 0x20, 0x29, 0x0b, 0x05, 0xb9, 0x55, 0x36, 0x78, 0xde, 0x60, 0xf0, 0xaa,
+	0xdd, 0x6a, 0x0f, 0x1b, 0xde, 0xe9, 0xc0, 0xcf, 0xe9, 0xab, 0xb7, 0xdb, 0x4d, 0xa6, 0x47, 0x71,
+	0x48, 0x76, 0xcc, 0xdb, 0x35, 0x08, 0x7f, 0x8b, 0xd0, 0x19, 0x15, 0x12, 0xb8, 0x17, 0x8d, 0x63,
+	0x72, 0x5b, 0x7b, 0x25, 0x79, 0xaf, 0x67, 0x8d, 0xc1, 0xb0, 0xed, 0x7b, 0xdd, 0x4e, 0xcf, 0xcf,
+	0xe8, 0xe2, 0x16, 0xda, 0x6e, 0x4c, 0x20, 0x92, 0x9d, 0x98, 0x1b, 0x96, 0x60, 0x6d, 0xfd, 0x30,
+	0x6f, 0xdd, 0x38, 0x6e, 0x77, 0x87, 0x9d, 0x9e, 0x6f, 0xbc, 0xf8, 0x05, 0x1b, 0xdc, 0x40, 0x5b,
+	0x4b, 0xa6, 0xf7, 0x63, 0x04, 0x9c, 0xdc, 0xd1, 0x4e, 0x1e, 0x94, 0x3b, 0xe9, 0xbd, 0xe9, 0xb6,
+	0x7d, 0x3f, 0x6f, 0x81, 0x0f, 0x11, 0x6e, 0xa7, 0x5b, 0xf3, 0x0c, 0x24, 0x6d, 0x51, 0x49, 0xc9,
+	0x5d, 0x5d, 0x66, 0x89, 0x44, 0x8d, 0x71, 0x33, 0x8e, 0x04, 0x9b, 0x44, 0x00, 0x64, 0xd7, 0x8c,
+	0x71, 0x4a, 0x28, 0xe9, 0xcb, 0x38, 0x11, 0x70, 0xc4, 0xc2, 0x90, 0xdc, 0x33, 0xd2, 0x94, 0x50,
+	0x87, 0xd7, 0x87, 0x11, 0xb0, 0x39, 0xe8, 0x1c, 0xc8, 0x7d, 0x73, 0x78, 0xb3, 0x9c, 0x3d, 0x96,
+	0x92, 0x45, 0x54, 0x85, 0x25, 0x24, 0x3d, 0x96, 0x4b, 0x4a, 0x0d, 0xcb, 0x19, 0xe5, 0x57, 0xa2,
+	0x11, 0x05, 0xdd, 0x64, 0xaa, 0xea, 0x24, 0xff, 0x35, 0xc3, 0x52, 0xa0, 0x57, 0xc3, 0x62, 0x4f,
+	0xf0, 0x5e, 0x76, 0x58, 0x0c, 0xa7, 0x07, 0xd7, 0x8c, 0xd2, 0x03, 0x3b, 0xb8, 0x1a, 0xd9, 0x3a,
+	0x25, 0x65, 0xaa, 0xad, 0x0f, 0xd3, 0x3a, 0x0d, 0xa1, 0x8e, 0x72, 0x0a, 0x06, 0x40, 0x43, 0xf2,
+	0xc8, 0x1c, 0xe5, 0x1c, 0xa9, 0x16, 0x66, 0x9f, 0x8e, 0xae, 0xe8, 0x04, 0x04, 0x79, 0x6c, 0x56,
+	0xed, 0x12, 0x2f, 0x65, 0xc3, 0xc5, 0x0c, 0xc8, 0xff, 0x56, 0x32, 0x85, 0x55, 0x6c, 0xaf, 0xdb,
+	0xec, 0x0d, 0x81, 0x4f, 0x05, 0xd9, 0x37, 0xb1, 0x53, 0x42, 0x55, 0xd5, 0x82, 0x90, 0xcd, 0x81,
+	0x9b, 0x2e, 0x3e, 0x31, 0x55, 0x65, 0xb9, 0x5c, 0x7e, 0x67, 0x71, 0x00, 0xc4, 0x2d, 0xe4, 0xa7,
+	0xc8, 0x9c, 0x96, 0x4e, 0xe4, 0x69, 0x41, 0x4b, 0x67, 0xf3, 0x0d, 0x42, 0x29, 0x21, 0xc8, 0xb3,
+	0xfd, 0xea, 0xc1, 0xe6, 0xf3, 0xfb, 0xf9, 0x13, 0xd6, 0xec, 0x75, 0x87, 0x0d, 0x4f, 0x9d, 0xae,
+	0x8c, 0xaa, 0x3b, 0x44, 0xf5, 0xec, 0x4c, 0xa9, 0xc4, 0xfb, 0x21, 0x1d, 0x41, 0x6f, 0xac, 0x87,
+	0xcb, 0xae, 0xdc, 0x1c, 0xb7, 0x9c, 0xe5, 0xa5, 0x4a, 0x65, 0x35, 0xcb, 0x96, 0x72, 0x39, 0x42,
+	0xab, 0x99, 0x52, 0xad, 0xea, 0x30, 0x2e, 0xa4, 0xfe, 0x02, 0x1b, 0x87, 0x2b, 0x42, 0x35, 0xf9,
+	0x94, 0x5a, 0xa1, 0x71, 0x95, 0x62, 0xbc, 0x83, 0xaa, 0x03, 0x36, 0xb1, 0x6b, 0x5c, 0x3d, 0xaa,
+	0xaf, 0xc1, 0x72, 0x41, 0x98, 0x25, 0xbe, 0x84, 0xee, 0x1c, 0x6d, 0xe7, 0x27, 0xf1, 0x5f, 0x8a,
+	0xfb, 0xab, 0x83, 0xb6, 0x72, 0xd3, 0xfb, 0x49, 0xe3, 0x7e, 0x85, 0x76, 0x9b, 0x71, 0x14, 0x30,
+	0xbd, 0x45, 0x3b, 0x31, 0x6f, 0x52, 0xce, 0x19, 0x9d, 0x80, 0xcd, 0xa2, 0x5c, 0x98, 0xcd, 0x76,
+	0x2d, 0x9f, 0xed, 0x7b, 0x07, 0xed, 0x96, 0x5e, 0xc3, 0x94, 0xcd, 0x1c, 0xb8, 0x50, 0x03, 0x6d,
+	0xbf, 0xb3, 0x16, 0xaa, 0x4f, 0xfa, 0x84, 0xc7, 0xc9, 0xcc, 0xa6, 0x6b, 0x00, 0x7e, 0x8c, 0x10,
+	0x87, 0x11, 0x9b, 0x31, 0x88, 0xa4, 0x20, 0xd5, 0xfd, 0xea, 0x41, 0xcd, 0xcf, 0x30, 0xd8, 0x43,
+	0x3b, 0xab, 0x18, 0x63, 0x06, 0x61, 0xa0, 0x5a, 0xa7, 0x0e, 0xe6, 0xa3, 0xfc, 0xc1, 0x6c, 0x77,
+	0x9b, 0xfe, 0x79, 0x7f, 0xe8, 0xf5, 0xba, 0x1d, 0xaf, 0x7d, 0xda, 0xf2, 0xaf, 0x99, 0xa9, 0x15,
+	0x3e, 0x4b, 0x2e, 0x42, 0x36, 0xba, 0x82, 0x85, 0xaa, 0xa8, 0x7a, 0x7d, 0x85, 0xf7, 0x5f, 0x1d,
+	0x9d, 0x7a, 0xcd, 0x93, 0xf6, 0xf9, 0xc0, 0xcf, 0xe8, 0xba, 0x6f, 0xd0, 0xad, 0x82, 0x7b, 0xf5,
+	0x76, 0xb4, 0xdb, 0x28, 0xf3, 0x76, 0x52, 0x02, 0xff, 0x1f, 0x6d, 0xd3, 0x44, 0x5e, 0xc6, 0x9c,
+	0xfd, 0x0c, 0x41, 0x22, 0x80, 0x93, 0x8a, 0xae, 0xac, 0xc0, 0xba, 0x2f, 0x10, 0x5a, 0x85, 0x54,
+	0x0b, 0x4a, 0xb1, 0x2c, 0xb0, 0x0e, 0x2d, 0x52, 0x3d, 0xbd, 0x82, 0xc5, 0x98, 0x85, 0xe9, 0xcd,
+	0xd3, 0x42, 0xf7, 0x4f, 0x07, 0xd5, 0xd2, 0x89, 0xcc, 0x2f, 0x32, 0xe7, 0xa3, 0x8b, 0xac, 0x52,
+	0xb6, 0xc8, 0xae, 0x2d, 0x8a, 0x6a, 0xd9, 0xa2, 0xc8, 0xae, 0xbb, 0x1b, 0x85, 0x75, 0x57, 0xb8,
+	0x4b, 0xac, 0x5d, 0xbf, 0x4b, 0xac, 0x16, 0xf1, 0x7a, 0x6e, 0x11, 0x7f, 0x8e, 0xd6, 0x3c, 0x09,
+	0x53, 0x41, 0x36, 0x4a, 0x37, 0x4f, 0xc3, 0x3f, 0xee, 0x79, 0xc3, 0xf6, 0x99, 0x6f, 0xb4, 0xdc,
+	0x3f, 0x54, 0xf1, 0x4b, 0xb2, 0x78, 0xc9, 0x71, 0xae, 0x5f, 0x72, 0xee, 0xa1, 0xf5, 0x97, 0x83,
+	0xa6, 0x5a, 0x91, 0xf6, 0xa2, 0x67, 0x50, 0xd9, 0x57, 0xa6, 0x5a, 0xfe, 0x95, 0xf9, 0x50, 0xd9,
+	0xd9, 0x2d, 0xbf, 0x56, 0xd8, 0xf2, 0x85, 0x96, 0xac, 0x7f, 0xa8, 0x25, 0x1b, 0xd9, 0x96, 0x3c,
+	0xff, 0xcd, 0x41, 0xdb, 0x47, 0x9d, 0x81, 0xea, 0xc3, 0xc0, 0xf4, 0x01, 0xfb, 0x68, 0x33, 0xf3,
+	0xdb, 0x85, 0x9f, 0x94, 0xfc, 0x53, 0xe4, 0xff, 0xc8, 0xf6, 0x3e, 0xf2, 0x93, 0xe3, 0xfe, 0x27,
+	0xf5, 0xd9, 0x82, 0xbf, 0xf5, 0xd9, 0x82, 0x7f, 0xe6, 0xf3, 0x62, 0x5d, 0xff, 0x34, 0x7e, 0xf9,
+	0xd7, 0x00, 0xc1, 0xc1, 0x13, 0x1a, 0x43, 0x0e, 0x00, 0x00,
 }
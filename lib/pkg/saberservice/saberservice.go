@@ -5,18 +5,22 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	btx "github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/app/bft"
 	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
 	"github.com/blockfreight/go-bftx/lib/pkg/leveldb"
 	th "github.com/blockfreight/go-bftx/lib/pkg/tenderhelper"
 	abcicli "github.com/tendermint/abci/client"
+	abcitypes "github.com/tendermint/abci/types"
 	rpc "github.com/tendermint/tendermint/rpc/client"
 	"google.golang.org/grpc"
 	yaml "gopkg.in/yaml.v2"
@@ -59,6 +63,88 @@ func loadconfiguration(s string) *BFTXEncryptionConfig {
 	return bfconfig
 }
 
+// EncryptedFields returns the names of the BF_TX fields listed in an encryption config file. A package count and
+// the list of containers cannot hold ciphertext, so a config that lists Packages or Containers is an error.
+func EncryptedFields(configPath string) ([]string, error) {
+	var bfconfig BFTXEncryptionConfig
+
+	ylconfig, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(ylconfig, &bfconfig); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(bfconfig.GetEncryptionfields()))
+	for _, field := range bfconfig.GetEncryptionfields() {
+		if strings.EqualFold(strings.TrimSpace(field.GetFieldname()), "Packages") {
+			return nil, errors.New("Field Packages is a package count, it cannot be encrypted")
+		}
+		if strings.EqualFold(strings.TrimSpace(field.GetFieldname()), "Containers") {
+			return nil, errors.New("Field Containers is a list of containers, it cannot be encrypted")
+		}
+		fields = append(fields, field.GetFieldname())
+	}
+	return fields, nil
+}
+
+// encryptionMetaData returns the EncryptionMetaData of a BF_TX encrypted with an encryption config, the list of
+// the fields it encrypts, which the node does not check the typed values of.
+func encryptionMetaData(config *BFTXEncryptionConfig) string {
+	fields := make([]string, 0, len(config.GetEncryptionfields()))
+	for _, field := range config.GetEncryptionfields() {
+		fields = append(fields, field.GetFieldname())
+	}
+	return btx.EncryptionMetaData(fields)
+}
+
+// markEncrypted lists the fields an encryption config encrypts in the EncryptionMetaData of a BF_TX saber
+// encrypted, and leaves their copies out of its containers and cargo items: saber only encrypts the fields of the
+// BF_TX itself, so the copies would publish their values in clear.
+func markEncrypted(payload *BFTX_Payload, config *BFTXEncryptionConfig) {
+	if payload == nil {
+		return
+	}
+	payload.EncryptionMetaData = encryptionMetaData(config)
+	encrypted := btx.EncryptedFields(btx.Properties{EncryptionMetaData: payload.EncryptionMetaData})
+	for _, container := range payload.Containers {
+		if encrypted["container"] {
+			container.Container = ""
+		}
+		if encrypted["containerseal"] {
+			container.ContainerSeal = ""
+		}
+		if encrypted["containertype"] {
+			container.ContainerType = ""
+		}
+		if encrypted["grossweight"] {
+			container.GrossWeight = ""
+		}
+		if encrypted["volume"] {
+			container.Volume = ""
+		}
+		for _, item := range container.Items {
+			if encrypted["descofgoods"] {
+				item.DescOfGoods = ""
+			}
+			if encrypted["marksandnumbers"] {
+				item.MarksAndNumbers = ""
+			}
+			if encrypted["packtype"] {
+				item.PackType = ""
+			}
+			if encrypted["grossweight"] {
+				item.GrossWeight = ""
+			}
+			if encrypted["volume"] {
+				item.Volume = ""
+			}
+		}
+	}
+}
+
 // NVCsvConverterNew is a function that
 // convert an array of bftx parameters to BFTXTransaction structure.
 // This is used for the converting the Lading.csv to bftx.BFTX
@@ -75,9 +161,9 @@ func NVCsvConverterNew(line []string) *BFTXTransaction {
 			Destination:     line[6],
 			MarksAndNumbers: line[7],
 			DescOfGoods:     nvparsedesc(line[8]),
-			GrossWeight:     line[9],
+			GrossWeight:     nvparsequantity(line[9], line[10]).String(),
 			UnitOfWeight:    line[10],
-			Volume:          line[11],
+			Volume:          nvparsequantity(line[11], line[12]).String(),
 			UnitOfVolume:    line[12],
 			Container:       line[13],
 			ContainerSeal:   line[14],
@@ -87,11 +173,39 @@ func NVCsvConverterNew(line []string) *BFTXTransaction {
 			PackType:        line[18],
 			INCOTerms:       line[19],
 			DeliverAgent:    line[20],
+			Containers:      []*CONTAINER{nvcontainer(line)},
 		},
 	}
 	return &msg
 }
 
+// NVCsvAppendContainer is a function that adds the container of a line to a BFTXTransaction
+// converted by NVCsvConverterNew, for a bill of lading of several lines, and adds up its
+// packages, gross weight and volume.
+func NVCsvAppendContainer(tx *BFTXTransaction, line []string) error {
+	container := nvcontainer(line)
+	packages, err := btx.SumCounts(btx.Count(tx.Properties.Packages), btx.Count(container.Packages))
+	if err != nil {
+		return err
+	}
+	weight, err := btx.SumQuantities(btx.ParseQuantity(tx.Properties.GrossWeight), btx.ParseQuantity(container.GrossWeight))
+	if err != nil {
+		return err
+	}
+	volume, err := btx.SumQuantities(btx.ParseQuantity(tx.Properties.Volume), btx.ParseQuantity(container.Volume))
+	if err != nil {
+		return err
+	}
+
+	tx.Properties.Containers = append(tx.Properties.Containers, container)
+	if packages != "" {
+		tx.Properties.Packages = string(packages)
+	}
+	tx.Properties.GrossWeight = weight.String()
+	tx.Properties.Volume = volume.String()
+	return nil
+}
+
 // NVCsvConverterOld is a function that
 // convert an array of bftx parameters to BF_TX structure.
 // This is used for the converting the Lading.csv to bftx.BFTX
@@ -99,27 +213,44 @@ func NVCsvConverterNew(line []string) *BFTXTransaction {
 func NVCsvConverterOld(line []string) btx.BF_TX {
 	msg := btx.BF_TX{
 		Properties: btx.Properties{
-			Shipper:         line[0],
-			Consignee:       line[1],
-			ReceiveAgent:    line[2],
+			Shipper:         btx.ParseParty(line[0]),
+			Consignee:       btx.ParseParty(line[1]),
+			ReceiveAgent:    btx.ParseParty(line[2]),
 			HouseBill:       line[3],
 			PortOfLoading:   line[4],
 			PortOfDischarge: line[5],
 			Destination:     line[6],
 			MarksAndNumbers: line[7],
 			DescOfGoods:     nvparsedesc(line[8]),
-			GrossWeight:     line[9],
+			GrossWeight:     nvparsequantity(line[9], line[10]),
 			UnitOfWeight:    line[10],
-			Volume:          line[11],
+			Volume:          nvparsequantity(line[11], line[12]),
 			UnitOfVolume:    line[12],
 			Container:       line[13],
 			ContainerSeal:   line[14],
 			ContainerMode:   line[15],
 			ContainerType:   line[16],
-			Packages:        line[17],
+			Packages:        nvparsecount(line[17]),
 			PackType:        line[18],
 			INCOTerms:       line[19],
-			DeliverAgent:    line[20],
+			DeliverAgent:    btx.ParseParty(line[20]),
+			Containers: []btx.Container{{
+				Container:     line[13],
+				ContainerSeal: line[14],
+				ContainerType: line[16],
+				Packages:      nvparsecount(line[17]),
+				GrossWeight:   nvparsequantity(line[9], line[10]),
+				Volume:        nvparsequantity(line[11], line[12]),
+				Items: []btx.CargoItem{{
+					DescOfGoods:     nvparsedesc(line[8]),
+					HSCode:          nvparsehscode(line),
+					MarksAndNumbers: line[7],
+					Packages:        nvparsecount(line[17]),
+					PackType:        line[18],
+					GrossWeight:     nvparsequantity(line[9], line[10]),
+					Volume:          nvparsequantity(line[11], line[12]),
+				}},
+			}},
 		},
 	}
 	return msg
@@ -135,14 +266,53 @@ func NVCsvConverterOld(line []string) btx.BF_TX {
 // 	return float32(c)
 // }
 
-// nvparseasint provides error handling necessary for bf_tx.Properties single-value int context
-// func nvparseasint(num string) int64 {
-// 	c, err := strconv.Atoi(num)
-// 	if err != nil {
-// 		log.Fatal(err)
-// 	}
-// 	return int64(c)
-// }
+// nvparsecount provides error handling necessary for bf_tx.Properties package count context
+func nvparsecount(num string) btx.Count {
+	c, err := btx.ParseCount(num)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return c
+}
+
+// nvparsequantity provides the bf_tx.Properties quantity of a value and its unit
+func nvparsequantity(num string, unit string) btx.Quantity {
+	if strings.TrimSpace(num) == "" {
+		return btx.Quantity{}
+	}
+	return btx.Quantity{Value: btx.Decimal(strings.TrimSpace(num)), Unit: strings.TrimSpace(unit)}
+}
+
+// nvcontainer provides the bf_tx.Properties container of a line, which holds the goods of the line
+func nvcontainer(line []string) *CONTAINER {
+	weight := nvparsequantity(line[9], line[10]).String()
+	volume := nvparsequantity(line[11], line[12]).String()
+	return &CONTAINER{
+		Container:     line[13],
+		ContainerSeal: line[14],
+		ContainerType: line[16],
+		Packages:      line[17],
+		GrossWeight:   weight,
+		Volume:        volume,
+		Items: []*CARGOITEM{{
+			DescOfGoods:     nvparsedesc(line[8]),
+			HSCode:          nvparsehscode(line),
+			MarksAndNumbers: line[7],
+			Packages:        line[17],
+			PackType:        line[18],
+			GrossWeight:     weight,
+			Volume:          volume,
+		}},
+	}
+}
+
+// nvparsehscode provides the HS code of the goods of a line, in its optional 23rd column
+func nvparsehscode(line []string) string {
+	if len(line) < 23 {
+		return ""
+	}
+	return strings.TrimSpace(line[22])
+}
 
 // nvparseasint provides error handling necessary for bf_tx.Properties single-value string context
 func nvparsedesc(desc string) string {
@@ -159,7 +329,8 @@ func nvparsedesc(desc string) string {
 // structure to old struct *BF_TX. These two structure is duplicated somehow. This function is used
 // for temporal conversion.
 // since this is just for temporal usage, I will just use json marshal and unmarshal
-// to convert structures
+// to convert structures. A *BFTXTransaction is a BF_TX of schema version bf_tx.SchemaFlags,
+// which decoding the JSON migrates to the current schema version.
 func BftxStructConverstionNO(tx *BFTXTransaction) (*btx.BF_TX, error) {
 	var oldbftx btx.BF_TX
 	bfjs, err := json.Marshal(*tx)
@@ -177,10 +348,13 @@ func BftxStructConverstionNO(tx *BFTXTransaction) (*btx.BF_TX, error) {
 // *BF_TX to new structure *BFTXTransaction. These two structure is duplicated somehow. This function is used
 // for temporal conversion.
 // since this is just for temporal usage, I will just use json marshal and unmarshal
-// to convert structures
+// to convert structures. The parties of the BF_TX are converted to their text form, the strings of the
+// BFTXTransaction.
 func BftxStructConverstionON(tx *btx.BF_TX) (*BFTXTransaction, error) {
 	var newbftx BFTXTransaction
-	bfjs, err := json.Marshal(tx)
+	parties := *tx
+	parties.Properties = btx.PartiesAsText(tx.Properties)
+	bfjs, err := json.Marshal(parties)
 	if err != nil {
 		log.Fatal("\nBftxStructConverstionON convertion error\n", err)
 	}
@@ -265,6 +439,7 @@ func SaberEncoding(tx *BFTXTransaction, st Saberinput) (*BFTXTransaction, error)
 
 	encr, err := c.BFTX_Encode(context.Background(), &bfencreq)
 	check(err)
+	markEncrypted(encr.Properties, txconfig)
 
 	return encr, err
 }
@@ -299,6 +474,9 @@ func SaberEncodingTestCase(st Saberinput) (*BFTXTransaction, error) {
 	}
 }
 
+// quotaBackoff is how long massSaberEncoding waits before it sends again a transaction rejected by the account quotas
+const quotaBackoff = 10 * time.Second
+
 // massSaberEncoding is used for massively load the transaction from the lading.csv file
 func massSaberEncoding(st Saberinput) error {
 	// define the index i
@@ -313,6 +491,12 @@ func massSaberEncoding(st Saberinput) error {
 		log.Fatal("csv read error:\n", err)
 	}
 
+	// The BF_TX are signed with the key of the party loading them
+	privKey, err := crypto.LoadKeyFile(os.Getenv("BFTX_KEY"))
+	if err != nil {
+		log.Fatal("Key file read error, set BFTX_KEY:\n", err)
+	}
+
 	// Define the abci client
 	abciClient, err := abcicli.NewClient("tcp://127.0.0.1:46658", "socket", true)
 	if err != nil {
@@ -326,6 +510,21 @@ func massSaberEncoding(st Saberinput) error {
 	}
 	defer abciClient.Stop()
 
+	// The envelopes are signed for the chain of the network, and take the next sequences of the key on it
+	resQuery, err := abciClient.QuerySync(abcitypes.RequestQuery{Path: "/chain_id"})
+	if err != nil {
+		log.Fatal("Chain Id query error:\n", err)
+	}
+	chainID := string(resQuery.Value)
+	resQuery, err = abciClient.QuerySync(abcitypes.RequestQuery{Path: "/nonce", Data: privKey.PubKey().Bytes()})
+	if err != nil {
+		log.Fatal("Nonce query error:\n", err)
+	}
+	sequence, err := bft.NextSequence(resQuery)
+	if err != nil {
+		log.Fatal("Nonce query error:\n", err)
+	}
+
 	// Define the rpc client
 	rpcClient := rpc.NewHTTP("tcp://127.0.0.1:46657", "/websocket")
 
@@ -348,24 +547,8 @@ func massSaberEncoding(st Saberinput) error {
 
 	reader := csv.NewReader(bufio.NewReader(csvFile))
 
-	for {
-		i++
-		line, err := reader.Read()
-		if err == io.EOF {
-			log.Fatal("io read error", err)
-		}
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		if len(line) != 22 {
-			fmt.Printf("breaking line number: %d \n", i)
-			fmt.Printf("Line has wrong length:%d \n", len(line))
-			fmt.Printf("Line: %+v", line)
-			continue
-		}
-		tx := NVCsvConverterNew(line)
-
+	// submit encrypts, signs and broadcasts a bill of lading, read up to line i
+	submit := func(tx *BFTXTransaction, i int) error {
 		bfencreq := BFTX_EncodeRequest{
 			Bftxtrans:  tx,
 			Bftxconfig: txconfig,
@@ -373,8 +556,9 @@ func massSaberEncoding(st Saberinput) error {
 		bfencr, err := bfsaberclient.BFTX_Encode(context.Background(), &bfencreq)
 		if err != nil {
 			log.Printf("Line %d, BFTX_Encode error: %v", i, err)
-			continue
+			return nil
 		}
+		markEncrypted(bfencr.Properties, txconfig)
 
 		// do the bftx sign--------------------------------------
 		oldbf, err := BftxStructConverstionNO(bfencr)
@@ -398,12 +582,15 @@ func massSaberEncoding(st Saberinput) error {
 		bfmsg.Id = bftxID
 		// do the bftx sign--------------------------------------
 
-		bfmsg, err = crypto.SignBFTX(bfmsg)
+		bfmsg, err = crypto.SignBFTX(bfmsg, privKey)
+		if err != nil {
+			return err
+		}
+		// Only a signed BF_TX can be submitted
+		bfmsg, err = btx.Transition(bfmsg, btx.StateSubmitted)
 		if err != nil {
 			return err
 		}
-		// Change the boolean valud for Transmitted attribute
-		bfmsg.Transmitted = true
 
 		// Get the BF_TX content in string format
 		content, err := btx.BFTXContent(bfmsg)
@@ -418,10 +605,37 @@ func massSaberEncoding(st Saberinput) error {
 			return err
 		}
 
-		resp, err := rpcClient.BroadcastTxSync([]byte(content))
+		// Wrap the BF_TX in an issue transaction envelope, signed with the same key so the party is its issuer
+		env, err := btx.NewBFTXEnvelope(bfmsg)
+		if err != nil {
+			log.Fatal("NewBFTXEnvelope error", err)
+			return err
+		}
+		env.ChainID = chainID
+		env.Sequence = sequence
+		envelope, err := json.Marshal(crypto.SignEnvelope(env, privKey))
+		if err != nil {
+			log.Fatal("Envelope encoding error", err)
+			return err
+		}
+
+		resp, err := rpcClient.BroadcastTxSync(envelope)
 		if err != nil {
 			log.Fatal("rpcclient err:", err)
 		}
+		// The network limits how much an account sends in a window of blocks, back off until the next one
+		for resp.Code == bft.CodeTypeQuotaExceeded {
+			log.Printf("Line %d, %s", i, resp.Log)
+			time.Sleep(quotaBackoff)
+			resp, err = rpcClient.BroadcastTxSync(envelope)
+			if err != nil {
+				log.Fatal("rpcclient err:", err)
+			}
+		}
+		// A rejected envelope does not use up its sequence
+		if resp.Code == abcitypes.CodeTypeOK {
+			sequence++
+		}
 		// added for flow control
 
 		if i%100 == 0 {
@@ -431,10 +645,50 @@ func massSaberEncoding(st Saberinput) error {
 		} else {
 			fmt.Print(i, ",")
 		}
-
+		return nil
 	}
-	return err
 
+	// Consecutive lines of the same HouseBill are the containers of one bill of lading
+	var pending *BFTXTransaction
+	for {
+		i++
+		line, err := reader.Read()
+		if err == io.EOF {
+			if pending != nil {
+				return submit(pending, i-1)
+			}
+			return nil
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// A 23rd column is the HS code of the goods of the line
+		if len(line) != 22 && len(line) != 23 {
+			fmt.Printf("breaking line number: %d \n", i)
+			fmt.Printf("Line has wrong length:%d \n", len(line))
+			fmt.Printf("Line: %+v", line)
+			continue
+		}
+		// A package count the BF_TX cannot decode would stop the whole load
+		if _, err := btx.ParseCount(line[17]); err != nil {
+			log.Printf("Line %d, %v", i, err)
+			continue
+		}
+
+		if pending != nil && line[3] != "" && line[3] == pending.Properties.HouseBill {
+			if err := NVCsvAppendContainer(pending, line); err != nil {
+				log.Printf("Line %d, %v", i, err)
+			}
+			continue
+		}
+		if pending != nil {
+			if err := submit(pending, i-1); err != nil {
+				return err
+			}
+		}
+		pending = NVCsvConverterNew(line)
+	}
 }
 
 // SaberDecodingTestCase is the function that enable it to connect to a container which realizing the
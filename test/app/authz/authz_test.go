@@ -0,0 +1,117 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/blockfreight/go-bftx/lib/app/authz"
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+)
+
+func TestDefaultRules(t *testing.T) {
+	t.Log("Test on Authorize function with the default rules")
+	rules := authz.DefaultRules()
+	if err := rules.Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := rules.Authorize(bf_tx.OpIssue, authz.Signer{Role: bf_tx.RoleCarrier}); err != nil {
+		t.Errorf("Error on Authorize of an issue by a carrier: %s", err.Error())
+	}
+	if err := rules.Authorize(bf_tx.OpIssue, authz.Signer{Role: bf_tx.RoleAgent}); err == nil {
+		t.Error("Error on Authorize, an agent that does not act for a carrier should not issue")
+	}
+	if err := rules.Authorize(bf_tx.OpTransfer, authz.Signer{Role: bf_tx.RoleBank, Relations: []string{authz.RelationHolder}}); err != nil {
+		t.Errorf("Error on Authorize of a transfer by the holder: %s", err.Error())
+	}
+	if err := rules.Authorize(bf_tx.OpTransfer, authz.Signer{Role: bf_tx.RoleCarrier, Relations: []string{authz.RelationIssuer}}); err == nil {
+		t.Error("Error on Authorize, only the holder should transfer")
+	}
+	delete(rules, bf_tx.OpVoid)
+	if err := rules.Authorize(bf_tx.OpVoid, authz.Signer{Role: bf_tx.RoleCarrier, Relations: []string{authz.RelationIssuer}}); err == nil {
+		t.Error("Error on Authorize, an operation without a rule should not be allowed")
+	}
+}
+
+func TestInvariants(t *testing.T) {
+	t.Log("Test on Authorize function with rules that give a transfer away to a role")
+	rules := authz.Rules{bf_tx.OpTransfer: {Allow: []authz.Grant{{Role: bf_tx.RoleCarrier}}}}
+	if err := rules.Authorize(bf_tx.OpTransfer, authz.Signer{Role: bf_tx.RoleCarrier}); err == nil {
+		t.Error("Error on Authorize, a carrier that does not hold the bill of lading should not transfer it")
+	}
+	if err := rules.Authorize(bf_tx.OpTransfer, authz.Signer{Role: bf_tx.RoleCarrier, Relations: []string{authz.RelationHolder}}); err != nil {
+		t.Errorf("Error on Authorize of a transfer by the holder: %s", err.Error())
+	}
+}
+
+func TestAuthorizeAmendment(t *testing.T) {
+	t.Log("Test on AuthorizeAmendment function with a change of the description of the goods")
+	rules := authz.DefaultRules()
+	before := bf_tx.Properties{Shipper: bf_tx.TradeParty{Name: "shipper1"}, DescOfGoods: "Fish"}
+	after := before
+	after.DescOfGoods = "Frozen fish"
+
+	shipper := authz.Signer{Role: bf_tx.RoleShipper, Relations: []string{authz.RelationShipper}}
+
+	err := rules.AuthorizeAmendment(authz.Signer{Role: bf_tx.RoleCarrier, Relations: []string{authz.RelationIssuer}}, bf_tx.StateCommitted, before, after)
+	if _, ok := err.(authz.FieldError); !ok {
+		t.Errorf("Error on AuthorizeAmendment, expected a FieldError and got %v", err)
+	}
+	if err := rules.AuthorizeAmendment(shipper, bf_tx.StatePending, before, after); err != nil {
+		t.Errorf("Error on AuthorizeAmendment by the shipper: %s", err.Error())
+	}
+	if err := rules.AuthorizeAmendment(shipper, bf_tx.StateCommitted, before, after); err == nil {
+		t.Error("Error on AuthorizeAmendment, the shipper should not amend a committed bill of lading")
+	}
+	after.Vessel = "Vessel Two"
+	err = rules.AuthorizeAmendment(shipper, bf_tx.StatePending, before, after)
+	if fieldErr, ok := err.(authz.FieldError); !ok || fieldErr.Field != "Vessel" {
+		t.Errorf("Error on AuthorizeAmendment, expected a FieldError on Vessel and got %v", err)
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	t.Log("Test on ChangedFields function with changes of the containers")
+	before := bf_tx.Properties{Containers: []bf_tx.Container{{Container: "CSQU3054383", Items: []bf_tx.CargoItem{{DescOfGoods: "Fish"}}}}}
+	after := bf_tx.Properties{Containers: []bf_tx.Container{{Container: "CSQU3054383", Items: []bf_tx.CargoItem{{DescOfGoods: "Frozen fish"}}}}}
+	if changed := authz.ChangedFields(before, after); len(changed) != 1 || changed[0] != authz.FieldCargoItems {
+		t.Errorf("Error on ChangedFields of the cargo items, expected [%s] and got %v", authz.FieldCargoItems, changed)
+	}
+	after.Containers[0].Container = "MSKU1234565"
+	if changed := authz.ChangedFields(before, after); len(changed) != 1 || changed[0] != "Containers" {
+		t.Errorf("Error on ChangedFields of a container number, expected [Containers] and got %v", changed)
+	}
+}
+
+func TestAuthorizeRole(t *testing.T) {
+	t.Log("Test on AuthorizeRole function")
+	rules := authz.DefaultRules()
+	if err := rules.AuthorizeRole(bf_tx.OpIssue, bf_tx.RoleShipper); err == nil {
+		t.Error("Error on AuthorizeRole, a shipper should never issue")
+	}
+	if err := rules.AuthorizeRole(bf_tx.OpIssue, bf_tx.RoleAgent); err != nil {
+		t.Errorf("Error on AuthorizeRole of an issue by an agent: %s", err.Error())
+	}
+	if err := rules.AuthorizeRole(bf_tx.OpTransfer, ""); err != nil {
+		t.Errorf("Error on AuthorizeRole of a transfer by an unregistered key: %s", err.Error())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Log("Test on Validate function with unknown roles and fields")
+	rules := authz.Rules{bf_tx.OpIssue: {Allow: []authz.Grant{{Role: "pirate"}}}}
+	if err := rules.Validate(); err == nil {
+		t.Error("Error on Validate, an unknown role should fail")
+	}
+	rules = authz.Rules{bf_tx.OpAmend: {Fields: map[string][]authz.Grant{"Treasure": {{Relation: authz.RelationIssuer}}}}}
+	if err := rules.Validate(); err == nil {
+		t.Error("Error on Validate, an unknown field should fail")
+	}
+	rules = authz.Rules{bf_tx.OpTransfer: {Allow: []authz.Grant{{Relation: authz.RelationHolder, Fields: []string{"Vessel"}}}}}
+	if err := rules.Validate(); err == nil {
+		t.Error("Error on Validate, a grant of a transfer limited to fields should fail")
+	}
+	rules = authz.Rules{bf_tx.OpRegisterParty: {Allow: []authz.Grant{{Role: bf_tx.RoleCarrier}}}}
+	if err := rules.Validate(); err == nil {
+		t.Error("Error on Validate, a rule for an operation the rules do not authorize should fail")
+	}
+}
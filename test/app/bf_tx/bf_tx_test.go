@@ -1,6 +1,11 @@
 package bf_tx
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
 	"reflect"
 	"testing"
 
@@ -19,51 +24,60 @@ func TestSetBFTX(t *testing.T) {
 	}
 }
 
-func TestTransmitedState(t *testing.T) {
-	t.Log("Test on State function")
+func TestSubmittedState(t *testing.T) {
+	t.Log("Test on State and Transition functions")
 	newBftx, err := bftx.SetBFTX("../../../examples/bf_tx_example.json")
 	if err != nil {
 		t.Log(err.Error())
 	}
 
-	newBftx.Transmitted = true
-	result := bftx.State(newBftx)
-
-	if result != "Transmitted!" {
-		t.Error("Error on string result of bftx.State() when Transmitted = true")
+	for _, state := range []bftx.Lifecycle{bftx.StateSigned, bftx.StateSubmitted} {
+		newBftx, err = bftx.Transition(newBftx, state)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 	}
 
+	if result := bftx.State(newBftx); result != bftx.StateSubmitted {
+		t.Errorf("Error on result of bftx.State() after submission, got %s", result)
+	}
 }
 
-func TestSignedState(t *testing.T) {
+func TestDraftState(t *testing.T) {
 	t.Log("Test on State function")
 	newBftx, err := bftx.SetBFTX("../../../examples/bf_tx_example.json")
 	if err != nil {
 		t.Log(err.Error())
 	}
 
-	newBftx.Verified = true
-	result := bftx.State(newBftx)
-
-	if result != "Signed!" {
-		t.Error("Error on string result of bftx.State() when Verified = true")
+	if result := bftx.State(newBftx); result != bftx.StateDraft {
+		t.Errorf("Error on result of bftx.State() when Transaction is constructed, got %s", result)
 	}
-
 }
 
-func TestConstructedState(t *testing.T) {
-	t.Log("Test on State function")
-	newBftx, err := bftx.SetBFTX("../../../examples/bf_tx_example.json")
-	if err != nil {
-		t.Log(err.Error())
-	}
+func TestIllegalTransition(t *testing.T) {
+	t.Log("Test on Transition function with a transition the lifecycle does not allow")
+	newBftx := bftx.BF_TX{Id: "BFTX1"}
 
-	result := bftx.State(newBftx)
+	_, err := bftx.Transition(newBftx, bftx.StateSubmitted)
+	if transitionErr, ok := err.(bftx.TransitionError); !ok || transitionErr.From != bftx.StateDraft || transitionErr.To != bftx.StateSubmitted {
+		t.Errorf("Error on bftx.Transition() from Draft to Submitted, got %v", err)
+	}
+	if bftx.CanTransition(bftx.StateAccomplished, bftx.StateTransferred) {
+		t.Error("Error on bftx.CanTransition(), an accomplished BF_TX cannot be transferred")
+	}
+}
 
-	if result != "Constructed!" {
-		t.Error("Error on string result of bftx.State() when Transaction is Constructed")
+func TestLegacyState(t *testing.T) {
+	t.Log("Test on decoding the state of a BF_TX encoded with the Verified and Transmitted flags")
+	var newBftx bftx.BF_TX
+	if err := json.Unmarshal([]byte(`{"Id":"BFTX1","Verified":true,"Transmitted":true}`), &newBftx); err != nil {
+		t.Fatal(err.Error())
 	}
 
+	if newBftx.Id != "BFTX1" || bftx.State(newBftx) != bftx.StateSubmitted {
+		t.Errorf("Error decoding a transmitted legacy BF_TX, got %+v", newBftx)
+	}
 }
 
 func TestReinitialize(t *testing.T) {
@@ -72,7 +86,317 @@ func TestReinitialize(t *testing.T) {
 
 	newBftx := bftx.Reinitialize(prop)
 
-	if newBftx.PrivateKey.Curve != nil || newBftx.PrivateKey.X != nil || newBftx.PrivateKey.D != nil || newBftx.Signhash != nil || newBftx.Signature != "" || newBftx.Verified != false || newBftx.Transmitted != false {
+	if newBftx.Signer != nil || newBftx.Signhash != nil || newBftx.Signature != "" || newBftx.Lifecycle != bftx.StateDraft {
 		t.Error("Error on BF_TX object returned by function bf_tx.Reinitialize()")
 	}
 }
+
+func TestDecodeEnvelope(t *testing.T) {
+	t.Log("Test on DecodeEnvelope function")
+	legacy := []byte(`{"Id":"BFTX2","Amendment":"BFTX1"}`)
+	env, err := bftx.DecodeEnvelope(legacy)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if env.Version != 0 || env.Type != bftx.OpAmend || string(env.Payload) != string(legacy) {
+		t.Errorf("Error decoding a bare BF_TX, got %+v", env)
+	}
+
+	env, err = bftx.NewEnvelope(bftx.OpSurrender, bftx.Surrender{Id: "BFTX1"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tx, _ := json.Marshal(env)
+	decoded, err := bftx.DecodeEnvelope(tx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(decoded, env) {
+		t.Errorf("Error decoding an envelope, expected %+v and got %+v", env, decoded)
+	}
+
+	if _, err := bftx.DecodeEnvelope([]byte(`{"Version":99,"Type":"issue"}`)); err == nil {
+		t.Error("Error decoding an envelope of an unsupported version, it should fail")
+	}
+}
+
+func TestCanonicalBFTX(t *testing.T) {
+	t.Log("Test on CanonicalBFTX function against its golden vector")
+	newBftx, err := bftx.SetBFTX("../../../examples/bf_tx_example.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	newBftx.Id = "BFTXgolden"
+	newBftx.Signer = []byte{1, 2, 3}
+	newBftx.Signature = "abcd"
+	newBftx.Lifecycle = bftx.StateCommitted
+	newBftx.BlockHeight = 7
+
+	expected, err := ioutil.ReadFile("testdata/bf_tx_example.canonical.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	canonical, err := bftx.CanonicalBFTX(newBftx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !bytes.Equal(canonical, expected) {
+		t.Errorf("Error on CanonicalBFTX, expected %s and got %s", expected, canonical)
+	}
+}
+
+func TestHashBFTX(t *testing.T) {
+	t.Log("Test on HashBFTX and GenerateBFTXUID functions against their golden vectors")
+	newBftx, err := bftx.SetBFTX("../../../examples/bf_tx_example.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	hash, err := bftx.HashBFTX(newBftx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result := hex.EncodeToString(hash); result != "b10ce5aba213989c4de42989405e223cd359d905c7af07308c42425f59e780a5" {
+		t.Errorf("Error on HashBFTX of the example BF_TX, got %s", result)
+	}
+	if result := bftx.GenerateBFTXUID(hash, []byte("salt")); result != "BFTXf8db894692edfb32b434f7fb3ed0ca16edb619dd0c7c56b084760e0e63aba78d" {
+		t.Errorf("Error on GenerateBFTXUID of the example BF_TX, got %s", result)
+	}
+
+	// The Id, Signer, signature and state attributes do not change the hash, the document does
+	signed := newBftx
+	signed.Id = "BFTXgolden"
+	signed.Signer = []byte{1, 2, 3}
+	signed.Signature = "abcd"
+	signed.Lifecycle = bftx.StateCommitted
+	if signedHash, _ := bftx.HashBFTX(signed); !bytes.Equal(signedHash, hash) {
+		t.Error("Error on HashBFTX, the Id, signature and state attributes changed the hash")
+	}
+	newBftx.Properties.Vessel = "Another vessel"
+	if changedHash, _ := bftx.HashBFTX(newBftx); bytes.Equal(changedHash, hash) {
+		t.Error("Error on HashBFTX, a changed document has the same hash")
+	}
+}
+
+func TestTypedValues(t *testing.T) {
+	t.Log("Test on decoding and encoding the typed Properties values")
+	var properties bftx.Properties
+	legacy := `{"GrossWeight":"15523","Volume":"","Packages":"12","FreightPayableAmt":"354534","DateShipped":"20161128"}`
+	if err := json.Unmarshal([]byte(legacy), &properties); err != nil {
+		t.Fatal(err.Error())
+	}
+	if properties.GrossWeight != (bftx.Quantity{Value: "15523"}) || properties.Packages != "12" || properties.FreightPayableAmt.Amount != "354534" {
+		t.Errorf("Error decoding legacy Properties values, got %+v", properties)
+	}
+	if date, err := properties.DateShipped.Time(); err != nil || date.Day() != 28 {
+		t.Errorf("Error on Time of the legacy date %s, got %v", properties.DateShipped, err)
+	}
+
+	typed := `{"GrossWeight":{"Value":"15523.5","Unit":"KGM"},"Packages":3,"FreightPayableAmt":"354534.00 USD","DateShipped":"2016-11-28"}`
+	if err := json.Unmarshal([]byte(typed), &properties); err != nil {
+		t.Fatal(err.Error())
+	}
+	if properties.GrossWeight != (bftx.Quantity{Value: "15523.5", Unit: "KGM"}) || properties.Packages != "3" || properties.FreightPayableAmt != (bftx.Money{Amount: "354534.00", Currency: "USD"}) {
+		t.Errorf("Error decoding typed Properties values, got %+v", properties)
+	}
+
+	// Every value encodes as its text form, legacy strings back to themselves
+	for _, value := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{properties.GrossWeight, `"15523.5 KGM"`},
+		{properties.FreightPayableAmt, `"354534.00 USD"`},
+		{properties.Packages, `"3"`},
+		{bftx.NewCount(0), `""`},
+		{bftx.Count("0"), `"0"`},
+		{bftx.Count(" 12"), `" 12"`},
+		{bftx.Count("12 CTNS"), `"12 CTNS"`},
+		{bftx.ParseQuantity("15,523 kg "), `"15,523 kg "`},
+		{bftx.ParseMoney(" 10"), `" 10"`},
+	} {
+		if encoded, err := json.Marshal(value.value); err != nil || string(encoded) != value.expected {
+			t.Errorf("Error encoding %#v, expected %s and got %s", value.value, value.expected, encoded)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"Packages":"12 CTNS"}`), &properties); err != nil {
+		t.Errorf("Error decoding the legacy count 12 CTNS, got %v", err)
+	}
+	if value, ok := properties.Packages.Int(); !ok || value != 12 || properties.Packages.Valid() {
+		t.Errorf("Error on the legacy count %q, expected the number 12 of an invalid count and got %d", properties.Packages, value)
+	}
+	if err := json.Unmarshal([]byte(`{"Packages":true}`), &properties); err == nil {
+		t.Error("Error decoding Packages, a count that is neither text nor a number should not decode")
+	}
+	if total, ok := bftx.Decimal("0.10").Rat(); !ok || total.Add(total, big.NewRat(2, 10)).Cmp(big.NewRat(3, 10)) != 0 {
+		t.Error("Error on Rat, decimals should add up exactly")
+	}
+}
+
+func TestSchemaMigration(t *testing.T) {
+	t.Log("Test on DecodeBFTX and MigrateBFTX functions with BF_TX of every schema version")
+	for _, record := range []struct {
+		data     string
+		version  bftx.SchemaVersion
+		expected bftx.Lifecycle
+	}{
+		{`{"Id":"BFTX1","Verified":true,"Transmitted":true}`, bftx.SchemaFlags, bftx.StateSubmitted},
+		{`{"Properties":{"Packages":"12"},"Id":"BFTX1","PrivateKey":{"PubKey":"04ab"},"Signhash":"AQI=","Verified":true}`, bftx.SchemaFlags, bftx.StateSigned},
+		{`{"Id":"BFTX1","Verified":true,"BlockHeight":7}`, bftx.SchemaFlags, bftx.StateCommitted},
+		{`{"Properties":{"Packages":"12","GrossWeight":"100 KGS"},"Id":"BFTX1","Lifecycle":"Signed"}`, bftx.SchemaLifecycle, bftx.StateSigned},
+		{`{"Properties":{"Packages":"12 CTNS"},"Id":"BFTX1","Lifecycle":"Signed"}`, bftx.SchemaLifecycle, bftx.StateSigned},
+		{`{"Properties":{"Packages":" 0"},"Id":"BFTX1","Lifecycle":"Signed"}`, bftx.SchemaLifecycle, bftx.StateSigned},
+		{`{"Schema":3,"Id":"BFTX1","Lifecycle":"Committed"}`, bftx.SchemaTyped, bftx.StateCommitted},
+		{`{"Schema":4,"Properties":{"Shipper":"VLX454323F"},"Id":"BFTX1","Lifecycle":"Committed"}`, bftx.SchemaContainers, bftx.StateCommitted},
+	} {
+		decoded, version, err := bftx.DecodeBFTX([]byte(record.data))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if version != record.version || decoded.Schema != bftx.CurrentSchema || bftx.State(decoded) != record.expected {
+			t.Errorf("Error decoding %s, expected version %d and state %s and got %d and %s", record.data, record.version, record.expected, version, bftx.State(decoded))
+		}
+
+		// A migrated BF_TX is in the current schema version and keeps its canonical encoding
+		migrated, _, err := bftx.MigrateBFTX([]byte(record.data))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if version, _ := bftx.DetectSchema(migrated); version != bftx.CurrentSchema {
+			t.Errorf("Error on MigrateBFTX of %s, expected version %d and got %d", record.data, bftx.CurrentSchema, version)
+		}
+		redecoded, _, _ := bftx.DecodeBFTX(migrated)
+		before, _ := bftx.CanonicalBFTX(decoded)
+		after, _ := bftx.CanonicalBFTX(redecoded)
+		if !bytes.Equal(before, after) {
+			t.Errorf("Error on MigrateBFTX of %s, the canonical encoding changed from %s to %s", record.data, before, after)
+		}
+	}
+
+	if _, _, err := bftx.DecodeBFTX([]byte(`{"Schema":99,"Id":"BFTX1"}`)); err == nil {
+		t.Error("Error decoding a BF_TX of an unknown schema version, it should fail")
+	}
+	if _, _, err := bftx.MigrateBFTX([]byte(`{"Id":"BFTX1","Lifecycle":"Signed","Properties":{"Packages":true}}`)); err == nil {
+		t.Error("Error on MigrateBFTX of a BF_TX with properties that do not migrate, it should fail")
+	}
+}
+
+func TestLegacyAmendment(t *testing.T) {
+	t.Log("Test on LegacyAmendedBy function and the migration of a legacy forward Amendment link")
+	legacy := []byte(`{"Id":"BFTX1","Lifecycle":"Committed","Amendment":"BFTX2"}`)
+	if successor, err := bftx.LegacyAmendedBy(legacy); err != nil || successor != "BFTX2" {
+		t.Errorf("Error on LegacyAmendedBy of %s, expected BFTX2 and got %q", legacy, successor)
+	}
+	decoded, _, err := bftx.DecodeBFTX(legacy)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if decoded.Amendment != "" {
+		t.Errorf("Error decoding %s, the forward link was read as the BF_TX it amends, %s", legacy, decoded.Amendment)
+	}
+
+	current := []byte(`{"Schema":5,"Id":"BFTX2","Lifecycle":"Committed","Amendment":"BFTX1"}`)
+	if successor, err := bftx.LegacyAmendedBy(current); err != nil || successor != "" {
+		t.Errorf("Error on LegacyAmendedBy of %s, expected no forward link and got %q", current, successor)
+	}
+	if decoded, _, _ := bftx.DecodeBFTX(current); decoded.Amendment != "BFTX1" {
+		t.Errorf("Error decoding %s, expected Amendment BFTX1 and got %q", current, decoded.Amendment)
+	}
+}
+
+func TestContainerTotals(t *testing.T) {
+	t.Log("Test on ContainerTotals and ItemTotals functions")
+	containers := []bftx.Container{
+		{Container: "CSQU3054383", Items: []bftx.CargoItem{
+			{Packages: "10", GrossWeight: bftx.Quantity{Value: "5000.25", Unit: "KGM"}, Volume: bftx.Quantity{Value: "20"}},
+			{Packages: "8", GrossWeight: bftx.Quantity{Value: "3000"}, Volume: bftx.Quantity{Value: "8.5", Unit: "MTQ"}},
+		}},
+		{Container: "MSKU1234565", Packages: "12", GrossWeight: bftx.Quantity{Value: "7523.25"}},
+	}
+
+	totals, err := bftx.ContainerTotals(containers)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := bftx.CargoTotals{Packages: "30", GrossWeight: bftx.Quantity{Value: "15523.50", Unit: "KGM"}, Volume: bftx.Quantity{Value: "28.5", Unit: "MTQ"}}
+	if totals != expected {
+		t.Errorf("Error on ContainerTotals, expected %+v and got %+v", expected, totals)
+	}
+
+	// Stated values compare by their decimal value, and a value left out is not compared
+	if mismatches := totals.Mismatches(bftx.CargoTotals{Packages: "30", GrossWeight: bftx.Quantity{Value: "15523.5", Unit: "KGM"}}); len(mismatches) != 0 {
+		t.Errorf("Error on Mismatches of the totals, got %v", mismatches)
+	}
+	if mismatches := totals.Mismatches(bftx.CargoTotals{Packages: "31", Volume: bftx.Quantity{Value: "28.5", Unit: "LTR"}}); !reflect.DeepEqual(mismatches, []string{"Packages", "Volume"}) {
+		t.Errorf("Error on Mismatches of other values, expected [Packages Volume] and got %v", mismatches)
+	}
+
+	if _, err := bftx.SumQuantities(bftx.Quantity{Value: "1", Unit: "KGM"}, bftx.Quantity{Value: "1", Unit: "LBR"}); err == nil {
+		t.Error("Error on SumQuantities, quantities of different units should not add up")
+	}
+	if numbers := bftx.ContainerNumbers(bftx.Properties{Container: "CSQU3054383", Containers: containers}); !reflect.DeepEqual(numbers, []string{"CSQU3054383", "MSKU1234565"}) {
+		t.Errorf("Error on ContainerNumbers, got %v", numbers)
+	}
+}
+
+func TestTradeParty(t *testing.T) {
+	t.Log("Test on decoding and encoding legacy and structured parties")
+	var properties bftx.Properties
+	legacy := `{"Shipper":"VLX454323F","Consignee":"To order of Bank One","NotifyAddress":null}`
+	if err := json.Unmarshal([]byte(legacy), &properties); err != nil {
+		t.Fatal(err.Error())
+	}
+	if properties.Shipper.Name != "VLX454323F" || properties.Shipper.Ref() != "VLX454323F" || !properties.NotifyAddress.Empty() {
+		t.Errorf("Error decoding legacy parties, got %+v", properties)
+	}
+
+	structured := `{"Consignee":{"ToOrder":true,"Name":"Bank One","Country":"SG","Party":"bank1"},"MasterInfo":{"FirstName":"Jo","Details":{"Name":"Jo Tan","Email":"jo@example.com"}}}`
+	if err := json.Unmarshal([]byte(structured), &properties); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !properties.Consignee.ToOrder || properties.Consignee.Ref() != "bank1" || properties.MasterInfo.Details == nil || properties.MasterInfo.Details.Email != "jo@example.com" {
+		t.Errorf("Error decoding structured parties, got %+v", properties)
+	}
+
+	// A legacy party encodes as its name, any other as an object of its fields
+	for _, party := range []struct {
+		party    bftx.TradeParty
+		expected string
+	}{
+		{bftx.TradeParty{Name: "VLX454323F"}, `"VLX454323F"`},
+		{bftx.TradeParty{Name: "Acme", Address: []string{}}, `"Acme"`},
+		{bftx.TradeParty{Name: "Acme", Country: "SG"}, `{"Name":"Acme","Country":"SG"}`},
+		{bftx.ParseParty(`{"Name":"Acme"}`), `"{\"Name\":\"Acme\"}"`},
+	} {
+		if encoded, err := json.Marshal(party.party); err != nil || string(encoded) != party.expected {
+			t.Errorf("Error encoding %#v, expected %s and got %s", party.party, party.expected, encoded)
+		}
+	}
+
+	// The saber proto carries the parties as text, which decodes back to the same parties
+	text := bftx.PartiesAsText(properties)
+	if text.Consignee.Name != properties.Consignee.String() || text.MasterInfo.Details.Name != properties.MasterInfo.Details.String() || properties.MasterInfo.Details.Name != "Jo Tan" {
+		t.Errorf("Error on PartiesAsText, got %+v", text)
+	}
+	if parsed := bftx.ParseParty(text.Consignee.Name); !reflect.DeepEqual(parsed, properties.Consignee) {
+		t.Errorf("Error on ParseParty of %s, got %+v", text.Consignee.Name, parsed)
+	}
+}
+
+func TestEncryptedFields(t *testing.T) {
+	t.Log("Test on EncryptedFields and EncryptionMetaData functions")
+	var properties bftx.Properties
+	if encrypted := bftx.EncryptedFields(properties); len(encrypted) != 0 {
+		t.Errorf("Error on EncryptedFields of a BF_TX without EncryptionMetaData, got %v", encrypted)
+	}
+
+	properties.EncryptionMetaData = bftx.EncryptionMetaData([]string{"GrossWeight", " Shipper", ""})
+	if properties.EncryptionMetaData != "GrossWeight,Shipper" {
+		t.Errorf("Error on EncryptionMetaData, expected GrossWeight,Shipper and got %q", properties.EncryptionMetaData)
+	}
+	expected := map[string]bool{"grossweight": true, "shipper": true}
+	if encrypted := bftx.EncryptedFields(properties); !reflect.DeepEqual(encrypted, expected) {
+		t.Errorf("Error on EncryptedFields of %q, got %v", properties.EncryptionMetaData, encrypted)
+	}
+}
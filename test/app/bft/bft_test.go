@@ -0,0 +1,1247 @@
+package bft
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/blockfreight/go-bftx/lib/app/authz"
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/app/bft"
+	"github.com/blockfreight/go-bftx/lib/app/params"
+	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
+	"github.com/tendermint/abci/types"
+	tmcrypto "github.com/tendermint/go-crypto"
+)
+
+// issuerKey is the key of the registered carrier newApp signs example BF_TX with
+var issuerKey = tmcrypto.GenPrivKeyEd25519().Wrap()
+
+// testValidator is the validator of the chains newApp starts, which approves their carriers
+var testValidator = tmcrypto.GenPrivKeyEd25519().Wrap()
+
+func newApp(t *testing.T) *bft.BftApplication {
+	app := bft.NewBftApplication()
+	app.InitChain(types.RequestInitChain{Validators: []*types.Validator{{PubKey: testValidator.PubKey().Bytes(), Power: 10}}})
+	registerParty(t, app, bf_tx.Party{Id: "carrier0", Name: "Carrier Zero", Role: bf_tx.RoleCarrier, PubKeys: [][]byte{issuerKey.PubKey().Bytes()}}, issuerKey)
+	return app
+}
+
+// registerParty registers a party with its key, and has the test validator approve it if it is a carrier
+func registerParty(t *testing.T, app *bft.BftApplication, party bf_tx.Party, key tmcrypto.PrivKey) {
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, key)); resDeliver.Code != 0 {
+		t.Fatal(resDeliver.Log)
+	}
+	if party.Role != bf_tx.RoleCarrier {
+		return
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: party.Id}, testValidator)); resDeliver.Code != 0 {
+		t.Fatal(resDeliver.Log)
+	}
+}
+
+func exampleTx(t *testing.T, signed bool, transmitted bool) []byte {
+	tx, err := json.Marshal(exampleBFTX(t, "BFTXtest", "", signed, transmitted))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return tx
+}
+
+func exampleBFTX(t *testing.T, id string, amendment string, signed bool, transmitted bool) bf_tx.BF_TX {
+	bftx, err := bf_tx.SetBFTX("../../../examples/bf_tx_example.json")
+	if err != nil {
+		t.Log(err.Error())
+	}
+	bftx.Id = id
+	bftx.Amendment = amendment
+	if signed {
+		bftx, err = crypto.SignBFTX(bftx, issuerKey)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if transmitted {
+		bftx.Lifecycle = bf_tx.StateSubmitted
+	}
+	return bftx
+}
+
+func TestCheckTxEncodingError(t *testing.T) {
+	t.Log("Test on CheckTx function with a malformed transaction")
+	app := newApp(t)
+
+	res := app.CheckTx([]byte("not a BF_TX"))
+	if res.Code != bft.CodeTypeEncodingError {
+		t.Errorf("Error on CheckTx code, expected %d and got %d", bft.CodeTypeEncodingError, res.Code)
+	}
+}
+
+func TestCheckTxUnsigned(t *testing.T) {
+	t.Log("Test on CheckTx function with an unsigned transaction")
+	app := newApp(t)
+
+	res := app.CheckTx(exampleTx(t, false, true))
+	if res.Code != bft.CodeTypeUnsigned {
+		t.Errorf("Error on CheckTx code, expected %d and got %d", bft.CodeTypeUnsigned, res.Code)
+	}
+}
+
+func TestCheckTxNotTransmitted(t *testing.T) {
+	t.Log("Test on CheckTx function with a transaction not transmitted")
+	app := newApp(t)
+
+	res := app.CheckTx(exampleTx(t, true, false))
+	if res.Code != bft.CodeTypeNotTransmitted {
+		t.Errorf("Error on CheckTx code, expected %d and got %d", bft.CodeTypeNotTransmitted, res.Code)
+	}
+}
+
+func TestCheckTxOK(t *testing.T) {
+	t.Log("Test on CheckTx function with a valid transaction")
+	app := newApp(t)
+
+	res := app.CheckTx(exampleTx(t, true, true))
+	if res.Code != 0 {
+		t.Errorf("Error on CheckTx code, expected 0 and got %d: %s", res.Code, res.Log)
+	}
+}
+
+func TestDeliverTxBadSignature(t *testing.T) {
+	t.Log("Test on DeliverTx function with a transaction modified after it was signed")
+	app := newApp(t)
+
+	var bftx bf_tx.BF_TX
+	json.Unmarshal(exampleTx(t, true, true), &bftx)
+	bftx.Properties.Shipper = bf_tx.TradeParty{Name: "Somebody else"}
+	tx, _ := json.Marshal(bftx)
+
+	res := app.DeliverTx(tx)
+	if res.Code != bft.CodeTypeBadSignature {
+		t.Errorf("Error on DeliverTx code, expected %d and got %d", bft.CodeTypeBadSignature, res.Code)
+	}
+}
+
+func TestDeliverTxDuplicateID(t *testing.T) {
+	t.Log("Test on DeliverTx function with a transaction delivered twice")
+	app := newApp(t)
+	tx := exampleTx(t, true, true)
+
+	res := app.DeliverTx(tx)
+	if res.Code != 0 {
+		t.Errorf("Error on DeliverTx code, expected 0 and got %d: %s", res.Code, res.Log)
+	}
+	res = app.DeliverTx(tx)
+	if res.Code != bft.CodeTypeDuplicateID {
+		t.Errorf("Error on DeliverTx code, expected %d and got %d", bft.CodeTypeDuplicateID, res.Code)
+	}
+}
+
+func TestCommitBlockHeight(t *testing.T) {
+	t.Log("Test on Commit function saving the state at the block height")
+	app := newApp(t)
+
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 7, Time: 1511827200}})
+	res := app.DeliverTx(exampleTx(t, true, true))
+	if res.Code != 0 {
+		t.Errorf("Error on DeliverTx code, expected 0 and got %d: %s", res.Code, res.Log)
+	}
+	app.EndBlock(types.RequestEndBlock{Height: 7})
+	app.Commit()
+
+	if info := app.Info(types.RequestInfo{}); info.LastBlockHeight != 7 {
+		t.Errorf("Error on Info LastBlockHeight, expected 7 and got %d", info.LastBlockHeight)
+	}
+
+	var bftx bf_tx.BF_TX
+	resQuery := app.Query(types.RequestQuery{Data: []byte("BFTXtest")})
+	if err := json.Unmarshal(resQuery.Value, &bftx); err != nil {
+		t.Fatal(err.Error())
+	}
+	if bftx.BlockHeight != 7 || bftx.BlockTime != 1511827200 {
+		t.Errorf("Error on stored BF_TX, expected block 7 at 1511827200 and got block %d at %d", bftx.BlockHeight, bftx.BlockTime)
+	}
+}
+
+func TestPersistentState(t *testing.T) {
+	t.Log("Test on NewPersistentBftApplication resuming from the last committed block after a restart")
+	dbDir, err := ioutil.TempDir("", "bftx")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dbDir)
+
+	app, err := bft.NewPersistentBftApplication(dbDir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	app.InitChain(types.RequestInitChain{Validators: []*types.Validator{{PubKey: testValidator.PubKey().Bytes(), Power: 10}}})
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 1, Time: 1511827200}})
+	registerParty(t, app, bf_tx.Party{Id: "carrier0", Name: "Carrier Zero", Role: bf_tx.RoleCarrier, PubKeys: [][]byte{issuerKey.PubKey().Bytes()}}, issuerKey)
+	app.Commit()
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 2, Time: 1511827260}})
+	if resDeliver := app.DeliverTx(exampleTx(t, true, true)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx code, expected 0 and got %d: %s", resDeliver.Code, resDeliver.Log)
+	}
+	app.Commit()
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 3, Time: 1511827320}})
+	resCommit := app.Commit()
+	app.Close()
+
+	app, err = bft.NewPersistentBftApplication(dbDir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer app.Close()
+
+	info := app.Info(types.RequestInfo{})
+	if info.LastBlockHeight != 3 {
+		t.Errorf("Error on Info LastBlockHeight, expected 3 and got %d", info.LastBlockHeight)
+	}
+	if !bytes.Equal(info.LastBlockAppHash, resCommit.Data) {
+		t.Errorf("Error on Info LastBlockAppHash, expected %X and got %X", resCommit.Data, info.LastBlockAppHash)
+	}
+
+	var bftx bf_tx.BF_TX
+	resQuery := app.Query(types.RequestQuery{Path: "/bftx/BFTXtest"})
+	if err := json.Unmarshal(resQuery.Value, &bftx); err != nil {
+		t.Fatal(err.Error())
+	}
+	if bftx.Id != "BFTXtest" || bftx.BlockHeight != 2 || bftx.BlockTime != 1511827260 {
+		t.Errorf("Error on stored BF_TX, expected BFTXtest of block 2 at 1511827260 and got %s of block %d at %d", bftx.Id, bftx.BlockHeight, bftx.BlockTime)
+	}
+	resQuery = app.Query(types.RequestQuery{Path: "/party/carrier0"})
+	if resQuery.Value == nil {
+		t.Error("Error on Query /party/carrier0, the registered party was lost")
+	}
+}
+
+func signedValSetChange(t *testing.T, chainID string, sequence uint64, signer tmcrypto.PrivKey, pubKey []byte, power int64) []byte {
+	change := bft.ValidatorSetChange{ChainID: chainID, Sequence: sequence, PubKey: pubKey, Power: power, Signer: signer.PubKey().Bytes()}
+	change.Signature = signer.Sign(change.SignBytes()).Bytes()
+	tx, err := bft.MakeValSetChangeTx(change)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return tx
+}
+
+func TestValidatorSetChange(t *testing.T) {
+	t.Log("Test on validator set changes through DeliverTx and EndBlock, approved by validators holding the vote threshold")
+	app := bft.NewBftApplication()
+	genesisKey := tmcrypto.GenPrivKeyEd25519().Wrap()
+	secondKey := tmcrypto.GenPrivKeyEd25519().Wrap()
+	newKey := tmcrypto.GenPrivKeyEd25519().Wrap()
+	app.SetGenesisState(bft.GenesisState{ChainID: "bftx-test", Params: params.Default()})
+	app.InitChain(types.RequestInitChain{Validators: []*types.Validator{
+		{PubKey: genesisKey.PubKey().Bytes(), Power: 10},
+		{PubKey: secondKey.PubKey().Bytes(), Power: 10},
+	}})
+
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{ChainId: "bftx-test", Height: 1}})
+	res := app.DeliverTx(signedValSetChange(t, "bftx-test", 1, newKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on DeliverTx code, expected %d and got %d", bft.CodeTypeUnauthorized, res.Code)
+	}
+	res = app.DeliverTx(signedValSetChange(t, "bftx-other", 1, genesisKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != bft.CodeTypeWrongChain {
+		t.Errorf("Error on DeliverTx code of a change for another chain, expected %d and got %d", bft.CodeTypeWrongChain, res.Code)
+	}
+	res = app.DeliverTx(signedValSetChange(t, "bftx-test", 2, genesisKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != bft.CodeTypeBadNonce {
+		t.Errorf("Error on DeliverTx code of a change ahead of the sequence, expected %d and got %d", bft.CodeTypeBadNonce, res.Code)
+	}
+
+	// Half of the power does not reach the vote threshold
+	res = app.DeliverTx(signedValSetChange(t, "bftx-test", 1, genesisKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != 0 {
+		t.Errorf("Error on DeliverTx code, expected 0 and got %d: %s", res.Code, res.Log)
+	}
+	res = app.DeliverTx(signedValSetChange(t, "bftx-test", 1, genesisKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != bft.CodeTypeDuplicateVote {
+		t.Errorf("Error on DeliverTx code of a second approval, expected %d and got %d", bft.CodeTypeDuplicateVote, res.Code)
+	}
+	if resEndBlock := app.EndBlock(types.RequestEndBlock{Height: 1}); len(resEndBlock.ValidatorUpdates) != 0 {
+		t.Errorf("Error on EndBlock validator updates, expected none below the vote threshold and got %+v", resEndBlock.ValidatorUpdates)
+	}
+	app.Commit()
+
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{ChainId: "bftx-test", Height: 2}})
+	res = app.DeliverTx(signedValSetChange(t, "bftx-test", 1, secondKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != 0 {
+		t.Errorf("Error on DeliverTx code, expected 0 and got %d: %s", res.Code, res.Log)
+	}
+	resEndBlock := app.EndBlock(types.RequestEndBlock{Height: 2})
+	if len(resEndBlock.ValidatorUpdates) != 1 || resEndBlock.ValidatorUpdates[0].Power != 10 {
+		t.Errorf("Error on EndBlock validator updates: %+v", resEndBlock.ValidatorUpdates)
+	}
+	app.Commit()
+
+	if len(app.Validators()) != 3 {
+		t.Errorf("Error on validator set, expected 3 validators and got %d", len(app.Validators()))
+	}
+	// The approvals cannot be replayed once the change applied
+	res = app.DeliverTx(signedValSetChange(t, "bftx-test", 1, genesisKey, newKey.PubKey().Bytes(), 10))
+	if res.Code != bft.CodeTypeBadNonce {
+		t.Errorf("Error on DeliverTx code of a replayed change, expected %d and got %d", bft.CodeTypeBadNonce, res.Code)
+	}
+	resQuery := app.Query(types.RequestQuery{Path: "/validators/sequence"})
+	if sequence, err := bft.NextSequence(resQuery); err != nil || sequence != 2 {
+		t.Errorf("Error on Query /validators/sequence, expected next sequence 2 and got %d", sequence)
+	}
+	if resQuery := app.Query(types.RequestQuery{Path: "/chain_id"}); string(resQuery.Value) != "bftx-test" {
+		t.Errorf("Error on Query /chain_id, expected bftx-test and got %s", resQuery.Value)
+	}
+}
+
+func TestQueryPaths(t *testing.T) {
+	t.Log("Test on Query function with the BF_TX and secondary index paths")
+	app := newApp(t)
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 1}})
+	app.DeliverTx(exampleTx(t, true, true))
+	app.Commit()
+
+	resQuery := app.Query(types.RequestQuery{Path: "/bftx/BFTXtest"})
+	if resQuery.Code != 0 || resQuery.Value == nil {
+		t.Errorf("Error on Query /bftx/BFTXtest: %+v", resQuery)
+	}
+
+	var ids []string
+	resQuery = app.Query(types.RequestQuery{Path: "/bol/15554", Prove: true})
+	if err := json.Unmarshal(resQuery.Value, &ids); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(ids) != 1 || ids[0] != "BFTXtest" {
+		t.Errorf("Error on Query /bol/15554, expected [BFTXtest] and got %v", ids)
+	}
+
+	resQuery = app.Query(types.RequestQuery{Path: "/shipper", Data: []byte("VLX454323F")})
+	if resQuery.Value == nil {
+		t.Error("Error on Query /shipper with the shipper in the query data")
+	}
+
+	resQuery = app.Query(types.RequestQuery{Path: "/unknown/BFTXtest"})
+	if resQuery.Code != bft.CodeTypeBadQuery {
+		t.Errorf("Error on Query code, expected %d and got %d", bft.CodeTypeBadQuery, resQuery.Code)
+	}
+}
+
+func TestQueryHeight(t *testing.T) {
+	t.Log("Test on Query function at past block heights and on version pruning")
+	app := newApp(t)
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 1}})
+	app.Commit()
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 2}})
+	app.DeliverTx(exampleTx(t, true, true))
+	app.Commit()
+
+	resQuery := app.Query(types.RequestQuery{Path: "/bftx/BFTXtest", Height: 1})
+	if resQuery.Code != 0 || resQuery.Value != nil || resQuery.Height != 1 {
+		t.Errorf("Error on Query at height 1, the BF_TX should not exist yet: %+v", resQuery)
+	}
+	resQuery = app.Query(types.RequestQuery{Path: "/bftx/BFTXtest", Height: 2})
+	if resQuery.Code != 0 || resQuery.Value == nil || resQuery.Height != 2 {
+		t.Errorf("Error on Query at height 2, the BF_TX should exist: %+v", resQuery)
+	}
+	resQuery = app.Query(types.RequestQuery{Path: "/bftx/BFTXtest", Height: 5})
+	if resQuery.Code != bft.CodeTypeUnknownHeight {
+		t.Errorf("Error on Query code at a future height, expected %d and got %d", bft.CodeTypeUnknownHeight, resQuery.Code)
+	}
+
+	// Every version out of the window is pruned, not only the one that has just fallen out of it
+	app.SetKeepRecent(1)
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 3}})
+	app.Commit()
+	for _, height := range []int64{1, 2} {
+		resQuery = app.Query(types.RequestQuery{Path: "/bftx/BFTXtest", Height: height})
+		if resQuery.Code != bft.CodeTypeUnknownHeight {
+			t.Errorf("Error on Query code at the pruned height %d, expected %d and got %d", height, bft.CodeTypeUnknownHeight, resQuery.Code)
+		}
+	}
+	resQuery = app.Query(types.RequestQuery{Path: "/bftx/BFTXtest", Height: 3})
+	if resQuery.Value == nil {
+		t.Error("Error on Query at the latest height after pruning")
+	}
+}
+
+func TestDeliverTxTags(t *testing.T) {
+	t.Log("Test on DeliverTx tags, encrypted fields are left out")
+	app := newApp(t)
+	app.SetEncryptedFields([]string{"PortOfLoading"})
+	resDeliver := app.DeliverTx(exampleTx(t, true, true))
+
+	tags := make(map[string]string)
+	for _, tag := range resDeliver.Tags {
+		tags[tag.Key] = tag.ValueString
+	}
+	if tags[bft.TagID] != "BFTXtest" {
+		t.Errorf("Error on tag %s, expected BFTXtest and got %q", bft.TagID, tags[bft.TagID])
+	}
+	if tags[bft.TagKey("Shipper")] != "VLX454323F" {
+		t.Errorf("Error on tag %s, expected VLX454323F and got %q", bft.TagKey("Shipper"), tags[bft.TagKey("Shipper")])
+	}
+	if tags[bft.TagKey("PortOfDischarge")] != "AUADL" {
+		t.Errorf("Error on tag %s, expected AUADL and got %q", bft.TagKey("PortOfDischarge"), tags[bft.TagKey("PortOfDischarge")])
+	}
+	if _, ok := tags[bft.TagKey("PortOfLoading")]; ok {
+		t.Error("Error on tags, the encrypted PortOfLoading field was emitted")
+	}
+}
+
+func TestDeliverTxContainerTags(t *testing.T) {
+	t.Log("Test on DeliverTx tags of a bill of lading of many containers")
+	app := newApp(t)
+	bftx := exampleBFTX(t, "BFTXcontainers", "", false, false)
+	bftx.Properties.Containers = []bf_tx.Container{
+		{Container: "CSQU3054383", GrossWeight: bf_tx.Quantity{Value: "15000"}},
+		{Container: "MSKU1234565", GrossWeight: bf_tx.Quantity{Value: "523"}},
+	}
+	bftx, err := crypto.SignBFTX(bftx, issuerKey)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	bftx.Lifecycle = bf_tx.StateSubmitted
+	tx, err := json.Marshal(bftx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	resDeliver := app.DeliverTx(tx)
+	if resDeliver.Code != 0 {
+		t.Fatalf("Error on DeliverTx, got code %d: %s", resDeliver.Code, resDeliver.Log)
+	}
+
+	var containers []string
+	for _, tag := range resDeliver.Tags {
+		if tag.Key == bft.TagKey("Container") {
+			containers = append(containers, tag.ValueString)
+		}
+	}
+	if !reflect.DeepEqual(containers, []string{"CSQU3054383", "MSKU1234565"}) {
+		t.Errorf("Error on container tags, got %v", containers)
+	}
+}
+
+func TestEncryptedFields(t *testing.T) {
+	t.Log("Test on DeliverTx of a BF_TX with encrypted fields")
+	app := newApp(t)
+	// The encryption config of the node does not decide which fields every node checks
+	app.SetEncryptedFields([]string{"GrossWeight"})
+	encryptedTx := func(id string, metadata string) []byte {
+		bftx := exampleBFTX(t, id, "", false, false)
+		bftx.Properties.GrossWeight = bf_tx.ParseQuantity("c2FiZXIgY2lwaGVydGV4dA==")
+		bftx.Properties.Shipper = bf_tx.TradeParty{Name: "c2hpcHBlciBjaXBoZXJ0ZXh0"}
+		bftx.Properties.EncryptionMetaData = metadata
+		bftx, err := crypto.SignBFTX(bftx, issuerKey)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		bftx.Lifecycle = bf_tx.StateSubmitted
+		tx, err := json.Marshal(bftx)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return tx
+	}
+
+	if resDeliver := app.DeliverTx(encryptedTx("BFTXplain", "")); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx of a ciphertext GrossWeight the BF_TX does not list, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	resDeliver := app.DeliverTx(encryptedTx("BFTXencrypted", bf_tx.EncryptionMetaData([]string{"GrossWeight", "Shipper"})))
+	if resDeliver.Code != 0 {
+		t.Fatalf("Error on DeliverTx of an encrypted GrossWeight, got code %d: %s", resDeliver.Code, resDeliver.Log)
+	}
+	for _, tag := range resDeliver.Tags {
+		if tag.Key == bft.TagKey("Shipper") {
+			t.Error("Error on tags, the encrypted Shipper field was emitted")
+		}
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	t.Log("Test on SearchQuery function")
+	query, err := bft.SearchQuery(map[string]string{"Vessel": "132153456", "Shipper": "VLX454323F", "Consignee": ""})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if query != "bftx.shipper='VLX454323F' AND bftx.vessel='132153456'" {
+		t.Errorf("Error on SearchQuery, got %s", query)
+	}
+
+	if _, err := bft.SearchQuery(map[string]string{}); err == nil {
+		t.Error("Error on SearchQuery, a search without filters should fail")
+	}
+	if _, err := bft.SearchQuery(map[string]string{"GrossWeight": "15523"}); err == nil {
+		t.Error("Error on SearchQuery, a search on an untagged field should fail")
+	}
+	if _, err := bft.SearchQuery(map[string]string{"Shipper": "x' OR bftx.id='y"}); err == nil {
+		t.Error("Error on SearchQuery, a quote in a value should fail")
+	}
+}
+
+func signedEnvelope(t *testing.T, app *bft.BftApplication, opType bf_tx.OpType, payload interface{}, signers ...tmcrypto.PrivKey) []byte {
+	env, err := bf_tx.NewEnvelope(opType, payload)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	env.ChainID = string(app.Query(types.RequestQuery{Path: "/chain_id"}).Value)
+	for _, signer := range signers {
+		env.Sequence, err = bft.NextSequence(app.Query(types.RequestQuery{Path: "/nonce", Data: signer.PubKey().Bytes()}))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		env = crypto.SignEnvelope(env, signer)
+	}
+	tx, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return tx
+}
+
+func TestOperations(t *testing.T) {
+	t.Log("Test on DeliverTx function with the envelope operations")
+	app := newApp(t)
+	signer := tmcrypto.GenPrivKeyEd25519().Wrap()
+	app.DeliverTx(exampleTx(t, true, true))
+
+	update := bf_tx.StatusUpdate{Id: "BFTXtest", Status: "departed"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpStatusUpdate, update)); resDeliver.Code != bft.CodeTypeUnsigned {
+		t.Errorf("Error on DeliverTx code of an unsigned status update, expected %d and got %d", bft.CodeTypeUnsigned, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpStatusUpdate, update, signer)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a status update signed by a stranger, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpStatusUpdate, update, issuerKey)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a status update: %s", resDeliver.Log)
+	}
+	update.Id = "BFTXunknown"
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpStatusUpdate, update, signer)); resDeliver.Code != bft.CodeTypeUnknownBFTX {
+		t.Errorf("Error on DeliverTx code of a status update of an unknown BF_TX, expected %d and got %d", bft.CodeTypeUnknownBFTX, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, "scuttle", update, signer)); resDeliver.Code != bft.CodeTypeUnknownOperation {
+		t.Errorf("Error on DeliverTx code of an unknown operation, expected %d and got %d", bft.CodeTypeUnknownOperation, resDeliver.Code)
+	}
+
+	var history []bft.HistoryEntry
+	resQuery := app.Query(types.RequestQuery{Path: "/history/BFTXtest"})
+	if err := json.Unmarshal(resQuery.Value, &history); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(history) != 2 || history[0].Type != bf_tx.OpIssue || history[1].Type != bf_tx.OpStatusUpdate {
+		t.Errorf("Error on BF_TX history, got %+v", history)
+	}
+
+	// A bare BF_TX is issued and held by the party of its signer
+	var title bft.Title
+	resQuery = app.Query(types.RequestQuery{Path: "/title/BFTXtest"})
+	if err := json.Unmarshal(resQuery.Value, &title); err != nil {
+		t.Fatal(err.Error())
+	}
+	if title.Holder.Party != "carrier0" {
+		t.Errorf("Error on the title of a bare BF_TX, expected holder carrier0 and got %+v", title.Holder)
+	}
+	var authority bft.Authority
+	resQuery = app.Query(types.RequestQuery{Data: []byte(bft.AuthorityPrefix + "BFTXtest")})
+	if err := json.Unmarshal(resQuery.Value, &authority); err != nil || authority.Party != "carrier0" {
+		t.Errorf("Error on the authority over a bare BF_TX, expected carrier0 and got %+v", authority)
+	}
+}
+
+func TestRegisterParty(t *testing.T) {
+	t.Log("Test on DeliverTx function with party registrations")
+	app := newApp(t)
+	signer := tmcrypto.GenPrivKeyEd25519().Wrap()
+	party := bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: "carrier", PubKeys: [][]byte{signer.PubKey().Bytes()}}
+
+	other := tmcrypto.GenPrivKeyEd25519().Wrap()
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, other)); resDeliver.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on DeliverTx code of a party signed with another key, expected %d and got %d", bft.CodeTypeUnauthorized, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, signer)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a party registration: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, signer)); resDeliver.Code != bft.CodeTypeDuplicateID {
+		t.Errorf("Error on DeliverTx code of a duplicate party, expected %d and got %d", bft.CodeTypeDuplicateID, resDeliver.Code)
+	}
+	if resQuery := app.Query(types.RequestQuery{Path: "/party/carrier1"}); resQuery.Value == nil {
+		t.Error("Error on Query of the registered party")
+	}
+}
+
+func TestApproveParty(t *testing.T) {
+	t.Log("Test on DeliverTx function approving a privileged role, and an agent acting for its principal")
+	app := newApp(t)
+	carrier := tmcrypto.GenPrivKeyEd25519().Wrap()
+	agent := tmcrypto.GenPrivKeyEd25519().Wrap()
+	stranger := tmcrypto.GenPrivKeyEd25519().Wrap()
+	party := bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: bf_tx.RoleCarrier, PubKeys: [][]byte{carrier.PubKey().Bytes()}, RoleApproved: true}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, carrier)); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx code of a party registered approved, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	party.RoleApproved = false
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, carrier))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "agent1", Name: "Agent One", Role: bf_tx.RoleAgent, PubKeys: [][]byte{agent.PubKey().Bytes()}, Principal: "carrier1"}, agent))
+
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), carrier)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of an issue by a carrier not approved, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	approval := bf_tx.PartyApproval{Party: "carrier1"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, approval, stranger)); resDeliver.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on DeliverTx code of an approval by a key that is not a validator, expected %d and got %d", bft.CodeTypeUnauthorized, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, approval, testValidator)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an approval by a validator: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, approval, testValidator)); resDeliver.Code != bft.CodeTypeDuplicateVote {
+		t.Errorf("Error on DeliverTx code of a second approval, expected %d and got %d", bft.CodeTypeDuplicateVote, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), carrier)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an issue by an approved carrier: %s", resDeliver.Log)
+	}
+
+	// The agent acts for the carrier it names once the carrier approved it
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXagent", "", true, true), agent)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of an issue by an agent its principal did not approve, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: "agent1"}, issuerKey)); resDeliver.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on DeliverTx code of an approval by another carrier than the principal, expected %d and got %d", bft.CodeTypeUnauthorized, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: "agent1"}, carrier)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an approval by the principal: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXagent", "", true, true), agent)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an issue by an agent for its principal: %s", resDeliver.Log)
+	}
+}
+
+func TestAmendmentLineage(t *testing.T) {
+	t.Log("Test on DeliverTx function with amendments, and on the lineage query")
+	app := newApp(t)
+	issuer := tmcrypto.GenPrivKeyEd25519().Wrap()
+	colleague := tmcrypto.GenPrivKeyEd25519().Wrap()
+	outsider := tmcrypto.GenPrivKeyEd25519().Wrap()
+	party := bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: "carrier", PubKeys: [][]byte{issuer.PubKey().Bytes(), colleague.PubKey().Bytes()}}
+	registerParty(t, app, party, issuer)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXoriginal", "", true, true), issuer))
+
+	amendment := exampleBFTX(t, "BFTXamendment1", "BFTXoriginal", true, true)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment)); resDeliver.Code != bft.CodeTypeUnsigned {
+		t.Errorf("Error on DeliverTx code of an unsigned amendment, expected %d and got %d", bft.CodeTypeUnsigned, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, outsider)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of an amendment by an outsider, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, issuer)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an amendment by the issuer: %s", resDeliver.Log)
+	}
+
+	amendment = exampleBFTX(t, "BFTXamendment2", "BFTXoriginal", true, true)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, issuer)); resDeliver.Code != bft.CodeTypeSuperseded {
+		t.Errorf("Error on DeliverTx code of an amendment of a superseded BF_TX, expected %d and got %d", bft.CodeTypeSuperseded, resDeliver.Code)
+	}
+	amendment = exampleBFTX(t, "BFTXamendment2", "BFTXamendment1", true, true)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, colleague)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an amendment by another key of the issuing party: %s", resDeliver.Log)
+	}
+
+	var lineage []string
+	resQuery := app.Query(types.RequestQuery{Path: "/lineage/BFTXamendment1"})
+	if err := json.Unmarshal(resQuery.Value, &lineage); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(lineage, []string{"BFTXoriginal", "BFTXamendment1", "BFTXamendment2"}) {
+		t.Errorf("Error on lineage query, got %v", lineage)
+	}
+}
+
+func TestTitleTransfer(t *testing.T) {
+	t.Log("Test on DeliverTx function with title transfers, and on the title query")
+	app := newApp(t)
+	carrier := tmcrypto.GenPrivKeyEd25519().Wrap()
+	bank := tmcrypto.GenPrivKeyEd25519().Wrap()
+	bearer := tmcrypto.GenPrivKeyEd25519().Wrap()
+	registerParty(t, app, bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: "carrier", PubKeys: [][]byte{carrier.PubKey().Bytes()}}, carrier)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "bank1", Name: "Bank One", Role: "bank", PubKeys: [][]byte{bank.PubKey().Bytes()}}, bank))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), carrier))
+
+	toBank := bf_tx.Transfer{Id: "BFTXbill", To: "bank1"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, toBank, bearer)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a transfer not signed by the holder, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", To: "nobody"}, carrier)); resDeliver.Code != bft.CodeTypeUnknownParty {
+		t.Errorf("Error on DeliverTx code of a transfer to an unknown party, expected %d and got %d", bft.CodeTypeUnknownParty, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, toBank, carrier)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a transfer by the holder: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, toBank, carrier)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a transfer by the former holder, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", Bearer: bearer.PubKey().Bytes()}, bank)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a transfer in blank: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", To: "carrier1"}, bearer)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a transfer by the bearer: %s", resDeliver.Log)
+	}
+
+	var title bft.Title
+	resQuery := app.Query(types.RequestQuery{Path: "/title/BFTXbill"})
+	if err := json.Unmarshal(resQuery.Value, &title); err != nil {
+		t.Fatal(err.Error())
+	}
+	if title.Holder.Party != "carrier1" || len(title.Endorsements) != 3 || title.Endorsements[1].To.Key == nil {
+		t.Errorf("Error on title query, got %+v", title)
+	}
+}
+
+func TestSurrenderRelease(t *testing.T) {
+	t.Log("Test on DeliverTx function with the surrender and release of a bill of lading")
+	app := newApp(t)
+	carrier := tmcrypto.GenPrivKeyEd25519().Wrap()
+	bank := tmcrypto.GenPrivKeyEd25519().Wrap()
+	registerParty(t, app, bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: "carrier", PubKeys: [][]byte{carrier.PubKey().Bytes()}}, carrier)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "bank1", Name: "Bank One", Role: "bank", PubKeys: [][]byte{bank.PubKey().Bytes()}}, bank))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), carrier))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", To: "bank1"}, carrier))
+
+	surrender := bf_tx.Surrender{Id: "BFTXbill"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpSurrender, surrender, carrier)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a surrender not signed by the holder, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpSurrender, bf_tx.Surrender{Id: "BFTXbill", To: "bank1"}, bank)); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx code of a surrender to a party that is not the DeliverAgent, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRelease, bf_tx.Release{Id: "BFTXbill"}, carrier)); resDeliver.Code != bft.CodeTypeIllegalState {
+		t.Errorf("Error on DeliverTx code of a release before surrender, expected %d and got %d", bft.CodeTypeIllegalState, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpSurrender, surrender, bank)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a surrender by the holder: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", To: "bank1"}, carrier)); resDeliver.Code != bft.CodeTypeSurrendered {
+		t.Errorf("Error on DeliverTx code of a transfer after surrender, expected %d and got %d", bft.CodeTypeSurrendered, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRelease, bf_tx.Release{Id: "BFTXbill"}, bank)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a release not signed by the carrier, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRelease, bf_tx.Release{Id: "BFTXbill"}, carrier)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a release by the carrier: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, exampleBFTX(t, "BFTXamended", "BFTXbill", true, true), carrier)); resDeliver.Code != bft.CodeTypeAccomplished {
+		t.Errorf("Error on DeliverTx code of an amendment after release, expected %d and got %d", bft.CodeTypeAccomplished, resDeliver.Code)
+	}
+
+	var title bft.Title
+	resQuery := app.Query(types.RequestQuery{Path: "/title/BFTXbill"})
+	if err := json.Unmarshal(resQuery.Value, &title); err != nil {
+		t.Fatal(err.Error())
+	}
+	if !title.Accomplished() || title.Surrender == nil || title.Holder.Party != "carrier1" {
+		t.Errorf("Error on title query, got %+v", title)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Log("Test on DeliverTx function moving BF_TX through their lifecycle")
+	app := newApp(t)
+	carrier := tmcrypto.GenPrivKeyEd25519().Wrap()
+	registerParty(t, app, bf_tx.Party{Id: "carrier1", Name: "Carrier One", Role: "carrier", PubKeys: [][]byte{carrier.PubKey().Bytes()}}, carrier)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), carrier))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, exampleBFTX(t, "BFTXamended", "BFTXbill", true, true), carrier))
+
+	for id, expected := range map[string]bf_tx.Lifecycle{"BFTXbill": bf_tx.StateAmended, "BFTXamended": bf_tx.StateCommitted} {
+		resQuery := app.Query(types.RequestQuery{Path: "/bftx/" + id})
+		if state := bf_tx.State(bf_tx.ByteArrayToBFTX(resQuery.Value)); state != expected {
+			t.Errorf("Error on state of %s, expected %s and got %s", id, expected, state)
+		}
+	}
+
+	void := bf_tx.Void{Id: "BFTXbill", Reason: "Issued twice"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVoid, void, tmcrypto.GenPrivKeyEd25519().Wrap())); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a void without authority, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVoid, void, carrier)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a void by the issuer: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", Bearer: carrier.PubKey().Bytes()}, carrier)); resDeliver.Code != bft.CodeTypeVoid {
+		t.Errorf("Error on DeliverTx code of a transfer of a void BF_TX, expected %d and got %d", bft.CodeTypeVoid, resDeliver.Code)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	t.Log("Test on DeliverTx function with party key rotations and revocations")
+	app := newApp(t)
+	newKey := tmcrypto.GenPrivKeyEd25519().Wrap()
+	stranger := tmcrypto.GenPrivKeyEd25519().Wrap()
+
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "pirate1", Name: "Pirate One", Role: "pirate", PubKeys: [][]byte{stranger.PubKey().Bytes()}}, stranger)); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx code of a party with an unknown role, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRevokeKey, bf_tx.KeyRevocation{Party: "carrier0", PubKey: issuerKey.PubKey().Bytes()}, issuerKey)); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx code of the revocation of the last key, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	rotation := bf_tx.KeyRotation{Party: "carrier0", PubKey: newKey.PubKey().Bytes(), Revoke: issuerKey.PubKey().Bytes()}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRotateKey, rotation, stranger)); resDeliver.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on DeliverTx code of a rotation not signed by the party, expected %d and got %d", bft.CodeTypeUnauthorized, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRotateKey, rotation, issuerKey)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a key rotation: %s", resDeliver.Log)
+	}
+
+	if res := app.CheckTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXtest", "", true, true))); res.Code != bft.CodeTypeRevokedKey {
+		t.Errorf("Error on CheckTx code of a BF_TX signed with a revoked key, expected %d and got %d", bft.CodeTypeRevokedKey, res.Code)
+	}
+	if res := app.CheckTx(signedEnvelope(t, app, bf_tx.OpRotateKey, bf_tx.KeyRotation{Party: "carrier0", PubKey: stranger.PubKey().Bytes()}, issuerKey)); res.Code != bft.CodeTypeRevokedKey {
+		t.Errorf("Error on CheckTx code of a transaction signed with a revoked key, expected %d and got %d", bft.CodeTypeRevokedKey, res.Code)
+	}
+
+	bftx := exampleBFTX(t, "BFTXtest", "", false, false)
+	bftx, err := crypto.SignBFTX(bftx, stranger)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	bftx, _ = bf_tx.Transition(bftx, bf_tx.StateSubmitted)
+	if res := app.CheckTx(signedEnvelope(t, app, bf_tx.OpIssue, bftx)); res.Code != bft.CodeTypeUnauthorized {
+		t.Errorf("Error on CheckTx code of a BF_TX signed with an unregistered key, expected %d and got %d", bft.CodeTypeUnauthorized, res.Code)
+	}
+	bftx, _ = crypto.SignBFTX(exampleBFTX(t, "BFTXtest", "", false, false), newKey)
+	bftx, _ = bf_tx.Transition(bftx, bf_tx.StateSubmitted)
+	if res := app.CheckTx(signedEnvelope(t, app, bf_tx.OpIssue, bftx)); res.Code != 0 {
+		t.Errorf("Error on CheckTx of a BF_TX signed with the new key: %s", res.Log)
+	}
+}
+
+func TestRoleRules(t *testing.T) {
+	t.Log("Test on DeliverTx function enforcing the role rules")
+	app := newApp(t)
+	shipper := tmcrypto.GenPrivKeyEd25519().Wrap()
+	agent := tmcrypto.GenPrivKeyEd25519().Wrap()
+	impostor := tmcrypto.GenPrivKeyEd25519().Wrap()
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "shipper1", Name: "Shipper One", Role: bf_tx.RoleShipper, PubKeys: [][]byte{shipper.PubKey().Bytes()}}, shipper))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "VLX454323F", Name: "Shipper Name", Role: bf_tx.RoleShipper, PubKeys: [][]byte{impostor.PubKey().Bytes()}}, impostor))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, bf_tx.Party{Id: "agent1", Name: "Agent One", Role: bf_tx.RoleAgent, PubKeys: [][]byte{agent.PubKey().Bytes()}, Principal: "carrier0"}, agent))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: "agent1"}, issuerKey))
+
+	// The agent is named to co-sign the bill, which stays Pending
+	bill := exampleBFTX(t, "BFTXbill", "", false, false)
+	bill.Properties.Shipper.Party = "shipper1"
+	bill.Properties.AgentForMaster.Party = "agent1"
+	bill, _ = crypto.SignBFTX(bill, issuerKey)
+	bill, _ = bf_tx.Transition(bill, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, bill, shipper)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of an issue by a shipper, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, bill, agent)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an issue by an agent for the master: %s", resDeliver.Log)
+	}
+
+	amendment := exampleBFTX(t, "BFTXamended", "BFTXbill", false, false)
+	amendment.Properties.Shipper.Party = "shipper1"
+	amendment.Properties.AgentForMaster.Party = "agent1"
+	amendment.Properties.DescOfGoods = "Frozen fish"
+	amendment.Properties.Vessel = "Vessel Two"
+	amendment, _ = crypto.SignBFTX(amendment, issuerKey)
+	amendment, _ = bf_tx.Transition(amendment, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, shipper)); resDeliver.Code != bft.CodeTypeFieldNotAllowed {
+		t.Errorf("Error on DeliverTx code of a change of the vessel by the shipper, expected %d and got %d", bft.CodeTypeFieldNotAllowed, resDeliver.Code)
+	}
+	amendment = exampleBFTX(t, "BFTXamended", "BFTXbill", false, false)
+	amendment.Properties.Shipper.Party = "shipper1"
+	amendment.Properties.AgentForMaster.Party = "agent1"
+	amendment.Properties.DescOfGoods = "Frozen fish"
+	amendment, _ = crypto.SignBFTX(amendment, issuerKey)
+	amendment, _ = bf_tx.Transition(amendment, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, agent)); resDeliver.Code != bft.CodeTypeFieldNotAllowed {
+		t.Errorf("Error on DeliverTx code of a change of the goods by the issuer, expected %d and got %d", bft.CodeTypeFieldNotAllowed, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, shipper)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a change of the goods by the shipper: %s", resDeliver.Log)
+	}
+
+	// The shipper no longer amends a committed bill
+	committed := exampleBFTX(t, "BFTXcommitted", "", false, false)
+	committed.Properties.Shipper.Party = "shipper1"
+	committed, _ = crypto.SignBFTX(committed, issuerKey)
+	committed, _ = bf_tx.Transition(committed, bf_tx.StateSubmitted)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, committed, agent))
+	amendment = exampleBFTX(t, "BFTXcommittedAmended", "BFTXcommitted", false, false)
+	amendment.Properties.Shipper.Party = "shipper1"
+	amendment.Properties.DescOfGoods = "Frozen fish"
+	amendment, _ = crypto.SignBFTX(amendment, issuerKey)
+	amendment, _ = bf_tx.Transition(amendment, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, shipper)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a change of the goods of a committed bill by the shipper, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+
+	// A shipper named only by its Name is not a registered party, whatever party has that Id
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXnamed", "", true, true), agent))
+	amendment = exampleBFTX(t, "BFTXnamedAmended", "BFTXnamed", false, false)
+	amendment.Properties.DescOfGoods = "Frozen fish"
+	amendment, _ = crypto.SignBFTX(amendment, issuerKey)
+	amendment, _ = bf_tx.Transition(amendment, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, impostor)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a change of the goods by a party registered under the shipper name, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+
+	app = newApp(t)
+	genesisParams := params.Default()
+	genesisParams.Rules = authz.DefaultRules()
+	genesisParams.Rules[bf_tx.OpIssue] = authz.Rule{Allow: []authz.Grant{{Role: bf_tx.RoleShipper}}}
+	app.SetParams(genesisParams)
+	app.InitChain(types.RequestInitChain{})
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), issuerKey)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of an issue by a carrier with genesis rules for shippers, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Log("Test on CheckTx and DeliverTx functions with replayed and out of order sequences")
+	app := newApp(t)
+	tx := signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), issuerKey)
+	if resDeliver := app.DeliverTx(tx); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an issue: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(tx); resDeliver.Code != bft.CodeTypeBadNonce {
+		t.Errorf("Error on DeliverTx code of a replayed issue, expected %d and got %d", bft.CodeTypeBadNonce, resDeliver.Code)
+	}
+	app.Commit()
+
+	// Both updates are admitted to the mempool before the next block
+	next, _ := bft.NextSequence(app.Query(types.RequestQuery{Path: "/nonce", Data: issuerKey.PubKey().Bytes()}))
+	updates := make([][]byte, 2)
+	for i := range updates {
+		env, _ := bf_tx.NewEnvelope(bf_tx.OpStatusUpdate, bf_tx.StatusUpdate{Id: "BFTXbill", Status: "departed"})
+		env.Sequence = next + uint64(i)
+		updates[i], _ = json.Marshal(crypto.SignEnvelope(env, issuerKey))
+	}
+	if res := app.CheckTx(updates[1]); res.Code != bft.CodeTypeBadNonce {
+		t.Errorf("Error on CheckTx code of a sequence ahead, expected %d and got %d", bft.CodeTypeBadNonce, res.Code)
+	}
+	for _, update := range updates {
+		if res := app.CheckTx(update); res.Code != 0 {
+			t.Errorf("Error on CheckTx of consecutive sequences: %s", res.Log)
+		}
+	}
+	if res := app.CheckTx(updates[0]); res.Code != bft.CodeTypeBadNonce {
+		t.Errorf("Error on CheckTx code of a sequence already in the mempool, expected %d and got %d", bft.CodeTypeBadNonce, res.Code)
+	}
+
+	// An envelope signed for another chain is not replayed on this one
+	env, _ := bf_tx.NewEnvelope(bf_tx.OpStatusUpdate, bf_tx.StatusUpdate{Id: "BFTXbill", Status: "arrived"})
+	env.ChainID = "bftx-other"
+	env.Sequence = next + 2
+	other, _ := json.Marshal(crypto.SignEnvelope(env, issuerKey))
+	if res := app.CheckTx(other); res.Code != bft.CodeTypeWrongChain {
+		t.Errorf("Error on CheckTx code of an envelope signed for another chain, expected %d and got %d", bft.CodeTypeWrongChain, res.Code)
+	}
+}
+
+func TestCosignatures(t *testing.T) {
+	t.Log("Test on DeliverTx function collecting the co-signatures of the parties named in a BF_TX")
+	app := newApp(t)
+	master := tmcrypto.GenPrivKeyEd25519().Wrap()
+	agent := tmcrypto.GenPrivKeyEd25519().Wrap()
+	registerParty(t, app, bf_tx.Party{Id: "master1", Name: "Master One", Role: bf_tx.RoleCarrier, PubKeys: [][]byte{master.PubKey().Bytes()}}, master)
+	registerParty(t, app, bf_tx.Party{Id: "agent1", Name: "Agent One", Role: bf_tx.RoleAgent, PubKeys: [][]byte{agent.PubKey().Bytes()}, Principal: "carrier0"}, agent)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpApproveParty, bf_tx.PartyApproval{Party: "agent1"}, issuerKey))
+
+	named := func(id string, agentForOwner string) bf_tx.BF_TX {
+		bftx := exampleBFTX(t, id, "", false, false)
+		bftx.Properties.MasterInfo.Party = "master1"
+		bftx.Properties.AgentForMaster.Party = "agent1"
+		bftx.Properties.AgentForOwner.Party = agentForOwner
+		bftx, _ = crypto.SignBFTX(bftx, issuerKey)
+		bftx, _ = bf_tx.Transition(bftx, bf_tx.StateSubmitted)
+		return bftx
+	}
+	cosign := func(bftx bf_tx.BF_TX, signer tmcrypto.PrivKey) []byte {
+		signature, err := crypto.CosignBFTX(bftx, signer)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return signedEnvelope(t, app, bf_tx.OpCosign, bf_tx.Cosign{Id: bftx.Id, Signature: signature}, signer)
+	}
+	state := func(id string) bf_tx.Lifecycle {
+		return bf_tx.State(bf_tx.ByteArrayToBFTX(app.Query(types.RequestQuery{Path: "/bftx/" + id}).Value))
+	}
+
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, named("BFTXbill", "ghost1"), issuerKey)); resDeliver.Code != bft.CodeTypeUnknownParty {
+		t.Errorf("Error on DeliverTx code of a BF_TX naming an unregistered party, expected %d and got %d", bft.CodeTypeUnknownParty, resDeliver.Code)
+	}
+	bftx := named("BFTXbill", "")
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, bftx, issuerKey)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a BF_TX naming parties: %s", resDeliver.Log)
+	}
+	if state := state("BFTXbill"); state != bf_tx.StatePending {
+		t.Errorf("Error on state of a BF_TX waiting for co-signatures, expected %s and got %s", bf_tx.StatePending, state)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", Bearer: master.PubKey().Bytes()}, issuerKey)); resDeliver.Code != bft.CodeTypeIllegalState {
+		t.Errorf("Error on DeliverTx code of a transfer of a pending BF_TX, expected %d and got %d", bft.CodeTypeIllegalState, resDeliver.Code)
+	}
+
+	if resDeliver := app.DeliverTx(cosign(bftx, issuerKey)); resDeliver.Code != bft.CodeTypeNotAllowed {
+		t.Errorf("Error on DeliverTx code of a co-signature by a party not named, expected %d and got %d", bft.CodeTypeNotAllowed, resDeliver.Code)
+	}
+	other := named("BFTXother", "")
+	forged, _ := crypto.CosignBFTX(other, master)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpCosign, bf_tx.Cosign{Id: "BFTXbill", Signature: forged}, master)); resDeliver.Code != bft.CodeTypeBadSignature {
+		t.Errorf("Error on DeliverTx code of a co-signature of another document, expected %d and got %d", bft.CodeTypeBadSignature, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(cosign(bftx, master)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a co-signature by the master: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(cosign(bftx, master)); resDeliver.Code != bft.CodeTypeDuplicateCosign {
+		t.Errorf("Error on DeliverTx code of a second co-signature by the master, expected %d and got %d", bft.CodeTypeDuplicateCosign, resDeliver.Code)
+	}
+	if state := state("BFTXbill"); state != bf_tx.StatePending {
+		t.Errorf("Error on state of a BF_TX short of its quorum, expected %s and got %s", bf_tx.StatePending, state)
+	}
+	if resDeliver := app.DeliverTx(cosign(bftx, agent)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a co-signature by the agent: %s", resDeliver.Log)
+	}
+	if state := state("BFTXbill"); state != bf_tx.StateCommitted {
+		t.Errorf("Error on state of a BF_TX co-signed by every named party, expected %s and got %s", bf_tx.StateCommitted, state)
+	}
+	var cosignatures []bft.Cosignature
+	json.Unmarshal(app.Query(types.RequestQuery{Path: "/cosign/BFTXbill"}).Value, &cosignatures)
+	if len(cosignatures) != 2 {
+		t.Errorf("Error on co-signatures of BFTXbill, expected 2 and got %d", len(cosignatures))
+	}
+
+	// An amendment waits for the co-signatures of its named parties again
+	amendment := exampleBFTX(t, "BFTXamended", "BFTXbill", false, false)
+	amendment.Properties.MasterInfo.Party = "master1"
+	amendment.Properties.AgentForMaster.Party = "agent1"
+	amendment.Properties.Vessel = "Vessel Two"
+	amendment, _ = crypto.SignBFTX(amendment, issuerKey)
+	amendment, _ = bf_tx.Transition(amendment, bf_tx.StateSubmitted)
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, amendment, issuerKey)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an amendment naming parties: %s", resDeliver.Log)
+	}
+	if state := state("BFTXamended"); state != bf_tx.StatePending {
+		t.Errorf("Error on state of an amendment waiting for co-signatures, expected %s and got %s", bf_tx.StatePending, state)
+	}
+	app.DeliverTx(cosign(amendment, master))
+	app.DeliverTx(cosign(amendment, agent))
+	if state := state("BFTXamended"); state != bf_tx.StateCommitted {
+		t.Errorf("Error on state of an amendment co-signed by every named party, expected %s and got %s", bf_tx.StateCommitted, state)
+	}
+
+	genesisParams := params.Default()
+	genesisParams.Quorum = 1
+	app.SetParams(genesisParams)
+	app.InitChain(types.RequestInitChain{})
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, other, issuerKey))
+	app.DeliverTx(cosign(other, agent))
+	if state := state("BFTXother"); state != bf_tx.StateCommitted {
+		t.Errorf("Error on state of a BF_TX co-signed by a genesis quorum of 1, expected %s and got %s", bf_tx.StateCommitted, state)
+	}
+}
+
+func TestGenesis(t *testing.T) {
+	t.Log("Test on InitChain function loading a genesis state exported from another application")
+	app := newApp(t)
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), issuerKey))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpAmend, exampleBFTX(t, "BFTXamended", "BFTXbill", true, true), issuerKey))
+	bearer := tmcrypto.GenPrivKeyEd25519().Wrap()
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpTransfer, bf_tx.Transfer{Id: "BFTXbill", Bearer: bearer.PubKey().Bytes()}, issuerKey))
+	app.Commit()
+
+	var genesis bft.GenesisDoc
+	if err := json.Unmarshal(app.Query(types.RequestQuery{Path: "/genesis"}).Value, &genesis); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(genesis.AppState.Parties) != 1 || len(genesis.AppState.BFTX) != 2 {
+		t.Errorf("Error on exported genesis, expected 1 party and 2 BF_TX and got %d and %d", len(genesis.AppState.Parties), len(genesis.AppState.BFTX))
+	}
+
+	imported := bft.NewBftApplication()
+	imported.SetGenesisState(genesis.AppState)
+	imported.InitChain(types.RequestInitChain{})
+	for _, path := range []string{"/bftx/BFTXbill", "/bftx/BFTXamended", "/party/carrier0", "/title/BFTXbill", "/lineage/BFTXbill", "/history/BFTXbill", "/quota/carrier0"} {
+		expected, got := app.Query(types.RequestQuery{Path: path}).Value, imported.Query(types.RequestQuery{Path: path}).Value
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("Error on query %s of the imported genesis, expected %s and got %s", path, expected, got)
+		}
+	}
+	for _, query := range []types.RequestQuery{{Data: []byte(bft.AuthorityPrefix + "BFTXbill")}, {Path: "/nonce", Data: issuerKey.PubKey().Bytes()}} {
+		expected, got := app.Query(query).Value, imported.Query(query).Value
+		if len(expected) == 0 || !reflect.DeepEqual(expected, got) {
+			t.Errorf("Error on query %s %q of the imported genesis, expected %s and got %s", query.Path, query.Data, expected, got)
+		}
+	}
+	if resDeliver := imported.DeliverTx(signedEnvelope(t, imported, bf_tx.OpStatusUpdate, bf_tx.StatusUpdate{Id: "BFTXamended", Status: "departed"}, issuerKey)); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of an operation on an imported BF_TX: %s", resDeliver.Log)
+	}
+
+	// Historical records are only imported signed with the key of a genesis party
+	strangerBFTX, err := crypto.SignBFTX(exampleBFTX(t, "BFTXamended", "BFTXbill", false, false), tmcrypto.GenPrivKeyEd25519().Wrap())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for name, invalid := range map[string][]bf_tx.BF_TX{
+		"a BF_TX amending a missing BF_TX":    {exampleBFTX(t, "BFTXamended", "BFTXbill", true, true)},
+		"an original without title or issuer": {exampleBFTX(t, "BFTXbill", "", true, true)},
+		"a cycle of amendments":               {exampleBFTX(t, "BFTXbill", "BFTXamended", true, true), exampleBFTX(t, "BFTXamended", "BFTXbill", true, true)},
+		"a BF_TX amended twice":               {exampleBFTX(t, "BFTXbill", "", true, true), exampleBFTX(t, "BFTXamended", "BFTXbill", true, true), exampleBFTX(t, "BFTXother", "BFTXbill", true, true)},
+		"an unsigned BF_TX":                   {exampleBFTX(t, "BFTXamended", "BFTXbill", false, false)},
+		"a BF_TX signed by a stranger":        {strangerBFTX},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Error on InitChain of a genesis with %s, expected a panic", name)
+				}
+			}()
+			invalidApp := bft.NewBftApplication()
+			invalidApp.SetGenesisState(bft.GenesisState{Params: params.Default(), Parties: genesis.AppState.Parties, BFTX: invalid})
+			invalidApp.InitChain(types.RequestInitChain{})
+		}()
+	}
+}
+
+func TestGovernance(t *testing.T) {
+	t.Log("Test on DeliverTx function changing the parameters by validator vote")
+	app := newApp(t)
+	validators := []tmcrypto.PrivKey{tmcrypto.GenPrivKeyEd25519().Wrap(), tmcrypto.GenPrivKeyEd25519().Wrap(), tmcrypto.GenPrivKeyEd25519().Wrap()}
+	genesis := types.RequestInitChain{}
+	for _, v := range validators {
+		genesis.Validators = append(genesis.Validators, &types.Validator{PubKey: v.PubKey().Bytes(), Power: 10})
+	}
+	app.InitChain(genesis)
+
+	proposal := bf_tx.ParamProposal{Changes: map[string]json.RawMessage{"Incoterms": json.RawMessage(`["FOB"]`)}}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpProposeParams, proposal, issuerKey)); resDeliver.Code != bft.CodeTypeNotValidator {
+		t.Errorf("Error on DeliverTx code of a proposal by a party, expected %d and got %d", bft.CodeTypeNotValidator, resDeliver.Code)
+	}
+	invalid := bf_tx.ParamProposal{Changes: map[string]json.RawMessage{"VoteThreshold": json.RawMessage(`0`)}}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpProposeParams, invalid, validators[0])); resDeliver.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on DeliverTx code of an invalid proposal, expected %d and got %d", bft.CodeTypeValidationError, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpProposeParams, proposal, validators[0])); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a proposal by a validator: %s", resDeliver.Log)
+	}
+
+	vote := bf_tx.Vote{Proposal: "1"}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, vote, validators[0])); resDeliver.Code != bft.CodeTypeDuplicateVote {
+		t.Errorf("Error on DeliverTx code of a second vote of the proposer, expected %d and got %d", bft.CodeTypeDuplicateVote, resDeliver.Code)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, vote, validators[1])); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a vote: %s", resDeliver.Log)
+	}
+	if incoterms := app.Params().Incoterms; incoterms != nil {
+		t.Errorf("Error on Incoterms of a proposal short of the threshold, expected none and got %v", incoterms)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, vote, validators[2])); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a vote: %s", resDeliver.Log)
+	}
+	if incoterms := app.Params().Incoterms; !reflect.DeepEqual(incoterms, []string{"FOB"}) {
+		t.Errorf("Error on Incoterms of a passed proposal, expected [FOB] and got %v", incoterms)
+	}
+	if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, bf_tx.Vote{Proposal: "1"}, validators[2])); resDeliver.Code != bft.CodeTypeProposalClosed {
+		t.Errorf("Error on DeliverTx code of a vote for a passed proposal, expected %d and got %d", bft.CodeTypeProposalClosed, resDeliver.Code)
+	}
+
+	// The live parameters apply to the next transactions
+	if res := app.CheckTx(signedEnvelope(t, app, bf_tx.OpIssue, exampleBFTX(t, "BFTXbill", "", true, true), issuerKey)); res.Code != bft.CodeTypeValidationError {
+		t.Errorf("Error on CheckTx code of a BF_TX with INCOTerms that are not allowed, expected %d and got %d", bft.CodeTypeValidationError, res.Code)
+	}
+	shrink := bf_tx.ParamProposal{Changes: map[string]json.RawMessage{"MaxTxBytes": json.RawMessage(`256`)}}
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpProposeParams, shrink, validators[0]))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, bf_tx.Vote{Proposal: "2"}, validators[1]))
+	app.DeliverTx(signedEnvelope(t, app, bf_tx.OpVote, bf_tx.Vote{Proposal: "2"}, validators[2]))
+	if res := app.CheckTx(exampleTx(t, true, true)); res.Code != bft.CodeTypeTxTooLarge {
+		t.Errorf("Error on CheckTx code of a transaction over MaxTxBytes, expected %d and got %d", bft.CodeTypeTxTooLarge, res.Code)
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	t.Log("Test on CheckTx and DeliverTx functions with the account quotas")
+	app := bft.NewBftApplication()
+	quotas := params.Default()
+	quotas.QuotaWindow = 10
+	quotas.QuotaTxs = 3
+	quotas.QuotaUnregisteredTxs = 2
+	quotas.QuotaRegistrationTxs = 1
+	app.SetParams(quotas)
+	app.InitChain(types.RequestInitChain{Validators: []*types.Validator{{PubKey: testValidator.PubKey().Bytes(), Power: 10}}})
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 1}})
+
+	// The registration counts against the key, the next transactions against carrier0
+	registerParty(t, app, bf_tx.Party{Id: "carrier0", Name: "Carrier Zero", Role: bf_tx.RoleCarrier, PubKeys: [][]byte{issuerKey.PubKey().Bytes()}}, issuerKey)
+	for i, tx := range [][]byte{
+		exampleTx(t, true, true),
+		signedEnvelope(t, app, bf_tx.OpStatusUpdate, bf_tx.StatusUpdate{Id: "BFTXtest", Status: "departed"}, issuerKey),
+	} {
+		if resDeliver := app.DeliverTx(tx); resDeliver.Code != 0 {
+			t.Errorf("Error on DeliverTx of transaction %d within the quota: %s", i, resDeliver.Log)
+		}
+	}
+	app.Commit()
+
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 2}})
+	update := bf_tx.StatusUpdate{Id: "BFTXtest", Status: "arrived"}
+	first := signedEnvelope(t, app, bf_tx.OpStatusUpdate, update, issuerKey)
+	if res := app.CheckTx(first); res.Code != 0 {
+		t.Errorf("Error on CheckTx of the last transaction of the quota: %s", res.Log)
+	}
+	env, _ := bf_tx.DecodeEnvelope(first)
+	env.Sequence++
+	second, _ := json.Marshal(crypto.SignEnvelope(env, issuerKey))
+	if res := app.CheckTx(second); res.Code != bft.CodeTypeQuotaExceeded {
+		t.Errorf("Error on CheckTx code of a transaction over the quota, expected %d and got %d", bft.CodeTypeQuotaExceeded, res.Code)
+	}
+	if resDeliver := app.DeliverTx(first); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of the last transaction of the quota: %s", resDeliver.Log)
+	}
+	if resDeliver := app.DeliverTx(second); resDeliver.Code != bft.CodeTypeQuotaExceeded {
+		t.Errorf("Error on DeliverTx code of a transaction over the quota, expected %d and got %d", bft.CodeTypeQuotaExceeded, resDeliver.Code)
+	}
+	app.Commit()
+
+	// A new window starts every QuotaWindow blocks
+	app.BeginBlock(types.RequestBeginBlock{Header: &types.Header{Height: 11}})
+	if resDeliver := app.DeliverTx(second); resDeliver.Code != 0 {
+		t.Errorf("Error on DeliverTx of a transaction in a new window: %s", resDeliver.Log)
+	}
+	resQuery := app.Query(types.RequestQuery{Path: "/quota/carrier0"})
+	var usage bft.Usage
+	if err := json.Unmarshal(resQuery.Value, &usage); err != nil {
+		t.Fatal(err.Error())
+	}
+	if usage.Window != 11 || usage.Txs != 1 || usage.Bytes != len(second) {
+		t.Errorf("Error on the usage of carrier0, expected 1 transaction of %d bytes from height 11 and got %+v", len(second), usage)
+	}
+
+	// The registrations of fresh keys are not held back by one another, each key has a quota of its own
+	for i := 0; i < 3; i++ {
+		key := tmcrypto.GenPrivKeyEd25519().Wrap()
+		party := bf_tx.Party{Id: "shipper" + strconv.Itoa(i), Name: "Shipper", Role: bf_tx.RoleShipper, PubKeys: [][]byte{key.PubKey().Bytes()}}
+		if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpRegisterParty, party, key)); resDeliver.Code != 0 {
+			t.Errorf("Error on DeliverTx of the registration of fresh key %d: %s", i, resDeliver.Log)
+		}
+	}
+	key := tmcrypto.GenPrivKeyEd25519().Wrap()
+	party := bf_tx.Party{Id: "shipper3", Name: "Shipper", Role: bf_tx.RoleShipper, PubKeys: [][]byte{key.PubKey().Bytes()}}
+	first = signedEnvelope(t, app, bf_tx.OpRegisterParty, party, key)
+	if res := app.CheckTx(first); res.Code != 0 {
+		t.Errorf("Error on CheckTx of a registration: %s", res.Log)
+	}
+	env, _ = bf_tx.DecodeEnvelope(first)
+	env.Sequence++
+	second, _ = json.Marshal(crypto.SignEnvelope(env, key))
+	if res := app.CheckTx(second); res.Code != bft.CodeTypeQuotaExceeded {
+		t.Errorf("Error on CheckTx code of a registration over the quota of its key, expected %d and got %d", bft.CodeTypeQuotaExceeded, res.Code)
+	}
+
+	// The validators govern the quotas, their proposals count against none
+	for i := 0; i < 3; i++ {
+		proposal := bf_tx.ParamProposal{Changes: map[string]json.RawMessage{"QuotaTxs": json.RawMessage(strconv.Itoa(4 + i))}}
+		if resDeliver := app.DeliverTx(signedEnvelope(t, app, bf_tx.OpProposeParams, proposal, testValidator)); resDeliver.Code != 0 {
+			t.Errorf("Error on DeliverTx of proposal %d of a validator: %s", i, resDeliver.Log)
+		}
+	}
+}
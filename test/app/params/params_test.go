@@ -0,0 +1,74 @@
+package params
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
+	"github.com/blockfreight/go-bftx/lib/app/params"
+)
+
+func TestDefault(t *testing.T) {
+	t.Log("Test on Validate function with the default parameters")
+	if err := params.Default().Validate(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var decoded params.Params
+	if err := json.Unmarshal([]byte(`{"MaxTxBytes": 512}`), &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := params.Default()
+	expected.MaxTxBytes = 512
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("Error on decoding parameters, expected the default values besides MaxTxBytes and got %+v", decoded)
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Log("Test on Apply function with valid and invalid changes")
+	changed, err := params.Default().Apply(map[string]json.RawMessage{"Incoterms": json.RawMessage(`["FOB", "CIF"]`)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !reflect.DeepEqual(changed.Incoterms, []string{"FOB", "CIF"}) || changed.VoteThreshold != params.Default().VoteThreshold {
+		t.Errorf("Error on Apply, expected only Incoterms to change and got %+v", changed)
+	}
+
+	for name, value := range map[string]string{
+		"Unknown":        `1`,
+		"VoteThreshold":  `101`,
+		"MaxTxBytes":     `"large"`,
+		"RequiredFields": `{"*": ["Unknown"]}`,
+		"Rules":          `{}`,
+	} {
+		if _, err := params.Default().Apply(map[string]json.RawMessage{name: json.RawMessage(value)}); err == nil {
+			t.Errorf("Error on Apply, %s = %s should not validate", name, value)
+		}
+	}
+}
+
+func TestCheckBFTX(t *testing.T) {
+	t.Log("Test on CheckBFTX function with required fields and INCOTerms")
+	checked := params.Default()
+	checked.RequiredFields = map[string][]string{params.AnyMode: {"Vessel"}, "FCL": {"ContainerSeal"}}
+	checked.Incoterms = []string{"FOB"}
+
+	bftx := bf_tx.BF_TX{Id: "BFTXtest"}
+	bftx.Properties.Vessel = "Vessel One"
+	bftx.Properties.ContainerMode = "LCL"
+	bftx.Properties.INCOTerms = "FOB"
+	if err := checked.CheckBFTX(bftx); err != nil {
+		t.Errorf("Error on CheckBFTX of a BF_TX with its required fields: %s", err.Error())
+	}
+	bftx.Properties.ContainerMode = "FCL"
+	if err := checked.CheckBFTX(bftx); err == nil {
+		t.Error("Error on CheckBFTX, a FCL BF_TX without ContainerSeal should not pass")
+	}
+	bftx.Properties.ContainerSeal = "SEAL1"
+	bftx.Properties.INCOTerms = "EXW"
+	if err := checked.CheckBFTX(bftx); err == nil {
+		t.Error("Error on CheckBFTX, a BF_TX with INCOTerms that are not allowed should not pass")
+	}
+}
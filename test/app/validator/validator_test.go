@@ -23,3 +23,145 @@ func TestValidator(t *testing.T) {
 		t.Error(result)
 	}
 }
+
+func TestValidateTypedFields(t *testing.T) {
+	t.Log("Test on ValidateFields function with typed quantities, amounts and dates")
+	bftx, err := bf_tx.SetBFTX("../../../examples/bf_tx_example.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	bftx.Properties.GrossWeight = bf_tx.Quantity{Value: "15523.5", Unit: "KGM"}
+	bftx.Properties.FreightPayableAmt = bf_tx.Money{Amount: "354534.00", Currency: "USD"}
+	bftx.Properties.DateShipped = "2016-11-28"
+	if valid, msg := validator.ValidateFields(bftx); !valid {
+		t.Errorf("Error on ValidateFields of typed values: %s", msg)
+	}
+
+	invalid := map[string]func(*bf_tx.Properties){
+		"GrossWeight":       func(p *bf_tx.Properties) { p.GrossWeight = bf_tx.Quantity{Value: "heavy"} },
+		"UnitOfWeight":      func(p *bf_tx.Properties) { p.GrossWeight.Unit = "KGM"; p.UnitOfWeight = "LBR" },
+		"Volume":            func(p *bf_tx.Properties) { p.Volume = bf_tx.Quantity{Value: "-1", Unit: "MTQ"} },
+		"FreightPayableAmt": func(p *bf_tx.Properties) { p.FreightPayableAmt = bf_tx.Money{Amount: "10", Currency: "dollars"} },
+		"Packages":          func(p *bf_tx.Properties) { p.Packages = "-1" },
+		"DateShipped":       func(p *bf_tx.Properties) { p.DateShipped = "28/11/2016" },
+	}
+	for field, change := range invalid {
+		changed := bftx
+		change(&changed.Properties)
+		if valid, _ := validator.ValidateFields(changed); valid {
+			t.Errorf("Error on ValidateFields, an invalid %s should not validate", field)
+		}
+	}
+
+	// The typed values of encrypted fields are ciphertext
+	bftx.Properties.GrossWeight = bf_tx.ParseQuantity("c2FiZXIgY2lwaGVydGV4dA==")
+	if valid, msg := validator.ValidateFieldsExcept(bftx, map[string]bool{"grossweight": true}); !valid {
+		t.Errorf("Error on ValidateFieldsExcept of an encrypted GrossWeight: %s", msg)
+	}
+}
+
+func TestValidateContainers(t *testing.T) {
+	t.Log("Test on ValidateFields function with the containers of a bill of lading")
+	example := func() bf_tx.BF_TX {
+		bftx, err := bf_tx.SetBFTX("../../../examples/bf_tx_example.json")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		bftx.Properties.UnitOfWeight = "KGM"
+		bftx.Properties.Packages = "30"
+		bftx.Properties.GrossWeight = bf_tx.Quantity{Value: "15523.5", Unit: "KGM"}
+		bftx.Properties.Container = "CSQU3054383"
+		bftx.Properties.Containers = []bf_tx.Container{
+			{Container: "CSQU3054383", ContainerSeal: "SL0001", ContainerType: "45G1", Items: []bf_tx.CargoItem{
+				{DescOfGoods: "Laptops", HSCode: "8471.30", Packages: "10", GrossWeight: bf_tx.Quantity{Value: "5000.25"}},
+				{DescOfGoods: "Monitors", HSCode: "852852", Packages: "8", GrossWeight: bf_tx.Quantity{Value: "3000"}},
+			}},
+			{Container: "MSKU1234565", Packages: "12", GrossWeight: bf_tx.Quantity{Value: "7523.25", Unit: "KGM"}},
+		}
+		return bftx
+	}
+	if valid, msg := validator.ValidateFields(example()); !valid {
+		t.Errorf("Error on ValidateFields of containers: %s", msg)
+	}
+
+	invalid := map[string]func(*bf_tx.Properties){
+		"container number":             func(p *bf_tx.Properties) { p.Containers[1].Container = "MSKU1234566" },
+		"HS code":                      func(p *bf_tx.Properties) { p.Containers[0].Items[0].HSCode = "84" },
+		"container unit":               func(p *bf_tx.Properties) { p.Containers[1].GrossWeight.Unit = "LBR" },
+		"container total of its items": func(p *bf_tx.Properties) { p.Containers[0].Packages = "17" },
+		"Packages total":               func(p *bf_tx.Properties) { p.Packages = "31" },
+		"GrossWeight total":            func(p *bf_tx.Properties) { p.GrossWeight.Value = "15523.6" },
+		"Container":                    func(p *bf_tx.Properties) { p.Container = "TGHU8798450" },
+	}
+	for name, change := range invalid {
+		changed := example()
+		change(&changed.Properties)
+		if valid, _ := validator.ValidateFields(changed); valid {
+			t.Errorf("Error on ValidateFields, an invalid %s should not validate", name)
+		}
+	}
+
+	// The GrossWeight of the BF_TX is not added up when it is encrypted
+	encrypted := example()
+	encrypted.Properties.GrossWeight = bf_tx.ParseQuantity("c2FiZXIgY2lwaGVydGV4dA==")
+	if valid, msg := validator.ValidateFieldsExcept(encrypted, map[string]bool{"grossweight": true}); !valid {
+		t.Errorf("Error on ValidateFieldsExcept of an encrypted GrossWeight: %s", msg)
+	}
+}
+
+func TestValidateParties(t *testing.T) {
+	t.Log("Test on ValidateFields function with structured parties")
+	example := func() bf_tx.BF_TX {
+		bftx, err := bf_tx.SetBFTX("../../../examples/bf_tx_example.json")
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		bftx.Properties.Shipper = bf_tx.TradeParty{
+			Name:       "Shipper One Pte Ltd",
+			Address:    []string{"1 Harbourfront Avenue", "#02-01"},
+			City:       "Singapore",
+			PostalCode: "098632",
+			Country:    "SG",
+			Contact:    "Jo Tan",
+			Email:      "jo@shipper.example",
+			LEI:        "5493001KJTIIGC8Y1R12",
+			GLN:        "4006381333931",
+			EORI:       "DE123456789012345",
+			Party:      "VLX454323F",
+		}
+		bftx.Properties.Consignee = bf_tx.TradeParty{ToOrder: true}
+		bftx.Properties.NotifyAddress = bf_tx.TradeParty{Name: "Consignee One", Country: "NL"}
+		bftx.Properties.AgentForOwner.Details = &bf_tx.TradeParty{Name: "Agent One", TaxID: "NL001234567B01"}
+		return bftx
+	}
+	if valid, msg := validator.ValidateFields(example()); !valid {
+		t.Errorf("Error on ValidateFields of parties: %s", msg)
+	}
+
+	invalid := map[string]func(*bf_tx.Properties){
+		"country":                    func(p *bf_tx.Properties) { p.Shipper.Country = "XX" },
+		"LEI":                        func(p *bf_tx.Properties) { p.Shipper.LEI = "5493001KJTIIGC8Y1R13" },
+		"GLN":                        func(p *bf_tx.Properties) { p.Shipper.GLN = "4006381333932" },
+		"EORI":                       func(p *bf_tx.Properties) { p.Shipper.EORI = "123456" },
+		"email":                      func(p *bf_tx.Properties) { p.Shipper.Email = "jo" },
+		"party without a name":       func(p *bf_tx.Properties) { p.NotifyAddress.Name = "" },
+		"shipper to order":           func(p *bf_tx.Properties) { p.Shipper.ToOrder = true },
+		"to order without notify":    func(p *bf_tx.Properties) { p.NotifyAddress = bf_tx.TradeParty{} },
+		"details of a master":        func(p *bf_tx.Properties) { p.MasterInfo.Details = &bf_tx.TradeParty{Name: "Master", Country: "sg"} },
+		"party of the agent Details": func(p *bf_tx.Properties) { p.AgentForOwner.Details.Party = "agent1" },
+	}
+	for name, change := range invalid {
+		changed := example()
+		change(&changed.Properties)
+		if valid, _ := validator.ValidateFields(changed); valid {
+			t.Errorf("Error on ValidateFields, an invalid %s should not validate", name)
+		}
+	}
+
+	// An encrypted Shipper holds ciphertext instead of its fields
+	encrypted := example()
+	encrypted.Properties.Shipper.Country = "c2FiZXI="
+	if valid, msg := validator.ValidateFieldsExcept(encrypted, map[string]bool{"shipper": true}); !valid {
+		t.Errorf("Error on ValidateFieldsExcept of an encrypted Shipper: %s", msg)
+	}
+}
@@ -1,10 +1,13 @@
 package crypto
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"testing"
 
 	"github.com/blockfreight/go-bftx/lib/app/bf_tx"
 	"github.com/blockfreight/go-bftx/lib/pkg/crypto"
+	tmcrypto "github.com/tendermint/go-crypto"
 )
 
 func TestSignBFTX(t *testing.T) {
@@ -14,7 +17,7 @@ func TestSignBFTX(t *testing.T) {
 		t.Log(err.Error())
 	}
 
-	bftx, err = crypto.SignBFTX(bftx)
+	bftx, err = crypto.SignBFTX(bftx, tmcrypto.GenPrivKeyEd25519().Wrap())
 	if err != nil {
 		t.Log(err.Error())
 	}
@@ -22,7 +25,41 @@ func TestSignBFTX(t *testing.T) {
 	if bftx.Signature == "" {
 		t.Error("Error on bf_tx.Signature")
 	}
-	if bftx.Verified == false {
-		t.Error("Error on bf_tx.Verified")
+	if bf_tx.State(bftx) != bf_tx.StateSigned {
+		t.Error("Error on bf_tx.State")
+	}
+	if !crypto.VerifyBFTX(bftx) {
+		t.Error("Error on crypto.VerifyBFTX of the signed BF_TX")
+	}
+
+	bftx.Properties.Shipper = bf_tx.TradeParty{Name: "Someone else"}
+	if crypto.VerifyBFTX(bftx) {
+		t.Error("Error on crypto.VerifyBFTX of a BF_TX changed after signing")
+	}
+}
+
+func TestVerifyNonCanonicalBFTX(t *testing.T) {
+	t.Log("Test on VerifyBFTX function with a BF_TX signed over its field-ordered JSON instead of its canonical encoding")
+	bftx, err := bf_tx.SetBFTX("../../../examples/bf_tx_example.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	privKey := tmcrypto.GenPrivKeyEd25519().Wrap()
+	bftx.Id = "BFTXlegacy"
+	bftx.Schema = 0
+	bftx.Signer = privKey.PubKey().Bytes()
+
+	// Only the canonical encoding is signed, never the JSON encoding of the BF_TX in the order of its fields
+	content, err := bf_tx.BFTXContent(bftx)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	signhash := md5.Sum([]byte(content))
+	bftx.Signhash = signhash[:]
+	bftx.Signature = hex.EncodeToString(privKey.Sign([]byte(content)).Bytes())
+	bftx.Lifecycle = bf_tx.StateSigned
+
+	if crypto.VerifyBFTX(bftx) {
+		t.Error("Error on crypto.VerifyBFTX of a BF_TX signed over its field-ordered JSON")
 	}
 }